@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+var ErrRequestEventHistory = errors.New("Некорректный запрос истории события")
+
+func (h *Handler) handleEventHistoryError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenEventHistory):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenEventHistory.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundEvent):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
+	case errors.Is(errOutside, app.ErrValidationAuditLimit):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrValidationAuditLimit.Error()))
+	case errors.Is(errOutside, ErrRequestEventHistory), errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// GetEventHistory returns audit log of event, its participants and payments: ?limit=100.
+func (h *Handler) GetEventHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleEventHistoryError(ctx, w, err)
+		return
+	}
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleEventHistoryError(ctx, w, err)
+		return
+	}
+
+	limit := models.DefaultAuditHistoryLimit
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.handleEventHistoryError(ctx, w, fmt.Errorf("%w: %w", ErrRequestEventHistory, err))
+			return
+		}
+	}
+
+	history, err := h.app.GetEventHistory(ctx, userID, eventID, limit)
+	if err != nil {
+		h.handleEventHistoryError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, history)
+}
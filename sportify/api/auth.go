@@ -18,6 +18,7 @@ import (
 
 	"github.com/go-pkgz/auth/provider"
 	"github.com/go-pkgz/auth/token"
+	"github.com/google/uuid"
 )
 
 func (h *Handler) handleCheck(ctx context.Context, w http.ResponseWriter, errOutside error) {
@@ -60,6 +61,25 @@ func (h *Handler) Check(w http.ResponseWriter, r *http.Request) {
 	h.WriteCheckResponse(ctx, w, r, userInfo.Name)
 }
 
+var ErrUnauthorized = errors.New("Вы не авторизованы")
+
+// getUserIDFromToken returns our user id from claims, use it only behind auth middleware.
+func (h *Handler) getUserIDFromToken(r *http.Request) (uuid.UUID, error) {
+	userInfo, err := token.GetUserInfo(r)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+
+	rawUserID := strings.TrimPrefix(strings.TrimPrefix(userInfo.ID, "telegram_"), "my_")
+
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%w: to parse uuid from claims=%s: %w", ErrUnauthorized, rawUserID, err)
+	}
+
+	return userID, nil
+}
+
 func (h *Handler) handleRegister(ctx context.Context, w http.ResponseWriter, errOutside error) {
 	h.logger.WithCtx(ctx).Error(errOutside)
 
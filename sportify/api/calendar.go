@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleCalendarError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenCalendarToken):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenCalendarToken.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundEvent):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
+	case errors.Is(errOutside, db.ErrUserNotFound):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrUserNotFound.Error()))
+	case errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+func (h *Handler) eventPageURL(eventID uuid.UUID) string {
+	return "https://" + h.domain + "/events/" + eventID.String()
+}
+
+func writeCalendar(w http.ResponseWriter, fileName string, calendar []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(calendar)
+}
+
+// GetEventCalendar returns one event as .ics file for adding to calendar.
+func (h *Handler) GetEventCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	event, err := h.app.GetEvent(ctx, eventID)
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	calendarEvent := models.NewCalendarEvent(&event.ShortEvent, event.Description, h.eventPageURL(event.ID))
+
+	writeCalendar(w, event.ID.String()+".ics",
+		models.FormatCalendar(calendarEvent.Summary, []models.CalendarEvent{calendarEvent}, time.Now()))
+}
+
+// GetUserCalendar is personal feed for subscription from calendar apps, they can't log in,
+// so feed is protected by secret token in url.
+func (h *Handler) GetUserCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	events, err := h.app.GetCalendarFeed(ctx, userID, r.URL.Query().Get("token"))
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	calendarEvents := make([]models.CalendarEvent, 0, len(events))
+	for i := range events {
+		calendarEvents = append(calendarEvents, models.NewCalendarEvent(&events[i], nil, h.eventPageURL(events[i].ID)))
+	}
+
+	writeCalendar(w, "calendar.ics", models.FormatCalendar("Мои игры", calendarEvents, time.Now()))
+}
+
+func (h *Handler) calendarFeedURL(userID uuid.UUID, token string) (string, error) {
+	feedURL, err := url.JoinPath("https://"+h.domain, h.apiPrefix, "users", userID.String(), "calendar.ics")
+	if err != nil {
+		return "", fmt.Errorf("to join calendar url: %w", err)
+	}
+
+	return feedURL + "?" + url.Values{"token": []string{token}}.Encode(), nil
+}
+
+func (h *Handler) writeCalendarFeed(ctx context.Context, w http.ResponseWriter, userID uuid.UUID, token string) {
+	feedURL, err := h.calendarFeedURL(userID, token)
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.ResponseCalendarFeed{URL: feedURL})
+}
+
+// GetCalendarFeed returns link of personal calendar feed of current user.
+func (h *Handler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	token, err := h.app.GetCalendarToken(ctx, userID)
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	h.writeCalendarFeed(ctx, w, userID, token)
+}
+
+// ResetCalendarFeed makes new link of calendar feed, old one stops working.
+func (h *Handler) ResetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	token, err := h.app.ResetCalendarToken(ctx, userID)
+	if err != nil {
+		h.handleCalendarError(ctx, w, err)
+		return
+	}
+
+	h.writeCalendarFeed(ctx, w, userID, token)
+}
@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/app/imageproc"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+)
+
+var ErrRequestClub = errors.New("Некорректный запрос клуба")
+
+func (h *Handler) handleClubError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, ErrRequestClub):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, ErrParseFileBody):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, ErrToBigFile):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", ErrToBigFile.Error()))
+	case errors.Is(errOutside, app.ErrWrongFormat):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrWrongFormat.Error()))
+	case errors.Is(errOutside, imageproc.ErrTooLarge):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", imageproc.ErrTooLarge.Error()))
+	case errors.Is(errOutside, app.ErrValidationRequestClub):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrClubTgChatNotVerified):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrClubTgChatAlreadyLinked):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, db.ErrClubMemberExist):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", db.ErrClubMemberExist.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenNotClubAdmin):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenNotClubAdmin.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenLastClubAdmin):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenLastClubAdmin.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundClub):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundClub.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundClubMember):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundClubMember.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+func (h *Handler) CreateClub(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	var requestClubCreate models.RequestClubCreate
+
+	err = json.Unmarshal(body, &requestClubCreate)
+	if err != nil {
+		h.handleClubError(ctx, w, fmt.Errorf("%w: %s", ErrRequestClub, err.Error()))
+		return
+	}
+
+	club, err := h.app.CreateClub(ctx, userID, &requestClubCreate)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, club)
+}
+
+func (h *Handler) EditClub(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	var requestClubEdit models.RequestClubEdit
+
+	err = json.Unmarshal(body, &requestClubEdit)
+	if err != nil {
+		h.handleClubError(ctx, w, fmt.Errorf("%w: %s", ErrRequestClub, err.Error()))
+		return
+	}
+
+	club, err := h.app.EditClub(ctx, userID, clubID, &requestClubEdit)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, club)
+}
+
+// UploadClubAvatar accepts base64 image same as UploadFile and sets it as club avatar.
+func (h *Handler) UploadClubAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	rawBody, err := readPhotoBody(w, r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	url, err := h.app.SaveClubAvatar(ctx, userID, clubID, rawBody)
+	if err != nil {
+		h.handleClubError(ctx, w, fmt.Errorf("to save club avatar: %w", err))
+		return
+	}
+
+	models.WriteJSONResponse(w, models.ResponseClubAvatar{URL: url})
+}
+
+func (h *Handler) FindClubs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubs, err := h.app.FindClubs(ctx, strings.TrimSpace(r.URL.Query().Get("q")))
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	for i := range clubs {
+		clubs[i].AvatarURL = common.Ref(clubs[i].GetAvatarURL(h.urlPrefixFile))
+	}
+
+	models.WriteJSONResponse(w, clubs)
+}
+
+func (h *Handler) GetClubPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	club, err := h.app.GetClub(ctx, clubID)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	members, err := h.app.GetClubMembers(ctx, clubID)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	membersAPI := make([]models.ClubMemberAPI, 0, len(members))
+
+	for _, member := range members {
+		user, err := h.app.GetUserFullByUserID(ctx, member.UserID)
+		if err != nil {
+			if errors.Is(err, db.ErrUserNotFound) {
+				continue
+			}
+
+			h.handleClubError(ctx, w, err)
+			return
+		}
+
+		membersAPI = append(membersAPI, models.ClubMemberAPI{
+			UserShortcutAPI: models.UserShortcutAPI{
+				ID:       user.ID,
+				Username: user.Username,
+				PhotoURL: user.GetPhotoURL(h.urlPrefixFile),
+				TgURL:    models.MapTgURL(user.TgID, user.Username),
+			},
+			Role: member.Role,
+		})
+	}
+
+	events, err := h.app.FindClubUpcomingEvents(ctx, clubID)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	club.AvatarURL = common.Ref(club.GetAvatarURL(h.urlPrefixFile))
+
+	models.WriteJSONResponse(w, models.ClubPageAPI{
+		Club:           *club,
+		Members:        membersAPI,
+		UpcomingEvents: events,
+	})
+}
+
+func (h *Handler) JoinClub(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	err = h.app.JoinClub(ctx, userID, clubID)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
+
+// CreateClubTgLinkCode returns command which club admin posts to telegram chat to link it to club.
+func (h *Handler) CreateClubTgLinkCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	response, err := h.app.CreateClubTgLinkCode(ctx, userID, clubID)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, response)
+}
+
+func (h *Handler) SetClubMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	memberID, err := api.GetUUID(r, "user_id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	var requestRole models.RequestClubMemberRole
+
+	err = json.Unmarshal(body, &requestRole)
+	if err != nil {
+		h.handleClubError(ctx, w, fmt.Errorf("%w: %s", ErrRequestClub, err.Error()))
+		return
+	}
+
+	err = h.app.SetClubMemberRole(ctx, userID, clubID, memberID, &requestRole)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
+
+func (h *Handler) RemoveClubMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	memberID, err := api.GetUUID(r, "user_id")
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	err = h.app.RemoveClubMember(ctx, userID, clubID, memberID)
+	if err != nil {
+		h.handleClubError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+var ErrRequestComment = errors.New("Некорректный запрос комментария")
+
+func (h *Handler) handleCommentError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenEditNotYourComment):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenEditNotYourComment.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenDeleteNotYourComment):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenDeleteNotYourComment.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenCommentSettings):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenCommentSettings.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundEvent):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundComment):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundComment.Error()))
+	case errors.Is(errOutside, app.ErrCommentParentNotFound):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrCommentParentNotFound.Error()))
+	case errors.Is(errOutside, ErrRequestComment), errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// mapCommentsToAPI adds authors to comments, every author is got once.
+func (h *Handler) mapCommentsToAPI(ctx context.Context, comments []models.Comment) ([]models.CommentAPI, error) {
+	authors := make(map[uuid.UUID]models.UserShortcutAPI)
+	result := make([]models.CommentAPI, 0, len(comments))
+
+	for _, comment := range comments {
+		author, ok := authors[comment.AuthorID]
+		if !ok {
+			user, err := h.app.GetUserFullByUserID(ctx, comment.AuthorID)
+			if err != nil {
+				return nil, err
+			}
+
+			author = models.UserShortcutAPI{
+				ID:       user.ID,
+				Username: user.Username,
+				PhotoURL: user.GetPhotoURL(h.urlPrefixFile),
+				TgURL:    models.MapTgURL(user.TgID, user.Username),
+			}
+			authors[comment.AuthorID] = author
+		}
+
+		result = append(result, models.CommentAPI{Comment: comment, Author: author})
+	}
+
+	return result, nil
+}
+
+func (h *Handler) GetEventComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	comments, err := h.app.GetEventComments(ctx, eventID)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	commentsAPI, err := h.mapCommentsToAPI(ctx, comments)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, commentsAPI)
+}
+
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	var requestCommentCreate models.RequestCommentCreate
+
+	err = json.Unmarshal(reqBody, &requestCommentCreate)
+	if err != nil {
+		h.handleCommentError(ctx, w, fmt.Errorf("%w: %s", ErrRequestComment, err.Error()))
+		return
+	}
+
+	err = requestCommentCreate.Valid()
+	if err != nil {
+		h.handleCommentError(ctx, w, fmt.Errorf("%w: %s", ErrRequestComment, err.Error()))
+		return
+	}
+
+	comment, err := h.app.CreateComment(ctx, userID, eventID, &requestCommentCreate)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, comment)
+}
+
+func (h *Handler) EditComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	commentID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	var requestCommentEdit models.RequestCommentEdit
+
+	err = json.Unmarshal(reqBody, &requestCommentEdit)
+	if err != nil {
+		h.handleCommentError(ctx, w, fmt.Errorf("%w: %s", ErrRequestComment, err.Error()))
+		return
+	}
+
+	err = requestCommentEdit.Valid()
+	if err != nil {
+		h.handleCommentError(ctx, w, fmt.Errorf("%w: %s", ErrRequestComment, err.Error()))
+		return
+	}
+
+	comment, err := h.app.EditComment(ctx, userID, commentID, &requestCommentEdit)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, comment)
+}
+
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	commentID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	err = h.app.DeleteComment(ctx, userID, commentID)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
+
+// SetCommentSettings sets by organizer if comments of event are sent to telegram.
+func (h *Handler) SetCommentSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	var requestCommentSettings models.RequestCommentSettings
+
+	err = json.Unmarshal(reqBody, &requestCommentSettings)
+	if err != nil {
+		h.handleCommentError(ctx, w, fmt.Errorf("%w: %s", ErrRequestComment, err.Error()))
+		return
+	}
+
+	err = h.app.SetCommentSettings(ctx, userID, eventID, &requestCommentSettings)
+	if err != nil {
+		h.handleCommentError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
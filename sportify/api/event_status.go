@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+var ErrRequestEventStatus = errors.New("Некорректный запрос на смену статуса события")
+
+func (h *Handler) handleEventStatusError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenStatusNotYourEvent):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenStatusNotYourEvent.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundEvent):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
+	case errors.Is(errOutside, app.ErrInvalidStatusChange):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrInvalidStatusChange.Error()))
+	case errors.Is(errOutside, ErrRequestEventStatus), errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// ChangeEventStatus cancels, postpones or finishes event: {"status": "cancelled", "reason": "дождь"}.
+func (h *Handler) ChangeEventStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleEventStatusError(ctx, w, err)
+		return
+	}
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleEventStatusError(ctx, w, err)
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleEventStatusError(ctx, w, err)
+		return
+	}
+
+	var requestEventStatus models.RequestEventStatus
+
+	err = json.Unmarshal(reqBody, &requestEventStatus)
+	if err != nil {
+		h.handleEventStatusError(ctx, w, fmt.Errorf("%w: %s", ErrRequestEventStatus, err.Error()))
+		return
+	}
+
+	err = requestEventStatus.Valid()
+	if err != nil {
+		h.handleEventStatusError(ctx, w, fmt.Errorf("%w: %s", ErrRequestEventStatus, err.Error()))
+		return
+	}
+
+	fullEvent, err := h.app.ChangeEventStatus(ctx, userID, eventID, &requestEventStatus)
+	if err != nil {
+		h.handleEventStatusError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, fullEvent)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+// eventStreamPing keeps connection alive behind proxies, which close idle connections.
+const eventStreamPing = 25 * time.Second
+
+func (h *Handler) handleEventStreamError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, db.ErrNotFoundEvent):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
+	case errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// StreamEvent sends changes of event as server-sent events until client goes away or event is deleted.
+func (h *Handler) StreamEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleEventStreamError(ctx, w, err)
+		return
+	}
+
+	changes, unsubscribe, err := h.app.SubscribeEventChanges(ctx, eventID)
+	if err != nil {
+		h.handleEventStreamError(ctx, w, err)
+		return
+	}
+	defer unsubscribe()
+
+	controller := http.NewResponseController(w)
+
+	// write timeout of server is for usual requests, stream lives as long as page is open
+	err = controller.SetWriteDeadline(time.Time{})
+	if err != nil {
+		h.logger.WithCtx(ctx).Warnw("Unable to reset write deadline of stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = controller.Flush()
+	if err != nil {
+		h.logger.WithCtx(ctx).Error(fmt.Errorf("to flush stream: %w", err))
+		return
+	}
+
+	ticker := time.NewTicker(eventStreamPing)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.streamsCtx.Done():
+			return
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": ping\n\n")
+		case message, ok := <-changes:
+			if !ok {
+				return
+			}
+
+			var data []byte
+
+			data, err = json.Marshal(message)
+			if err != nil {
+				h.logger.WithCtx(ctx).Error(fmt.Errorf("to marshal event change: %w", err))
+				return
+			}
+
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Kind, data)
+			if err == nil && message.Kind == models.EventStreamDeleted {
+				_ = controller.Flush()
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+
+		err = controller.Flush()
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// FindEventFacets returns counts of events by sport type, level, price and date
+// for the same filters as FindEvents.
+func (h *Handler) FindEventFacets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filterParams, err := models.ParseFilterParams(r.URL.Query())
+	if err != nil {
+		h.handleFindEvents(ctx, w, fmt.Errorf("%w: %w", ErrRequestFilterParams, err))
+		return
+	}
+
+	err = h.applyExcludeJoined(r, filterParams)
+	if err != nil {
+		h.handleFindEvents(ctx, w, err)
+		return
+	}
+
+	now := models.MoscowNow()
+	filterParams.DateExpression = squirrel.GtOrEq{"start_time": now}
+
+	facets, err := h.app.FindEventFacets(ctx, filterParams)
+	if err != nil {
+		h.handleFindEvents(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, facets)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// feedMaxAge is how long feed is kept by us, aggregators and proxies, feeds are polled often
+	// and every new feed is search in database.
+	feedMaxAge = 5 * time.Minute
+	// feedCacheMaxEntries bounds memory of cache, feeds above it are built on every request.
+	feedCacheMaxEntries = 1000
+)
+
+type feedCacheEntry struct {
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+// feedCache keeps rendered feeds by format and normalized query, so polling of the same feed
+// and answers 304 don't go to database.
+type feedCache struct {
+	mu      sync.Mutex
+	entries map[string]feedCacheEntry
+}
+
+func newFeedCache() *feedCache {
+	return &feedCache{
+		mu:      sync.Mutex{},
+		entries: make(map[string]feedCacheEntry),
+	}
+}
+
+func (c *feedCache) get(key string, now time.Time) (feedCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return feedCacheEntry{}, false //nolint:exhaustruct
+	}
+
+	return entry, true
+}
+
+func (c *feedCache) set(key string, entry feedCacheEntry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= feedCacheMaxEntries {
+		for k, v := range c.entries {
+			if !now.Before(v.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	if len(c.entries) < feedCacheMaxEntries {
+		c.entries[key] = entry
+	}
+}
+
+var ErrRequestFeedFormat = errors.New("Формат ленты должен быть rss, atom или json")
+
+var feedContentTypes = map[string]string{ //nolint:gochecknoglobals
+	models.FeedFormatRSS:  "application/rss+xml; charset=utf-8",
+	models.FeedFormatAtom: "application/atom+xml; charset=utf-8",
+	models.FeedFormatJSON: "application/feed+json; charset=utf-8",
+}
+
+func formatFeed(format string, feed *models.Feed) ([]byte, error) {
+	switch format {
+	case models.FeedFormatRSS:
+		return models.FormatRSS(feed)
+	case models.FeedFormatAtom:
+		return models.FormatAtom(feed)
+	case models.FeedFormatJSON:
+		return models.FormatJSONFeed(feed)
+	default:
+		return nil, ErrRequestFeedFormat
+	}
+}
+
+func (h *Handler) handleFindEventsFeed(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrRequestFeedFormat), errors.Is(errOutside, ErrRequestFilterParams):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		h.handleFindEvents(ctx, w, errOutside)
+	}
+}
+
+// FindEventsFeed is search of upcoming events as RSS, Atom or JSON Feed, it takes the same filters as FindEvents.
+func (h *Handler) FindEventsFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format := chi.URLParam(r, "format")
+	if _, ok := feedContentTypes[format]; !ok {
+		h.handleFindEventsFeed(ctx, w, ErrRequestFeedFormat)
+		return
+	}
+
+	// Encode sorts parameters, so the same filters in other order are the same feed
+	normalizedURI := r.URL.Path
+	if query := r.URL.Query().Encode(); query != "" {
+		normalizedURI += "?" + query
+	}
+
+	if entry, ok := h.feedCache.get(normalizedURI, time.Now()); ok {
+		writeFeed(w, r, format, entry)
+		return
+	}
+
+	filterParams, err := models.ParseFilterParams(r.URL.Query())
+	if err != nil {
+		h.handleFindEventsFeed(ctx, w, fmt.Errorf("%w: %w", ErrRequestFilterParams, err))
+		return
+	}
+
+	now := models.MoscowNow()
+	filterParams.DateExpression = squirrel.GtOrEq{"start_time": now}
+	filterParams.WithTotal = false
+
+	if filterParams.Limit == 0 {
+		filterParams.Limit = models.DefaultEventsLimit
+	}
+
+	page, err := h.app.FindEventsPage(ctx, filterParams)
+	if err != nil {
+		h.handleFindEventsFeed(ctx, w, err)
+		return
+	}
+
+	feed := &models.Feed{
+		Title:       "Ближайшие игры",
+		Description: "Спортивные события по выбранным фильтрам",
+		Link:        "https://" + h.domain + "/events",
+		FeedURL:     "https://" + h.domain + normalizedURI,
+		Updated:     time.Now(),
+		Items:       make([]models.FeedItem, 0, len(page.Events)),
+	}
+
+	for i := range page.Events {
+		feed.Items = append(feed.Items, models.NewFeedItem(&page.Events[i], h.eventPageURL(page.Events[i].ID)))
+	}
+
+	body, err := formatFeed(format, feed)
+	if err != nil {
+		h.handleFindEventsFeed(ctx, w, err)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	entry := feedCacheEntry{
+		body:      body,
+		etag:      `"` + hex.EncodeToString(hash[:16]) + `"`,
+		expiresAt: feed.Updated.Add(feedMaxAge),
+	}
+
+	h.feedCache.set(normalizedURI, entry, feed.Updated)
+	writeFeed(w, r, format, entry)
+}
+
+// writeFeed answers 304 for known etag, clients keep feed only until our cache of it expires.
+func writeFeed(w http.ResponseWriter, r *http.Request, format string, entry feedCacheEntry) {
+	maxAge := max(int(time.Until(entry.expiresAt).Seconds()), 0)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Header().Set("ETag", entry.etag)
+
+	if r.Header.Get("If-None-Match") == entry.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", feedContentTypes[format])
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(entry.body)
+}
@@ -9,11 +9,12 @@ import (
 	"net/http"
 
 	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/app/imageproc"
 	"github.com/TheVovchenskiy/sportify-backend/models"
 )
 
 const (
-	MaxSizePhotoBytes = 50 * 1024 * 1024
+	MaxSizePhotoBytes = 10 * 1024 * 1024
 )
 
 func (h *Handler) handleUploadFileError(ctx context.Context, w http.ResponseWriter, errOutside error) {
@@ -26,6 +27,8 @@ func (h *Handler) handleUploadFileError(ctx context.Context, w http.ResponseWrit
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", ErrToBigFile.Error()))
 	case errors.Is(errOutside, app.ErrWrongFormat):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrWrongFormat.Error()))
+	case errors.Is(errOutside, imageproc.ErrTooLarge):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", imageproc.ErrTooLarge.Error()))
 	default:
 		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
 	}
@@ -36,34 +39,45 @@ var (
 	ErrToBigFile     = fmt.Errorf("слишком большой файл максимум %d", MaxSizePhotoBytes/1024/1024)
 )
 
-func (h *Handler) UploadFile(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// readPhotoBody reads base64 photo from body. Body is limited before reading,
+// so big upload is not read into memory whole.
+func readPhotoBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(base64.StdEncoding.EncodedLen(MaxSizePhotoBytes)))
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		h.handleUploadFileError(ctx, w, err)
-		return
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, ErrToBigFile
+		}
+
+		return nil, fmt.Errorf("to read body: %w", err)
 	}
 
-	lenBody := base64.StdEncoding.DecodedLen(len(body))
-	if lenBody > MaxSizePhotoBytes {
-		h.handleUploadFileError(ctx, w, ErrToBigFile)
-		return
+	rawBody := make([]byte, base64.StdEncoding.DecodedLen(len(body)))
+
+	n, err := base64.StdEncoding.Decode(rawBody, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrParseFileBody, err.Error())
 	}
 
-	rawBody := make([]byte, lenBody)
+	return rawBody[:n], nil
+}
+
+func (h *Handler) UploadFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	_, err = base64.StdEncoding.Decode(rawBody, body)
+	rawBody, err := readPhotoBody(w, r)
 	if err != nil {
-		h.handleUploadFileError(ctx, w, fmt.Errorf("%w: %s", ErrParseFileBody, err.Error()))
+		h.handleUploadFileError(ctx, w, err)
 		return
 	}
 
-	url, err := h.app.SaveImage(ctx, rawBody)
+	variants, err := h.app.SaveImage(ctx, rawBody)
 	if err != nil {
 		h.handleUploadFileError(ctx, w, fmt.Errorf("to save file: %w", err))
 		return
 	}
 
-	models.WriteJSONResponse(w, models.ResponseUploadFile{URL: url})
+	models.WriteJSONResponse(w, models.ResponseUploadFile{URL: variants.Full, Variants: *variants})
 }
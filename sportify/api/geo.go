@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+var ErrRequestCoordinates = errors.New("Некорректные координаты")
+
+func (h *Handler) handleGeoError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrRequestCoordinates):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrAddressNotFound):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", app.ErrAddressNotFound.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+func parseCoordinate(r *http.Request, name string, limit float64) (float64, error) {
+	value, err := strconv.ParseFloat(r.URL.Query().Get(name), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %s", ErrRequestCoordinates, name, err.Error())
+	}
+
+	if value < -limit || value > limit {
+		return 0, fmt.Errorf("%w: %s вне диапазона", ErrRequestCoordinates, name)
+	}
+
+	return value, nil
+}
+
+// ReverseGeocode turns pin dropped on map into address.
+func (h *Handler) ReverseGeocode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	latitude, err := parseCoordinate(r, "lat", 90) //nolint:mnd
+	if err != nil {
+		h.handleGeoError(ctx, w, err)
+		return
+	}
+
+	longitude, err := parseCoordinate(r, "lon", 180) //nolint:mnd
+	if err != nil {
+		h.handleGeoError(ctx, w, err)
+		return
+	}
+
+	response, err := h.app.ReverseGeocode(ctx, latitude, longitude)
+	if err != nil {
+		h.handleGeoError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, response)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,10 +28,11 @@ import (
 
 type App interface {
 	CreateEventSite(ctx context.Context, request *models.RequestEventCreateSite) (*models.FullEvent, error)
-	CreateEventTg(ctx context.Context, fullEvent *models.FullEvent) (*models.FullEvent, error)
+	CreateEventTg(ctx context.Context, fullEvent *models.FullEvent, tgChatID int64) (*models.FullEvent, error)
 	EditEventSite(ctx context.Context, request *models.RequestEventEditSite) (*models.FullEvent, error)
 	DeleteEvent(ctx context.Context, userID uuid.UUID, eventID uuid.UUID) error
-	FindEvents(ctx context.Context, filterParams *models.FilterParams) ([]models.ShortEvent, error)
+	HardDeleteEvent(ctx context.Context, userID, eventID uuid.UUID) error
+	FindEventsPage(ctx context.Context, filterParams *models.FilterParams) (*models.EventsPage, error)
 	GetEvent(ctx context.Context, id uuid.UUID) (*models.FullEvent, error)
 	SubscribeEventFromTg(ctx context.Context, tgChatID, tgMessageID, tgUserID int64) (*models.ResponseSubscribeEvent, error)
 	SubscribeEvent(
@@ -42,7 +44,7 @@ type App interface {
 	) (*models.ResponseSubscribeEvent, error)
 	UserIsSubscribed(ctx context.Context, eventID uuid.UUID, reqParams *models.RequestUserIsSubscribedParams) (bool, error)
 	DetectEventMessage(text string, regexps []string, minMatches int) (bool, error)
-	SaveImage(ctx context.Context, file []byte) (string, error)
+	SaveImage(ctx context.Context, file []byte) (*models.ImageVariants, error)
 	PayEvent(ctx context.Context, request *models.RequestEventPay) (*models.ResponseEventPay, error)
 	GetPayment(ctx context.Context, id uuid.UUID) (*models.ResponsesPayment, error)
 
@@ -58,6 +60,90 @@ type App interface {
 
 	GetUserFullByUserID(ctx context.Context, userID uuid.UUID) (*models.UserFull, error)
 	UpdateProfile(ctx context.Context, userID uuid.UUID, reqUpdate models.RequestUpdateProfile) error
+
+	// Club block
+
+	CreateClub(ctx context.Context, userID uuid.UUID, request *models.RequestClubCreate) (*models.Club, error)
+	EditClub(ctx context.Context, userID, clubID uuid.UUID, request *models.RequestClubEdit) (*models.Club, error)
+	SaveClubAvatar(ctx context.Context, userID, clubID uuid.UUID, file []byte) (string, error)
+	GetClub(ctx context.Context, clubID uuid.UUID) (*models.Club, error)
+	GetClubMembers(ctx context.Context, clubID uuid.UUID) ([]models.ClubMember, error)
+	FindClubs(ctx context.Context, query string) ([]models.Club, error)
+	FindClubUpcomingEvents(ctx context.Context, clubID uuid.UUID) ([]models.ShortEvent, error)
+	JoinClub(ctx context.Context, userID, clubID uuid.UUID) error
+	RemoveClubMember(ctx context.Context, userID, clubID, memberID uuid.UUID) error
+	SetClubMemberRole(ctx context.Context, userID, clubID, memberID uuid.UUID, request *models.RequestClubMemberRole) error
+	CreateClubTgLinkCode(ctx context.Context, userID, clubID uuid.UUID) (*models.ResponseClubTgLinkCode, error)
+	TryLinkClubTgChat(ctx context.Context, message string, tgChatID int64) (bool, error)
+
+	// Venue block
+
+	CreateVenue(ctx context.Context, userID uuid.UUID, request *models.RequestVenueCreate) (*models.Venue, error)
+	EditVenue(ctx context.Context, userID, venueID uuid.UUID, request *models.RequestVenueEdit) (*models.Venue, error)
+	GetVenue(ctx context.Context, venueID uuid.UUID) (*models.Venue, error)
+	SuggestVenues(ctx context.Context, query string, sportType *models.SportType) ([]models.Venue, error)
+	FindVenueUpcomingEvents(ctx context.Context, venueID uuid.UUID) ([]models.ShortEvent, error)
+
+	// Geo block
+
+	ReverseGeocode(ctx context.Context, latitude, longitude float64) (*models.ResponseReverseGeocode, error)
+	FindMapEvents(ctx context.Context, filterParams *models.FilterParams, zoom int) (*models.MapEvents, error)
+	FindEventFacets(ctx context.Context, filterParams *models.FilterParams) (*models.EventFacets, error)
+	CreateSavedSearch(
+		ctx context.Context,
+		userID uuid.UUID,
+		request *models.RequestSavedSearchCreate,
+	) (*models.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error
+	GetSavedSearchAlerts(ctx context.Context, userID uuid.UUID) ([]models.SavedSearchAlert, error)
+	RecommendEvents(
+		ctx context.Context,
+		userID uuid.UUID,
+		latitude, longitude *float64,
+		limit int,
+	) ([]models.RecommendedEvent, error)
+	GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) (
+		*models.ResponseNotifications, error,
+	)
+	MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+	SetNotificationPreferences(
+		ctx context.Context,
+		userID uuid.UUID,
+		preferences []models.NotificationPreference,
+	) ([]models.NotificationPreference, error)
+	SetEventReminder(ctx context.Context, userID, eventID uuid.UUID, request *models.RequestReminder) error
+	GetUserReminder(ctx context.Context, userID uuid.UUID) (*models.RequestReminder, error)
+	SetUserReminder(ctx context.Context, userID uuid.UUID, request *models.RequestReminder) error
+	ChangeEventStatus(
+		ctx context.Context,
+		userID, eventID uuid.UUID,
+		request *models.RequestEventStatus,
+	) (*models.FullEvent, error)
+	GetEventHistory(ctx context.Context, userID, eventID uuid.UUID, limit int) ([]models.AuditRecord, error)
+	GetEventComments(ctx context.Context, eventID uuid.UUID) ([]models.Comment, error)
+	GetCalendarToken(ctx context.Context, userID uuid.UUID) (string, error)
+	ResetCalendarToken(ctx context.Context, userID uuid.UUID) (string, error)
+	GetCalendarFeed(ctx context.Context, userID uuid.UUID, token string) ([]models.ShortEvent, error)
+	GetEventShareCard(ctx context.Context, event *models.ShortEvent) (string, error)
+	SubscribeEventChanges(ctx context.Context, eventID uuid.UUID) (<-chan models.EventStreamMessage, func(), error)
+	CreateComment(
+		ctx context.Context,
+		userID, eventID uuid.UUID,
+		request *models.RequestCommentCreate,
+	) (*models.Comment, error)
+	EditComment(
+		ctx context.Context,
+		userID, commentID uuid.UUID,
+		request *models.RequestCommentEdit,
+	) (*models.Comment, error)
+	DeleteComment(ctx context.Context, userID, commentID uuid.UUID) error
+	SetCommentSettings(
+		ctx context.Context,
+		userID, eventID uuid.UUID,
+		request *models.RequestCommentSettings,
+	) error
 }
 
 var _ App = (*app.App)(nil)
@@ -72,7 +158,11 @@ type Handler struct {
 	logger        *mylogger.MyLogger
 	telegram      *telegramapi.TelegramAPIDummy
 	tokenService  *token.Service
-	app           App
+	feedCache     *feedCache
+	// streamsCtx is canceled on shutdown of server, streams don't wait for clients to leave.
+	streamsCtx   context.Context //nolint:containedctx
+	closeStreams context.CancelFunc
+	app          App
 }
 
 func NewHandler(
@@ -81,6 +171,8 @@ func NewHandler(
 	folderID, IAMToken, domain, port, apiPrefix, urlPrefixFile string,
 	telegram *telegramapi.TelegramAPIDummy,
 ) Handler {
+	streamsCtx, closeStreams := context.WithCancel(context.Background())
+
 	return Handler{
 		app:           app,
 		logger:        logger,
@@ -91,9 +183,17 @@ func NewHandler(
 		apiPrefix:     apiPrefix,
 		urlPrefixFile: urlPrefixFile,
 		telegram:      telegram,
+		feedCache:     newFeedCache(),
+		streamsCtx:    streamsCtx,
+		closeStreams:  closeStreams,
 	}
 }
 
+// CloseStreams ends all event streams, it is called on shutdown of server.
+func (h *Handler) CloseStreams() {
+	h.closeStreams()
+}
+
 // Update need for ClaimsUpdater change userID to our
 func (h *Handler) Update(claims token.Claims) token.Claims {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -122,6 +222,10 @@ func (h *Handler) handleCreateEventSiteError(ctx context.Context, w http.Respons
 	switch {
 	case errors.Is(errOutside, ErrRequestEventCreateSite):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenNotClubAdmin):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenNotClubAdmin.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundVenue):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", db.ErrNotFoundVenue.Error()))
 	default:
 		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
 	}
@@ -203,15 +307,15 @@ func (h *Handler) GetUsersEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filterParams.CreatorID = common.Ref(userID)
+	filterParams.WithCancelled = true
 
-	events, err := h.app.FindEvents(ctx, filterParams)
-	h.logger.WithCtx(ctx).Info("Got events", events)
+	page, err := h.app.FindEventsPage(ctx, filterParams)
 	if err != nil {
 		h.handleGetEventsError(ctx, w, err)
 		return
 	}
 
-	models.WriteJSONResponse(w, events)
+	writeEventsPage(w, page)
 }
 
 func (h *Handler) handleGetUsersSubActiveEvents(ctx context.Context, w http.ResponseWriter, errOutside error) {
@@ -244,19 +348,16 @@ func (h *Handler) GetUsersSubActiveEvents(w http.ResponseWriter, r *http.Request
 	}
 
 	filterParams.SubscriberIDs = []uuid.UUID{userID}
-	// Это жесткий костыль, как привратить time.Now() из московского пояса в utc, но лучше я не придумал
-	// time.Local = time.UTC не работает должным образом
-	now := time.Now().Add(time.Hour * 3)
+	now := models.MoscowNow()
 	filterParams.DateExpression = squirrel.GtOrEq{"start_time": now.Add(-1 * time.Hour * 24)}
 
-	events, err := h.app.FindEvents(ctx, filterParams)
-	h.logger.WithCtx(ctx).Info("Got events", events)
+	page, err := h.app.FindEventsPage(ctx, filterParams)
 	if err != nil {
 		h.handleGetEventsError(ctx, w, err)
 		return
 	}
 
-	models.WriteJSONResponse(w, events)
+	writeEventsPage(w, page)
 }
 
 func (h *Handler) handleGetUsersSubArchiveEvents(ctx context.Context, w http.ResponseWriter, errOutside error) {
@@ -289,19 +390,21 @@ func (h *Handler) GetUsersSubArchiveEvents(w http.ResponseWriter, r *http.Reques
 	}
 
 	filterParams.SubscriberIDs = []uuid.UUID{userID}
-	// Это жесткий костыль, как привратить time.Now() из московского пояса в utc, но лучше я не придумал
-	// time.Local = time.UTC не работает должным образом
-	now := time.Now().Add(time.Hour * 3)
-	filterParams.DateExpression = squirrel.LtOrEq{"start_time": now.Add(-1 * time.Hour * 24)}
+	now := models.MoscowNow()
+	// cancelled events go to archive at once
+	filterParams.WithCancelled = true
+	filterParams.DateExpression = squirrel.Or{
+		squirrel.LtOrEq{"start_time": now.Add(-1 * time.Hour * 24)},
+		squirrel.Eq{"status": models.EventStatusCancelled},
+	}
 
-	events, err := h.app.FindEvents(ctx, filterParams)
-	h.logger.WithCtx(ctx).Info("Got events", events)
+	page, err := h.app.FindEventsPage(ctx, filterParams)
 	if err != nil {
 		h.handleGetEventsError(ctx, w, err)
 		return
 	}
 
-	models.WriteJSONResponse(w, events)
+	writeEventsPage(w, page)
 }
 
 func (h *Handler) handleEditEventSiteError(ctx context.Context, w http.ResponseWriter, errOutside error) {
@@ -314,8 +417,12 @@ func (h *Handler) handleEditEventSiteError(ctx context.Context, w http.ResponseW
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
 	case errors.Is(errOutside, app.ErrForbiddenEditNotYourEvent):
 		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenEditNotYourEvent.Error()))
+	case errors.Is(errOutside, app.ErrEditCancelledEvent):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrEditCancelledEvent.Error()))
 	case errors.Is(errOutside, ErrRequestEditEventSite):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundVenue):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", db.ErrNotFoundVenue.Error()))
 	default:
 		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
 	}
@@ -365,6 +472,10 @@ func (h *Handler) handleDeleteEvent(ctx context.Context, w http.ResponseWriter,
 	switch {
 	case errors.Is(errOutside, app.ErrForbiddenDeleteNotYourEvent):
 		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenDeleteNotYourEvent.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenHardDeleteNotModerator):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenHardDeleteNotModerator.Error()))
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
 	case errors.Is(errOutside, db.ErrNotFoundEvent):
 		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
 	case errors.Is(errOutside, api.ErrInvalidUUID):
@@ -407,9 +518,54 @@ func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	models.WriteJSONResponse(w, models.NewResponseEventDelete())
 }
 
+// HardDeleteEvent is admin removal of event forever, unlike DeleteEvent of organizer.
+func (h *Handler) HardDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleDeleteEvent(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleDeleteEvent(ctx, w, err)
+		return
+	}
+
+	err = h.app.HardDeleteEvent(ctx, userID, eventID)
+	if err != nil {
+		h.handleDeleteEvent(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseEventDelete())
+}
+
 func (h *Handler) handleGetEventsError(ctx context.Context, w http.ResponseWriter, errOutside error) {
 	h.logger.WithCtx(ctx).Error(errOutside)
-	models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+
+	switch {
+	case errors.Is(errOutside, app.ErrDistanceWithoutPoint):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrDistanceWithoutPoint.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// writeEventsPage keeps body as plain array of events for old clients,
+// page info is passed in headers. List is cut into pages only when client passes limit or cursor.
+func writeEventsPage(w http.ResponseWriter, page *models.EventsPage) {
+	if page.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
+
+	if page.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(*page.Total))
+	}
+
+	models.WriteJSONResponse(w, page.Events)
 }
 
 func (h *Handler) handleFindEvents(ctx context.Context, w http.ResponseWriter, errOutside error) {
@@ -418,11 +574,34 @@ func (h *Handler) handleFindEvents(ctx context.Context, w http.ResponseWriter, e
 	switch {
 	case errors.Is(errOutside, ErrRequestFilterParams):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrValidationMapRequest):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrValidationMapRequest.Error()))
+	case errors.Is(errOutside, app.ErrDistanceWithoutPoint):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrDistanceWithoutPoint.Error()))
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
 	default:
 		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
 	}
 }
 
+// applyExcludeJoined hides events the user has joined, events are public,
+// so user is known only if request has token.
+func (h *Handler) applyExcludeJoined(r *http.Request, filterParams *models.FilterParams) error {
+	if !filterParams.ExcludeJoined {
+		return nil
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		return err
+	}
+
+	filterParams.ExcludeSubscriberID = &userID
+
+	return nil
+}
+
 func (h *Handler) FindEvents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -434,18 +613,22 @@ func (h *Handler) FindEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Это жесткий костыль, как привратить time.Now() из московского пояса в utc, но лучше я не придумал
-	// time.Local = time.UTC не работает должным образом
-	now := time.Now().Add(time.Hour * 3)
+	err = h.applyExcludeJoined(r, filterParams)
+	if err != nil {
+		h.handleFindEvents(ctx, w, err)
+		return
+	}
+
+	now := models.MoscowNow()
 	filterParams.DateExpression = squirrel.GtOrEq{"start_time": now}
 
-	events, err := h.app.FindEvents(ctx, filterParams)
+	page, err := h.app.FindEventsPage(ctx, filterParams)
 	if err != nil {
 		h.handleFindEvents(ctx, w, err)
 		return
 	}
 
-	models.WriteJSONResponse(w, events)
+	writeEventsPage(w, page)
 }
 
 var ErrInvalidEventID = errors.New("Неверный event id")
@@ -527,6 +710,8 @@ func (h *Handler) handleSubscribeEventFromTgError(ctx context.Context, w http.Re
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", models.ErrAllBusy.Error()))
 	case errors.Is(errOutside, models.ErrFoundSubscriber):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", models.ErrFoundSubscriber.Error()))
+	case errors.Is(errOutside, db.ErrEventNotActive):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", db.ErrEventNotActive.Error()))
 	case errors.Is(errOutside, models.ErrNotFoundSubscriber):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", models.ErrNotFoundSubscriber.Error()))
 	default:
@@ -577,6 +762,8 @@ func (h *Handler) handleSubscribeEventError(ctx context.Context, w http.Response
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", models.ErrAllBusy.Error()))
 	case errors.Is(errOutside, models.ErrFoundSubscriber):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", models.ErrFoundSubscriber.Error()))
+	case errors.Is(errOutside, db.ErrEventNotActive):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", db.ErrEventNotActive.Error()))
 	case errors.Is(errOutside, models.ErrNotFoundSubscriber):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", models.ErrNotFoundSubscriber.Error()))
 	default:
@@ -816,6 +1003,17 @@ func (h *Handler) TryCreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// result of link command is posted to chat by app, bot only needs the request to be handled
+	linked, err := h.app.TryLinkClubTgChat(ctx, tgMessage.RawMessage, tgMessage.Chat.ID)
+	if linked {
+		if err != nil {
+			h.logger.WithCtx(ctx).Warnf("to link club tg chat: %+v", err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	// TODO add detecting same message for example by RawMessage
 
 	if ok, err := h.app.DetectEventMessage(tgMessage.RawMessage, app.SportEventRegExps, 3); !ok || err != nil {
@@ -837,7 +1035,7 @@ func (h *Handler) TryCreateEvent(w http.ResponseWriter, r *http.Request) {
 	fullEvent.RawMessage = common.Ref(tgMessage.RawMessage)
 	fullEvent.Description = common.Ref(tgMessage.RawMessage)
 
-	resultFullEvent, err := h.app.CreateEventTg(ctx, fullEvent)
+	resultFullEvent, err := h.app.CreateEventTg(ctx, fullEvent, tgMessage.Chat.ID)
 	if err != nil {
 		h.handleGetEventError(ctx, w, err)
 		return
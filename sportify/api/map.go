@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// FindMapEvents returns events or clusters of events in visible area of map,
+// all filters of FindEvents work here too.
+func (h *Handler) FindMapEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q := r.URL.Query()
+
+	filterParams, err := models.ParseFilterParams(q)
+	if err != nil {
+		h.handleFindEvents(ctx, w, fmt.Errorf("%w: %w", ErrRequestFilterParams, err))
+		return
+	}
+
+	err = h.applyExcludeJoined(r, filterParams)
+	if err != nil {
+		h.handleFindEvents(ctx, w, err)
+		return
+	}
+
+	zoom, err := strconv.Atoi(q.Get("zoom"))
+	if err != nil {
+		h.handleFindEvents(ctx, w, fmt.Errorf("%w: %w", app.ErrValidationMapRequest, err))
+		return
+	}
+
+	now := models.MoscowNow()
+	filterParams.DateExpression = squirrel.GtOrEq{"start_time": now}
+
+	mapEvents, err := h.app.FindMapEvents(ctx, filterParams, zoom)
+	if err != nil {
+		h.handleFindEvents(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, mapEvents)
+}
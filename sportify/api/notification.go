@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+var ErrRequestNotifications = errors.New("Некорректный запрос уведомлений")
+
+func (h *Handler) handleNotificationError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, ErrRequestNotifications):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrValidationNotifications):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrValidationNotifications.Error()))
+	case errors.Is(errOutside, app.ErrValidationNotificationPreference):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// GetNotifications returns the latest notifications and count of unread: ?unread_only=true&limit=50.
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	q := r.URL.Query()
+	limit := models.DefaultNotificationsLimit
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.handleNotificationError(ctx, w, fmt.Errorf("%w: %w", ErrRequestNotifications, err))
+			return
+		}
+	}
+
+	notifications, err := h.app.GetNotifications(ctx, userID, q.Get("unread_only") == "true", limit)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, notifications)
+}
+
+func (h *Handler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	var requestNotificationsRead models.RequestNotificationsRead
+
+	// empty body marks all notifications
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &requestNotificationsRead)
+		if err != nil {
+			h.handleNotificationError(ctx, w, fmt.Errorf("%w: %s", ErrRequestNotifications, err.Error()))
+			return
+		}
+	}
+
+	err = h.app.MarkNotificationsRead(ctx, userID, requestNotificationsRead.IDs)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
+
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	preferences, err := h.app.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, preferences)
+}
+
+func (h *Handler) SetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	var preferences []models.NotificationPreference
+
+	err = json.Unmarshal(body, &preferences)
+	if err != nil {
+		h.handleNotificationError(ctx, w, fmt.Errorf("%w: %s", ErrRequestNotifications, err.Error()))
+		return
+	}
+
+	result, err := h.app.SetNotificationPreferences(ctx, userID, preferences)
+	if err != nil {
+		h.handleNotificationError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, result)
+}
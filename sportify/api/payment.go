@@ -22,6 +22,8 @@ func (h *Handler) handlePayEventError(ctx context.Context, w http.ResponseWriter
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
 	case errors.Is(errOutside, app.ErrPayFree):
 		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrPayFree.Error()))
+	case errors.Is(errOutside, app.ErrPaymentsDisabled):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrPaymentsDisabled.Error()))
 	case errors.Is(errOutside, db.ErrNotFoundEvent):
 		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
 	default:
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+var ErrRequestRecommendations = errors.New("Некорректный запрос рекомендаций")
+
+func (h *Handler) handleRecommendEventsError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, ErrRequestRecommendations):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrValidationRecommendations):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrValidationRecommendations.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+// RecommendEvents returns upcoming events ranked for current user, every event has reasons
+// of recommendation: ?lat=55.75&lon=37.62&limit=20, position is optional.
+func (h *Handler) RecommendEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleRecommendEventsError(ctx, w, err)
+		return
+	}
+
+	q := r.URL.Query()
+
+	// lat and lon are validated the same way as in events search
+	filterParams, err := models.ParseFilterParams(q)
+	if err != nil {
+		h.handleRecommendEventsError(ctx, w, fmt.Errorf("%w: %w", ErrRequestRecommendations, err))
+		return
+	}
+
+	limit := app.DefaultRecommendationsLimit
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.handleRecommendEventsError(ctx, w, fmt.Errorf("%w: %w", ErrRequestRecommendations, err))
+			return
+		}
+	}
+
+	events, err := h.app.RecommendEvents(ctx, userID, filterParams.Latitude, filterParams.Longitude, limit)
+	if err != nil {
+		h.handleRecommendEventsError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, events)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+var ErrRequestReminder = errors.New("Некорректный запрос напоминания")
+
+func (h *Handler) handleReminderError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenReminderNotYourEvent):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenReminderNotYourEvent.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundEvent):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundEvent.Error()))
+	case errors.Is(errOutside, ErrRequestReminder), errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+func readRequestReminder(r *http.Request) (*models.RequestReminder, error) {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestReminder models.RequestReminder
+
+	err = json.Unmarshal(reqBody, &requestReminder)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRequestReminder, err.Error())
+	}
+
+	err = requestReminder.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRequestReminder, err.Error())
+	}
+
+	return &requestReminder, nil
+}
+
+// SetEventReminder sets for how many hours before start participants of event are reminded.
+func (h *Handler) SetEventReminder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	requestReminder, err := readRequestReminder(r)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	err = h.app.SetEventReminder(ctx, userID, eventID, requestReminder)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
+
+func (h *Handler) GetUserReminder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	reminder, err := h.app.GetUserReminder(ctx, userID)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, reminder)
+}
+
+// SetUserReminder sets reminder of user for all events, it is stronger than setting of event.
+func (h *Handler) SetUserReminder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	requestReminder, err := readRequestReminder(r)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	err = h.app.SetUserReminder(ctx, userID, requestReminder)
+	if err != nil {
+		h.handleReminderError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+var ErrRequestSavedSearch = errors.New("Некорректный запрос сохраненного поиска")
+
+func (h *Handler) handleSavedSearchError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, ErrRequestSavedSearch):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrValidationSavedSearch):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrTooManySavedSearches):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrTooManySavedSearches.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundSavedSearch):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundSavedSearch.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+func (h *Handler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	var requestSavedSearchCreate models.RequestSavedSearchCreate
+
+	err = json.Unmarshal(body, &requestSavedSearchCreate)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, fmt.Errorf("%w: %s", ErrRequestSavedSearch, err.Error()))
+		return
+	}
+
+	savedSearch, err := h.app.CreateSavedSearch(ctx, userID, &requestSavedSearchCreate)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, savedSearch)
+}
+
+func (h *Handler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	savedSearches, err := h.app.GetSavedSearches(ctx, userID)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, savedSearches)
+}
+
+func (h *Handler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	savedSearchID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	err = h.app.DeleteSavedSearch(ctx, userID, savedSearchID)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.NewResponseOK())
+}
+
+// GetSavedSearchAlerts is feed of new events found by saved searches of user.
+func (h *Handler) GetSavedSearchAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	alerts, err := h.app.GetSavedSearchAlerts(ctx, userID)
+	if err != nil {
+		h.handleSavedSearchError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, alerts)
+}
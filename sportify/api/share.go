@@ -0,0 +1,128 @@
+package api
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/sharecard"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+// sharePageMaxAge is short, so changes of event reach previews soon.
+const sharePageMaxAge = 5 * time.Minute
+
+// shareTemplate is page for crawlers of messengers, they don't run SPA, people are redirected to it.
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta name="description" content="{{.Description}}">
+<meta property="og:type" content="website">
+<meta property="og:site_name" content="{{.SiteName}}">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:image" content="{{.Image}}">
+{{- if .ImageWidth}}
+<meta property="og:image:width" content="{{.ImageWidth}}">
+<meta property="og:image:height" content="{{.ImageHeight}}">
+{{- end}}
+<meta property="og:locale" content="ru_RU">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:title" content="{{.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+<meta name="twitter:image" content="{{.Image}}">
+<link rel="canonical" href="{{.URL}}">
+<meta http-equiv="refresh" content="0; url={{.URL}}">
+</head>
+<body>
+<a href="{{.URL}}">{{.Title}}</a>
+</body>
+</html>
+`)) //nolint:gochecknoglobals
+
+type sharePage struct {
+	SiteName    string
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	ImageWidth  int
+	ImageHeight int
+}
+
+// ShareEvent renders Open Graph preview of event page.
+func (h *Handler) ShareEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleGetEventError(ctx, w, err)
+		return
+	}
+
+	event, err := h.app.GetEvent(ctx, eventID)
+	if err != nil {
+		h.handleGetEventError(ctx, w, err)
+		return
+	}
+
+	share := models.NewEventShare(&event.ShortEvent)
+	page := sharePage{
+		SiteName:    h.domain,
+		Title:       share.Title + " " + share.DateTime,
+		Description: share.Description(),
+		URL:         h.eventPageURL(event.ID),
+		Image:       event.URLPreview,
+		ImageWidth:  sharecard.Width,
+		ImageHeight: sharecard.Height,
+	}
+
+	cardURL, err := h.app.GetEventShareCard(ctx, &event.ShortEvent)
+	if err != nil {
+		// preview with photo of event is better than error for crawler
+		h.logger.WithCtx(ctx).Warnw("Unable to get share card", "event_id", eventID, "error", err)
+
+		page.ImageWidth, page.ImageHeight = 0, 0
+	} else {
+		page.Image = cardURL
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(sharePageMaxAge.Seconds())))
+	w.WriteHeader(http.StatusOK)
+
+	err = shareTemplate.Execute(w, page)
+	if err != nil {
+		h.logger.WithCtx(ctx).Error(err)
+	}
+}
+
+// GetEventShareCard redirects to share card of event, so its link stays the same when event is changed.
+func (h *Handler) GetEventShareCard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleGetEventError(ctx, w, err)
+		return
+	}
+
+	event, err := h.app.GetEvent(ctx, eventID)
+	if err != nil {
+		h.handleGetEventError(ctx, w, err)
+		return
+	}
+
+	cardURL, err := h.app.GetEventShareCard(ctx, &event.ShortEvent)
+	if err != nil {
+		h.handleGetEventError(ctx, w, err)
+		return
+	}
+
+	http.Redirect(w, r, cardURL, http.StatusFound)
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/app"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/api"
+)
+
+var ErrRequestVenue = errors.New("Некорректный запрос площадки")
+
+func (h *Handler) handleVenueError(ctx context.Context, w http.ResponseWriter, errOutside error) {
+	h.logger.WithCtx(ctx).Error(errOutside)
+
+	switch {
+	case errors.Is(errOutside, ErrUnauthorized):
+		models.WriteResponseError(w, models.NewResponseUnauthorizedErr("", ErrUnauthorized.Error()))
+	case errors.Is(errOutside, api.ErrInvalidUUID):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, ErrRequestVenue):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrValidationRequestVenue):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", errOutside.Error()))
+	case errors.Is(errOutside, app.ErrVenueCoordinatesNotFound):
+		models.WriteResponseError(w, models.NewResponseBadRequestErr("", app.ErrVenueCoordinatesNotFound.Error()))
+	case errors.Is(errOutside, app.ErrForbiddenEditNotYourVenue):
+		models.WriteResponseError(w, models.NewResponseForbiddenErr("", app.ErrForbiddenEditNotYourVenue.Error()))
+	case errors.Is(errOutside, db.ErrNotFoundVenue):
+		models.WriteResponseError(w, models.NewResponseNotFoundErr("", db.ErrNotFoundVenue.Error()))
+	default:
+		models.WriteResponseError(w, models.NewResponseInternalServerErr("", models.InternalServerErrMessage))
+	}
+}
+
+func (h *Handler) CreateVenue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	var requestVenueCreate models.RequestVenueCreate
+
+	err = json.Unmarshal(body, &requestVenueCreate)
+	if err != nil {
+		h.handleVenueError(ctx, w, fmt.Errorf("%w: %s", ErrRequestVenue, err.Error()))
+		return
+	}
+
+	venue, err := h.app.CreateVenue(ctx, userID, &requestVenueCreate)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, venue)
+}
+
+func (h *Handler) EditVenue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	venueID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromToken(r)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	var requestVenueEdit models.RequestVenueEdit
+
+	err = json.Unmarshal(body, &requestVenueEdit)
+	if err != nil {
+		h.handleVenueError(ctx, w, fmt.Errorf("%w: %s", ErrRequestVenue, err.Error()))
+		return
+	}
+
+	venue, err := h.app.EditVenue(ctx, userID, venueID, &requestVenueEdit)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, venue)
+}
+
+// SuggestVenues is autocomplete for venue field of event form: ?q=part of name or address&sport_type=football.
+func (h *Handler) SuggestVenues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var sportType *models.SportType
+
+	if rawSportType := r.URL.Query().Get("sport_type"); rawSportType != "" {
+		if _, ok := models.EnToRuSportType(models.SportType(rawSportType)); !ok {
+			h.handleVenueError(ctx, w, fmt.Errorf("%w: неизвестный вид спорта %q", ErrRequestVenue, rawSportType))
+			return
+		}
+
+		sportType = (*models.SportType)(&rawSportType)
+	}
+
+	venues, err := h.app.SuggestVenues(ctx, strings.TrimSpace(r.URL.Query().Get("q")), sportType)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, venues)
+}
+
+func (h *Handler) GetVenuePage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	venueID, err := api.GetUUID(r, "id")
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	venue, err := h.app.GetVenue(ctx, venueID)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	events, err := h.app.FindVenueUpcomingEvents(ctx, venueID)
+	if err != nil {
+		h.handleVenueError(ctx, w, err)
+		return
+	}
+
+	models.WriteJSONResponse(w, models.VenuePageAPI{
+		Venue:          *venue,
+		UpcomingEvents: events,
+	})
+}
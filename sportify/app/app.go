@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/TheVovchenskiy/sportify-backend/app/botapi"
+	"github.com/TheVovchenskiy/sportify-backend/app/geocoder"
 	"github.com/TheVovchenskiy/sportify-backend/app/yookassa"
 	"github.com/TheVovchenskiy/sportify-backend/db"
 	"github.com/TheVovchenskiy/sportify-backend/models"
@@ -24,6 +24,7 @@ type EventStorage interface {
 	CreateEvent(ctx context.Context, event *models.FullEvent) error
 	EditEvent(ctx context.Context, event *models.FullEvent) error
 	DeleteEvent(ctx context.Context, userID, eventID uuid.UUID) error
+	HardDeleteEvent(ctx context.Context, eventID uuid.UUID) error
 	GetCreatorID(ctx context.Context, eventID uuid.UUID) (uuid.UUID, error)
 	FindEvents(ctx context.Context, filterParams *models.FilterParams) ([]models.ShortEvent, error)
 	GetEvent(ctx context.Context, id uuid.UUID) (*models.FullEvent, error)
@@ -31,12 +32,16 @@ type EventStorage interface {
 	SubscribeEvent(ctx context.Context, id uuid.UUID, userID uuid.UUID, subscribe bool) (*models.ResponseSubscribeEvent, error)
 	AddUserPaid(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	SetCoordinates(ctx context.Context, latitude, longitude string, id uuid.UUID) error
+	SetEventStatus(
+		ctx context.Context, eventID uuid.UUID, oldStatus, status models.EventStatus, cancelReason *string,
+	) error
 }
 
 var _ EventStorage = (*db.PostgresStorage)(nil)
 
 type FileStorage interface {
 	SaveFile(ctx context.Context, file []byte, fileName string) error
+	ReadFile(ctx context.Context, fileName string) ([]byte, error)
 	Check(ctx context.Context, files []string) ([]bool, error)
 }
 
@@ -46,12 +51,16 @@ type BotAPI interface {
 	EventCreated(ctx context.Context, eventCreateRequest models.EventCreatedBotRequest) (*models.EventCreatedBotResponse, error)
 	EventUpdated(ctx context.Context, eventUpdateRequest models.EventUpdatedBotRequest) error
 	EventDeleted(ctx context.Context, eventDeleteRequest models.EventDeletedBotRequest) error
+	SendMessage(ctx context.Context, messageRequest models.MessageBotRequest) error
+	SendChatMessage(ctx context.Context, messageRequest models.ChatMessageBotRequest) error
+	CommentCreated(ctx context.Context, commentRequest models.CommentBotRequest) error
 }
 
 var _ BotAPI = (*botapi.BotAPI)(nil)
 
 type YookassaClient interface {
 	DoPayment(ctx context.Context, idempotencyKey, redirectURL string, amount float64) (*models.Payment, error)
+	DoRefund(ctx context.Context, idempotencyKey string, paymentID uuid.UUID, amount float64) (bool, error)
 }
 
 var _ YookassaClient = (*yookassa.Client)(nil)
@@ -60,6 +69,8 @@ type PaymentPayoutStorage interface {
 	CreatePayment(ctx context.Context, payment *models.Payment) error
 	GetPayment(ctx context.Context, id uuid.UUID) (*models.Payment, error)
 	UpdateStatusPayment(ctx context.Context, id uuid.UUID, status models.PaymentStatus) error
+	RefundEventPayments(ctx context.Context, eventID uuid.UUID) ([]models.Payment, error)
+	GetPaymentsByStatus(ctx context.Context, status models.PaymentStatus) ([]models.Payment, error)
 }
 
 var _ PaymentPayoutStorage = (*db.PostgresPaymentPayoutStorage)(nil)
@@ -73,47 +84,107 @@ var _ TokenStorage = (*db.MapTokenStorage)(nil)
 
 //go:generate mockgen -source=app.go -destination=mocks/app.go -package=mocks EventStorage
 
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*geocoder.Coordinates, error)
+	Reverse(ctx context.Context, latitude, longitude float64) (*models.AddressDetails, error)
+}
+
+var _ Geocoder = (*geocoder.Cached)(nil)
+
 type App struct {
-	yandexAPIKey         string
-	urlPrefixFile        string
-	fileStorage          FileStorage
-	eventStorage         EventStorage
-	paymentPayoutStorage PaymentPayoutStorage
-	authStorage          AuthStorage
-	tokenStorage         TokenStorage
-	yookassaClient       YookassaClient
-	httpClient           *http.Client
-	logger               *mylogger.MyLogger
-	muFindByAddress      *sync.Mutex
-	botAPI               BotAPI
-	queueCoordinates     *queueCoordinates
+	urlPrefixFile         string
+	fileStorage           FileStorage
+	eventStorage          EventStorage
+	paymentPayoutStorage  PaymentPayoutStorage
+	authStorage           AuthStorage
+	clubStorage           ClubStorage
+	venueStorage          VenueStorage
+	coordinatesJobStorage CoordinatesJobStorage
+	mapStorage            MapStorage
+	facetStorage          FacetStorage
+	savedSearchStorage    SavedSearchStorage
+	recommendationStorage RecommendationStorage
+	notificationStorage   NotificationStorage
+	reminderStorage       ReminderStorage
+	auditStorage          AuditStorage
+	commentStorage        CommentStorage
+	eventStreamStorage    EventStreamStorage
+	calendarStorage       CalendarStorage
+	tokenStorage          TokenStorage
+	yookassaClient        YookassaClient
+	geocoder              Geocoder
+	geocodeTimeout        time.Duration
+	recommendationWeights models.RecommendationWeights
+	auditSettings         models.AuditSettings
+	logger                *mylogger.MyLogger
+	botAPI                BotAPI
+	wakeUpCoordinates     chan struct{}
+	eventHub              *eventHub
 }
 
-func NewApp(
-	yandexAPIKey, urlPrefixFile string,
-	fileStorage FileStorage,
-	eventStorage EventStorage,
-	authStorage AuthStorage,
-	tokenStorage TokenStorage,
-	logger *mylogger.MyLogger,
-	botAPI BotAPI,
-	// paymentPayoutStorage PaymentPayoutStorage,
-	// yookassaClient YookassaClient,
-) *App {
+// Deps are dependencies of App. Storages are named, so two storages of same type can't be swapped silently.
+type Deps struct {
+	URLPrefixFile         string
+	FileStorage           FileStorage
+	EventStorage          EventStorage
+	AuthStorage           AuthStorage
+	ClubStorage           ClubStorage
+	VenueStorage          VenueStorage
+	CoordinatesJobStorage CoordinatesJobStorage
+	MapStorage            MapStorage
+	FacetStorage          FacetStorage
+	SavedSearchStorage    SavedSearchStorage
+	RecommendationStorage RecommendationStorage
+	NotificationStorage   NotificationStorage
+	ReminderStorage       ReminderStorage
+	AuditStorage          AuditStorage
+	CommentStorage        CommentStorage
+	EventStreamStorage    EventStreamStorage
+	CalendarStorage       CalendarStorage
+	TokenStorage          TokenStorage
+	Geocoder              Geocoder
+	GeocodeTimeout        time.Duration
+	RecommendationWeights models.RecommendationWeights
+	AuditSettings         models.AuditSettings
+	Logger                *mylogger.MyLogger
+	BotAPI                BotAPI
+	PaymentPayoutStorage  PaymentPayoutStorage
+	// YookassaClient is nil when shop is not configured, then refunds wait in refund_pending.
+	YookassaClient YookassaClient
+}
+
+func NewApp(deps Deps) *App {
+	logger := deps.Logger
+
 	app := &App{
-		yandexAPIKey:     yandexAPIKey,
-		urlPrefixFile:    urlPrefixFile,
-		eventStorage:     eventStorage,
-		fileStorage:      fileStorage,
-		authStorage:      authStorage,
-		httpClient:       http.DefaultClient,
-		tokenStorage:     tokenStorage,
-		logger:           logger,
-		muFindByAddress:  &sync.Mutex{},
-		botAPI:           botAPI,
-		queueCoordinates: &queueCoordinates{idsInQueue: make(map[uuid.UUID]struct{})},
-		// paymentPayoutStorage: paymentPayoutStorage,
-		// yookassaClient:       yookassaClient,
+		urlPrefixFile:         deps.URLPrefixFile,
+		eventStorage:          deps.EventStorage,
+		fileStorage:           deps.FileStorage,
+		authStorage:           deps.AuthStorage,
+		clubStorage:           deps.ClubStorage,
+		venueStorage:          deps.VenueStorage,
+		coordinatesJobStorage: deps.CoordinatesJobStorage,
+		mapStorage:            deps.MapStorage,
+		facetStorage:          deps.FacetStorage,
+		savedSearchStorage:    deps.SavedSearchStorage,
+		recommendationStorage: deps.RecommendationStorage,
+		notificationStorage:   deps.NotificationStorage,
+		reminderStorage:       deps.ReminderStorage,
+		auditStorage:          deps.AuditStorage,
+		commentStorage:        deps.CommentStorage,
+		eventStreamStorage:    deps.EventStreamStorage,
+		calendarStorage:       deps.CalendarStorage,
+		tokenStorage:          deps.TokenStorage,
+		geocoder:              deps.Geocoder,
+		geocodeTimeout:        deps.GeocodeTimeout,
+		recommendationWeights: deps.RecommendationWeights,
+		auditSettings:         deps.AuditSettings,
+		logger:                logger,
+		botAPI:                deps.BotAPI,
+		wakeUpCoordinates:     make(chan struct{}, 1),
+		eventHub:              newEventHub(),
+		paymentPayoutStorage:  deps.PaymentPayoutStorage,
+		yookassaClient:        deps.YookassaClient,
 	}
 
 	// TODO add context to cancel
@@ -126,18 +197,43 @@ func NewApp(
 		app.RefreshCoordinates(context.TODO(), time.Second*60)
 	}()
 
-	return app
-}
+	go func() {
+		defer func() {
+			if pan := recover(); pan != nil {
+				logger.Errorf("panic: %v", pan)
+			}
+		}()
+		app.RemindEvents(context.TODO(), time.Minute)
+	}()
 
-type coordinates struct {
-	ID      uuid.UUID
-	Address string
-}
+	go func() {
+		defer func() {
+			if pan := recover(); pan != nil {
+				logger.Errorf("panic: %v", pan)
+			}
+		}()
+		app.CleanAuditLog(context.TODO(), time.Hour)
+	}()
 
-type queueCoordinates struct {
-	queue      []coordinates
-	idsInQueue map[uuid.UUID]struct{}
-	mu         sync.RWMutex
+	go func() {
+		defer func() {
+			if pan := recover(); pan != nil {
+				logger.Errorf("panic: %v", pan)
+			}
+		}()
+		app.ListenEventChanges(context.TODO())
+	}()
+
+	go func() {
+		defer func() {
+			if pan := recover(); pan != nil {
+				logger.Errorf("panic: %v", pan)
+			}
+		}()
+		app.RefundPendingPayments(context.TODO(), time.Minute*10)
+	}()
+
+	return app
 }
 
 var (
@@ -145,12 +241,14 @@ var (
 	urlPreviewDummy     = "default_football.jpeg"
 )
 
-func (a *App) CreateEventTg(ctx context.Context, fullEvent *models.FullEvent) (*models.FullEvent, error) {
+func (a *App) CreateEventTg(ctx context.Context, fullEvent *models.FullEvent, tgChatID int64) (*models.FullEvent, error) {
 	// TODO add in db persistent map uuid to id from tg user
 	fullEvent.CreatorID = creatorIDTgDummy
+	a.attributeEventToClub(ctx, fullEvent, tgChatID)
 	fullEvent.ID = uuid.New()
 	// TODO try get photos from tg message and default photo to different SportType
 	fullEvent.CreationType = models.CreationTypeTg
+	fullEvent.Status = models.EventStatusScheduled
 	fullEvent.IsFree = models.IsFreePrice(fullEvent.Price)
 	fullEvent.URLPreview = a.urlPrefixFile + urlPreviewDummy
 	fullEvent.URLPhotos = []string{a.urlPrefixFile + urlPreviewDummy}
@@ -160,7 +258,9 @@ func (a *App) CreateEventTg(ctx context.Context, fullEvent *models.FullEvent) (*
 		return nil, fmt.Errorf("to create event: %w", err)
 	}
 
-	a.addInQueueRefreshCoordinatesIfExpired(&fullEvent.ShortEvent)
+	a.auditEvent(ctx, models.AuditActionCreate, models.AuditSourceTg, nil, nil, fullEvent)
+	a.wakeUpRefreshCoordinates()
+	a.alertSavedSearches(ctx, fullEvent)
 
 	return fullEvent, nil
 }
@@ -232,7 +332,8 @@ func (a *App) onEventCreate(ctx context.Context, tgParams *models.TgParams, full
 	return *response.TgChatID, *response.TgMessageID, nil
 }
 
-func (a *App) onEventUpdate(ctx context.Context, eventID uuid.UUID) {
+// onEventUpdate re-renders telegram post of event, users of notice are told about update by bot.
+func (a *App) onEventUpdate(ctx context.Context, eventID uuid.UUID, notice *models.MessageBotRequest) {
 	fullEvent, err := a.GetEvent(ctx, eventID)
 	if err != nil {
 		a.logger.WithCtx(ctx).Warnw("Unable to get event", "event_id", fullEvent.ID, "error", err)
@@ -241,6 +342,14 @@ func (a *App) onEventUpdate(ctx context.Context, eventID uuid.UUID) {
 
 	if fullEvent.TgChatID == nil || fullEvent.TgMessageID == nil {
 		a.logger.WithCtx(ctx).Infow("Unable to update tg event, no info about chat or message", "event_id", fullEvent.ID)
+
+		if notice != nil && len(notice.TgUserIDs) > 0 {
+			err = a.botAPI.SendMessage(ctx, *notice)
+			if err != nil {
+				a.logger.WithCtx(ctx).Warnw("Unable to send message", "event_id", fullEvent.ID, "error", err)
+			}
+		}
+
 		return
 	}
 
@@ -256,6 +365,11 @@ func (a *App) onEventUpdate(ctx context.Context, eventID uuid.UUID) {
 		Event:       *botEvent,
 	}
 
+	if notice != nil && len(notice.TgUserIDs) > 0 {
+		eventUpdated.TgUserIDsToNotify = notice.TgUserIDs
+		eventUpdated.TextToNotify = notice.Text
+	}
+
 	err = a.botAPI.EventUpdated(ctx, eventUpdated)
 	if err != nil {
 		a.logger.WithCtx(ctx).Warnw("Unable to send event updated", "event_id", fullEvent.ID, "error", err)
@@ -270,6 +384,9 @@ func (a *App) onEventDelete(ctx context.Context, eventID uuid.UUID) {
 		return
 	}
 
+	a.notifyParticipants(ctx, models.NotificationEventDeleted, &fullEvent.ShortEvent, fullEvent.CreatorID,
+		fmt.Sprintf("Событие «%s» отменено", eventTitle(&fullEvent.ShortEvent)))
+
 	if fullEvent.TgChatID == nil || fullEvent.TgMessageID == nil {
 		a.logger.WithCtx(ctx).Infow("Unable to delete tg event, no info about chat or message", "event_id", fullEvent.ID)
 		return
@@ -316,8 +433,22 @@ func (a *App) getDefaultEventPhoto(sportType models.SportType) string {
 }
 
 func (a *App) CreateEventSite(ctx context.Context, request *models.RequestEventCreateSite) (*models.FullEvent, error) {
+	if request.CreateEvent.ClubID != nil {
+		err := a.checkClubAdmin(ctx, *request.CreateEvent.ClubID, request.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	result := models.NewFullEventSite(uuid.New(), request.UserID, &request.CreateEvent)
 
+	err := a.applyVenueToEvent(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	a.resolveEventAddress(ctx, result)
+
 	if result.URLPreview == "" || len(result.URLPhotos) == 0 {
 		defaultPhoto := a.getDefaultEventPhoto(result.SportType)
 		result.URLPreview = defaultPhoto
@@ -335,17 +466,22 @@ func (a *App) CreateEventSite(ctx context.Context, request *models.RequestEventC
 		result.TgMessageID = &messageID
 	}
 
-	err := a.eventStorage.CreateEvent(ctx, result)
+	err = a.eventStorage.CreateEvent(ctx, result)
 	if err != nil {
 		return nil, fmt.Errorf("to create event: %w", err)
 	}
 
-	a.addInQueueRefreshCoordinatesIfExpired(&result.ShortEvent)
+	a.auditEvent(ctx, models.AuditActionCreate, models.AuditSourceSite, &request.UserID, nil, result)
+	a.wakeUpRefreshCoordinates()
+	a.alertSavedSearches(ctx, result)
 
 	return result, nil
 }
 
-var ErrForbiddenEditNotYourEvent = errors.New("Вы не можете изменять не свое событие")
+var (
+	ErrForbiddenEditNotYourEvent = errors.New("Вы не можете изменять не свое событие")
+	ErrEditCancelledEvent        = errors.New("Отмененное событие нельзя изменить")
+)
 
 func (a *App) EditEventSite(ctx context.Context, request *models.RequestEventEditSite) (*models.FullEvent, error) {
 	eventFromDB, err := a.eventStorage.GetEvent(ctx, request.EventID)
@@ -357,6 +493,10 @@ func (a *App) EditEventSite(ctx context.Context, request *models.RequestEventEdi
 		return nil, ErrForbiddenEditNotYourEvent
 	}
 
+	if eventFromDB.Status == models.EventStatusCancelled {
+		return nil, ErrEditCancelledEvent
+	}
+
 	if len(request.EventEditSite.GameLevels) == 0 {
 		request.EventEditSite.GameLevels = eventFromDB.GameLevels
 	}
@@ -384,6 +524,8 @@ func (a *App) EditEventSite(ctx context.Context, request *models.RequestEventEdi
 		ShortEvent: models.ShortEvent{
 			ID:          request.EventID,
 			CreatorID:   eventFromDB.CreatorID,
+			ClubID:      eventFromDB.ClubID,
+			VenueID:     eventFromDB.VenueID,
 			SportType:   common.NewValWithFallback(request.EventEditSite.SportType, &eventFromDB.SportType),
 			Address:     common.NewValWithFallback(request.EventEditSite.Address, &eventFromDB.Address),
 			DateAndTime: common.NewValWithFallback(request.EventEditSite.DateAndTime, &eventFromDB.DateAndTime),
@@ -397,6 +539,27 @@ func (a *App) EditEventSite(ctx context.Context, request *models.RequestEventEdi
 		CreationType: eventFromDB.CreationType,
 	}
 
+	if request.EventEditSite.VenueID != nil {
+		preResult.VenueID = request.EventEditSite.VenueID
+
+		if request.EventEditSite.Address == nil {
+			preResult.Address = ""
+		}
+	}
+
+	err = a.applyVenueToEvent(ctx, preResult)
+	if err != nil {
+		return nil, err
+	}
+
+	if preResult.VenueID == nil && preResult.Address == eventFromDB.Address {
+		preResult.Latitude = eventFromDB.Latitude
+		preResult.Longitude = eventFromDB.Longitude
+		preResult.AddressDetails = eventFromDB.AddressDetails
+	} else {
+		a.resolveEventAddress(ctx, preResult)
+	}
+
 	err = a.eventStorage.EditEvent(ctx, preResult)
 	if err != nil {
 		return nil, fmt.Errorf("to edit event: %w", err)
@@ -408,8 +571,21 @@ func (a *App) EditEventSite(ctx context.Context, request *models.RequestEventEdi
 	preResult.URLAuthor = eventFromDB.URLAuthor
 	preResult.IsFree = eventFromDB.IsFree
 	preResult.RawMessage = eventFromDB.RawMessage
+	preResult.Status = eventFromDB.Status
+	preResult.CancelReason = eventFromDB.CancelReason
+
+	a.auditEvent(ctx, models.AuditActionUpdate, models.AuditSourceSite, &request.UserID, eventFromDB, preResult)
 
-	a.onEventUpdate(ctx, preResult.ID)
+	changes := models.DiffEvents(&eventFromDB.ShortEvent, &preResult.ShortEvent)
+	a.publishEventChange(ctx, preResult.ID, models.EventStreamUpdated, changes)
+
+	if len(changes) == 0 {
+		a.onEventUpdate(ctx, preResult.ID, nil)
+	} else {
+		a.notifyEventChanges(ctx, &preResult.ShortEvent, request.UserID,
+			models.FormatEventChanges(eventTitle(&eventFromDB.ShortEvent), changes))
+	}
+	a.wakeUpRefreshCoordinates()
 
 	return preResult, nil
 }
@@ -417,12 +593,12 @@ func (a *App) EditEventSite(ctx context.Context, request *models.RequestEventEdi
 var ErrForbiddenDeleteNotYourEvent = errors.New("Вы не можете удалять чужое событие")
 
 func (a *App) DeleteEvent(ctx context.Context, userID uuid.UUID, eventID uuid.UUID) error {
-	creatorID, err := a.eventStorage.GetCreatorID(ctx, eventID)
+	event, err := a.eventStorage.GetEvent(ctx, eventID)
 	if err != nil {
-		return fmt.Errorf("to get cretor id: %w", err)
+		return fmt.Errorf("to get event: %w", err)
 	}
 
-	if creatorID != userID {
+	if event.CreatorID != userID {
 		return ErrForbiddenDeleteNotYourEvent
 	}
 
@@ -433,49 +609,59 @@ func (a *App) DeleteEvent(ctx context.Context, userID uuid.UUID, eventID uuid.UU
 		return fmt.Errorf("to delete event: %w", err)
 	}
 
+	a.auditEvent(ctx, models.AuditActionDelete, models.AuditSourceSite, &userID, event, nil)
+	a.publishEventChange(ctx, eventID, models.EventStreamDeleted, nil)
+
 	return nil
 }
 
-func (a *App) FindEvents(ctx context.Context, filterParams *models.FilterParams) ([]models.ShortEvent, error) {
-	if filterParams.Address != "" {
-		func() {
-			a.muFindByAddress.Lock()
-			defer a.muFindByAddress.Unlock()
+var ErrForbiddenHardDeleteNotModerator = errors.New("Удалять события насовсем могут только модераторы")
 
-			latitude, longitude, err := a.getCoordinatesByAddress(ctx, filterParams.Address, UserAgentFind)
-			if err != nil {
-				a.logger.WithCtx(ctx).Errorf("to find address from=%s FindEvents: %v", filterParams.Address, err)
-			} else {
-				filterParams.AddressLatitude = &latitude
-				filterParams.AddressLongitude = &longitude
-			}
+// HardDeleteEvent removes event with its comments and reminders forever, also event deleted by organizer.
+// Only moderators from config can do it, audit log keeps last state of event.
+func (a *App) HardDeleteEvent(ctx context.Context, userID, eventID uuid.UUID) error {
+	if !a.isModerator(userID) {
+		return ErrForbiddenHardDeleteNotModerator
+	}
 
-			time.Sleep(time.Millisecond * 1100)
-		}()
+	event, err := a.eventStorage.GetEvent(ctx, eventID)
+	switch {
+	case err == nil:
+		a.onEventDelete(ctx, eventID)
+	case errors.Is(err, db.ErrNotFoundEvent):
+		// event is already deleted by organizer, row is still removed
+	default:
+		return fmt.Errorf("to get event: %w", err)
 	}
 
-	events, err := a.eventStorage.FindEvents(ctx, filterParams)
+	err = a.eventStorage.HardDeleteEvent(ctx, eventID)
 	if err != nil {
-		return nil, fmt.Errorf("to find events: %w", err)
+		return fmt.Errorf("to hard delete event: %w", err)
 	}
 
-	for _, event := range events {
-		a.addInQueueRefreshCoordinatesIfExpired(&event)
-	}
+	a.audit(ctx, &models.AuditRecord{ //nolint:exhaustruct
+		EntityType: models.AuditEntityEvent,
+		EntityID:   eventID,
+		EventID:    &eventID,
+		Action:     models.AuditActionDelete,
+		ActorID:    &userID,
+		Source:     models.AuditSourceAdmin,
+		Before:     auditJSON(event),
+	})
+	a.publishEventChange(ctx, eventID, models.EventStreamDeleted, nil)
 
-	return events, nil
+	return nil
 }
 
-func (a *App) addInQueueRefreshCoordinatesIfExpired(event *models.ShortEvent) {
-	if event.ExpirationTimeCoordinates.Before(time.Now()) {
-		func() {
-			a.queueCoordinates.mu.Lock()
-			defer a.queueCoordinates.mu.Unlock()
+func (a *App) FindEvents(ctx context.Context, filterParams *models.FilterParams) ([]models.ShortEvent, error) {
+	a.applyAddressSearch(ctx, filterParams)
 
-			a.queueCoordinates.queue = append(a.queueCoordinates.queue, coordinates{ID: event.ID, Address: event.Address})
-			a.queueCoordinates.idsInQueue[event.ID] = struct{}{}
-		}()
+	events, err := a.eventStorage.FindEvents(ctx, filterParams)
+	if err != nil {
+		return nil, fmt.Errorf("to find events: %w", err)
 	}
+
+	return events, nil
 }
 
 func (a *App) GetEvent(ctx context.Context, id uuid.UUID) (*models.FullEvent, error) {
@@ -484,8 +670,6 @@ func (a *App) GetEvent(ctx context.Context, id uuid.UUID) (*models.FullEvent, er
 		return nil, fmt.Errorf("to get event: %w", err)
 	}
 
-	a.addInQueueRefreshCoordinatesIfExpired(&event.ShortEvent)
-
 	return event, nil
 }
 
@@ -512,13 +696,20 @@ func (a *App) SubscribeEventFromTg(ctx context.Context, tgChatID, tgMessageID, t
 		return nil, fmt.Errorf("to subscribe event: %w", err)
 	}
 
-	a.onEventUpdate(ctx, responseSubscribeEvent.ID)
+	a.auditParticipant(ctx, models.AuditSourceTg, userFullFromTgID.ID, !userIsSubscribed, responseSubscribeEvent)
+	a.onEventUpdate(ctx, responseSubscribeEvent.ID, nil)
+	a.publishSubscribersChange(ctx, responseSubscribeEvent)
+	a.onSubscriptionChanged(ctx, userFullFromTgID.ID, !userIsSubscribed, responseSubscribeEvent)
 
 	return responseSubscribeEvent, nil
 }
 
 func (a *App) SubscribeEvent(ctx context.Context, id uuid.UUID, userID *uuid.UUID, tgID *int64, subscribe bool) (*models.ResponseSubscribeEvent, error) {
+	source := models.AuditSourceSite
+
 	if userID == nil && tgID != nil {
+		source = models.AuditSourceTg
+
 		userFullFromTgID, err := a.authStorage.GetUserFullByTgID(ctx, *tgID)
 		if err != nil {
 			return nil, fmt.Errorf("to get user full by tg id: %w", err)
@@ -531,7 +722,10 @@ func (a *App) SubscribeEvent(ctx context.Context, id uuid.UUID, userID *uuid.UUI
 		return nil, fmt.Errorf("to subscribe event: %w", err)
 	}
 
-	a.onEventUpdate(ctx, responseSubscribeEvent.ID)
+	a.auditParticipant(ctx, source, *userID, subscribe, responseSubscribeEvent)
+	a.onEventUpdate(ctx, responseSubscribeEvent.ID, nil)
+	a.publishSubscribersChange(ctx, responseSubscribeEvent)
+	a.onSubscriptionChanged(ctx, *userID, subscribe, responseSubscribeEvent)
 
 	return responseSubscribeEvent, nil
 }
@@ -559,9 +753,16 @@ func (a *App) UserIsSubscribed(ctx context.Context, eventID uuid.UUID, reqParams
 	return false, nil
 }
 
-var ErrPayFree = errors.New("Вы не можете оплатить бесплатное событие")
+var (
+	ErrPayFree          = errors.New("Вы не можете оплатить бесплатное событие")
+	ErrPaymentsDisabled = errors.New("Оплата сейчас недоступна")
+)
 
 func (a *App) PayEvent(ctx context.Context, request *models.RequestEventPay) (*models.ResponseEventPay, error) {
+	if a.yookassaClient == nil {
+		return nil, ErrPaymentsDisabled
+	}
+
 	fullEvent, err := a.GetEvent(ctx, request.EventID)
 	if err != nil {
 		return nil, fmt.Errorf("to get event: %w", err)
@@ -588,6 +789,8 @@ func (a *App) PayEvent(ctx context.Context, request *models.RequestEventPay) (*m
 		return nil, fmt.Errorf("to create payment: %w", err)
 	}
 
+	a.auditPayment(ctx, models.AuditActionCreate, models.AuditSourceSite, &request.UserID, payment)
+
 	return &models.ResponseEventPay{
 		ID:              payment.ID,
 		ConfirmationURL: payment.ConfirmationURL,
@@ -626,6 +829,11 @@ func (a *App) GetPayment(ctx context.Context, paymentID uuid.UUID) (*models.Resp
 			if err != nil {
 				fmt.Println("add user paid: ", err)
 			}
+
+			a.auditPayment(ctx, models.AuditActionStatus, models.AuditSourceSystem, nil, payment)
+
+			a.notify(ctx, models.NotificationPaymentStatus, []uuid.UUID{payment.UserID}, &payment.EventID,
+				"Оплата события прошла успешно")
 		}()
 		muPayment.Unlock()
 	}()
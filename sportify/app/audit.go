@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type AuditStorage interface {
+	AddAuditRecord(ctx context.Context, record *models.AuditRecord) error
+	GetEventHistory(ctx context.Context, eventID uuid.UUID, limit int) ([]models.AuditRecord, error)
+	DeleteAuditRecordsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var _ AuditStorage = (*db.PostgresStorage)(nil)
+
+var (
+	ErrForbiddenEventHistory = errors.New("Историю события видят только организатор и модераторы")
+	ErrValidationAuditLimit  = fmt.Errorf("limit истории должен быть от 1 до %d", models.MaxAuditHistoryLimit)
+)
+
+func auditJSON(value any) json.RawMessage {
+	result, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// audit writes record after change is done. Change is not rolled back
+// when record can't be written, so error is only logged.
+func (a *App) audit(ctx context.Context, record *models.AuditRecord) {
+	err := a.auditStorage.AddAuditRecord(ctx, record)
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to write audit record",
+			"entity_type", record.EntityType, "entity_id", record.EntityID, "action", record.Action, "error", err)
+	}
+}
+
+func (a *App) auditEvent(
+	ctx context.Context,
+	action models.AuditAction,
+	source models.AuditSource,
+	actorID *uuid.UUID,
+	before, after *models.FullEvent,
+) {
+	record := &models.AuditRecord{ //nolint:exhaustruct
+		Action:  action,
+		ActorID: actorID,
+		Source:  source,
+	}
+
+	if before != nil {
+		record.EntityID = before.ID
+		record.Before = auditJSON(before)
+	}
+
+	if after != nil {
+		record.EntityID = after.ID
+		record.After = auditJSON(after)
+	}
+
+	record.EntityType = models.AuditEntityEvent
+	record.EventID = &record.EntityID
+
+	a.audit(ctx, record)
+}
+
+func (a *App) auditParticipant(
+	ctx context.Context,
+	source models.AuditSource,
+	userID uuid.UUID,
+	subscribe bool,
+	response *models.ResponseSubscribeEvent,
+) {
+	action := models.AuditActionLeave
+	if subscribe {
+		action = models.AuditActionJoin
+	}
+
+	a.audit(ctx, &models.AuditRecord{ //nolint:exhaustruct
+		EntityType: models.AuditEntityParticipant,
+		EntityID:   userID,
+		EventID:    &response.ID,
+		Action:     action,
+		ActorID:    &userID,
+		Source:     source,
+		After:      auditJSON(response),
+	})
+}
+
+func (a *App) auditPayment(
+	ctx context.Context,
+	action models.AuditAction,
+	source models.AuditSource,
+	actorID *uuid.UUID,
+	payment *models.Payment,
+) {
+	a.audit(ctx, &models.AuditRecord{ //nolint:exhaustruct
+		EntityType: models.AuditEntityPayment,
+		EntityID:   payment.ID,
+		EventID:    &payment.EventID,
+		Action:     action,
+		ActorID:    actorID,
+		Source:     source,
+		After:      auditJSON(payment),
+	})
+}
+
+func (a *App) isModerator(userID uuid.UUID) bool {
+	for _, moderatorID := range a.auditSettings.ModeratorIDs {
+		if moderatorID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetEventHistory returns changes of event, organizer sees history of own event and moderators of any.
+func (a *App) GetEventHistory(ctx context.Context, userID, eventID uuid.UUID, limit int) (
+	[]models.AuditRecord, error,
+) {
+	if limit < 1 || limit > models.MaxAuditHistoryLimit {
+		return nil, ErrValidationAuditLimit
+	}
+
+	if !a.isModerator(userID) {
+		creatorID, err := a.eventStorage.GetCreatorID(ctx, eventID)
+		if err != nil {
+			return nil, fmt.Errorf("to get creator id: %w", err)
+		}
+
+		if creatorID != userID {
+			return nil, ErrForbiddenEventHistory
+		}
+	}
+
+	history, err := a.auditStorage.GetEventHistory(ctx, eventID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to get event history: %w", err)
+	}
+
+	return history, nil
+}
+
+// CleanAuditLog removes records older than retention from config.
+func (a *App) CleanAuditLog(ctx context.Context, period time.Duration) {
+	if a.auditSettings.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(period)
+		}
+
+		deleted, err := a.auditStorage.DeleteAuditRecordsBefore(ctx, time.Now().Add(-a.auditSettings.Retention))
+		if err != nil {
+			a.logger.WithCtx(ctx).Error(err)
+			continue
+		}
+
+		if deleted > 0 {
+			a.logger.Infof("deleted %d old audit records", deleted)
+		}
+	}
+}
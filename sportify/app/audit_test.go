@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/geocoder"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingAuditStorage struct {
+	AuditStorage
+	records []models.AuditRecord
+}
+
+func (s *recordingAuditStorage) AddAuditRecord(_ context.Context, record *models.AuditRecord) error {
+	s.records = append(s.records, *record)
+
+	return nil
+}
+
+type oneCommentStorage struct {
+	CommentStorage
+	comment *models.Comment
+	deleted bool
+}
+
+func (s *oneCommentStorage) GetComment(context.Context, uuid.UUID) (*models.Comment, error) {
+	return s.comment, nil
+}
+
+func (s *oneCommentStorage) DeleteComment(context.Context, uuid.UUID) error {
+	s.deleted = true
+
+	return nil
+}
+
+type creatorEventStorage struct {
+	EventStorage
+	creatorID uuid.UUID
+}
+
+func (s creatorEventStorage) GetCreatorID(context.Context, uuid.UUID) (uuid.UUID, error) {
+	return s.creatorID, nil
+}
+
+type nopEventStreamStorage struct {
+	EventStreamStorage
+}
+
+func (nopEventStreamStorage) NotifyEventChange(context.Context, []byte) error {
+	return nil
+}
+
+func newCommentDeleteApp(t *testing.T, comment *models.Comment, creatorID uuid.UUID) (
+	*App, *oneCommentStorage, *recordingAuditStorage,
+) {
+	t.Helper()
+
+	commentStorage := &oneCommentStorage{comment: comment} //nolint:exhaustruct
+	auditStorage := &recordingAuditStorage{}               //nolint:exhaustruct
+
+	return &App{ //nolint:exhaustruct
+		eventStorage:       creatorEventStorage{creatorID: creatorID}, //nolint:exhaustruct
+		commentStorage:     commentStorage,
+		auditStorage:       auditStorage,
+		eventStreamStorage: nopEventStreamStorage{}, //nolint:exhaustruct
+		logger:             mylogger.NewNop(),
+	}, commentStorage, auditStorage
+}
+
+func TestDeleteCommentAudit(t *testing.T) {
+	t.Parallel()
+
+	authorID, creatorID := uuid.New(), uuid.New()
+	comment := &models.Comment{ID: uuid.New(), EventID: uuid.New(), AuthorID: authorID, Text: "спам"} //nolint:exhaustruct
+
+	// author deletes own comment, it is not moderation
+	a, commentStorage, auditStorage := newCommentDeleteApp(t, comment, creatorID)
+	assert.NoError(t, a.DeleteComment(context.Background(), authorID, comment.ID))
+	assert.True(t, commentStorage.deleted)
+	assert.Empty(t, auditStorage.records)
+
+	a, commentStorage, auditStorage = newCommentDeleteApp(t, comment, creatorID)
+	assert.NoError(t, a.DeleteComment(context.Background(), creatorID, comment.ID))
+	assert.True(t, commentStorage.deleted)
+
+	if assert.Len(t, auditStorage.records, 1) {
+		record := auditStorage.records[0]
+		assert.Equal(t, models.AuditEntityComment, record.EntityType)
+		assert.Equal(t, comment.ID, record.EntityID)
+		assert.Equal(t, &comment.EventID, record.EventID)
+		assert.Equal(t, models.AuditActionDelete, record.Action)
+		assert.Equal(t, &creatorID, record.ActorID)
+		assert.Contains(t, string(record.Before), "спам")
+	}
+
+	a, commentStorage, auditStorage = newCommentDeleteApp(t, comment, creatorID)
+	assert.ErrorIs(t, a.DeleteComment(context.Background(), uuid.New(), comment.ID), ErrForbiddenDeleteNotYourComment)
+	assert.False(t, commentStorage.deleted)
+	assert.Empty(t, auditStorage.records)
+}
+
+type completingCoordinatesJobStorage struct {
+	CoordinatesJobStorage
+	completed []uuid.UUID
+}
+
+func (s *completingCoordinatesJobStorage) CompleteCoordinatesJob(
+	_ context.Context,
+	eventID uuid.UUID,
+	_, _ string,
+	_ *models.AddressDetails,
+) error {
+	s.completed = append(s.completed, eventID)
+
+	return nil
+}
+
+type constGeocoder struct {
+	Geocoder
+	coordinates *geocoder.Coordinates
+}
+
+func (g constGeocoder) Geocode(context.Context, string) (*geocoder.Coordinates, error) {
+	return g.coordinates, nil
+}
+
+func TestProcessCoordinatesJobAudit(t *testing.T) {
+	t.Parallel()
+
+	jobStorage := &completingCoordinatesJobStorage{} //nolint:exhaustruct
+	auditStorage := &recordingAuditStorage{}         //nolint:exhaustruct
+	a := &App{                                       //nolint:exhaustruct
+		coordinatesJobStorage: jobStorage,
+		auditStorage:          auditStorage,
+		geocoder: constGeocoder{coordinates: &geocoder.Coordinates{ //nolint:exhaustruct
+			Latitude:  "55.75",
+			Longitude: "37.61",
+		}},
+		logger: mylogger.NewNop(),
+	}
+
+	job := &models.CoordinatesJob{EventID: uuid.New(), Address: "Москва, Воротынская улица, 9", Attempts: 1}
+	a.processCoordinatesJob(context.Background(), job)
+
+	assert.Equal(t, []uuid.UUID{job.EventID}, jobStorage.completed)
+
+	if assert.Len(t, auditStorage.records, 1) {
+		record := auditStorage.records[0]
+		assert.Equal(t, models.AuditEntityEvent, record.EntityType)
+		assert.Equal(t, job.EventID, record.EntityID)
+		assert.Equal(t, models.AuditSourceSystem, record.Source)
+		assert.Nil(t, record.ActorID)
+		assert.Contains(t, string(record.Before), "Воротынская")
+		assert.Contains(t, string(record.After), "55.75")
+	}
+}
+
+func TestIsModerator(t *testing.T) {
+	t.Parallel()
+
+	moderatorID := uuid.New()
+	a := &App{auditSettings: models.AuditSettings{ModeratorIDs: []uuid.UUID{moderatorID}}} //nolint:exhaustruct
+
+	assert.True(t, a.isModerator(moderatorID))
+	assert.False(t, a.isModerator(uuid.New()))
+}
@@ -150,3 +150,102 @@ func (api *BotAPI) EventDeleted(ctx context.Context, eventDeleteRequest models.E
 
 	return fmt.Errorf("bad status code: %d", resp.StatusCode)
 }
+
+func (api *BotAPI) SendMessage(ctx context.Context, messageRequest models.MessageBotRequest) error {
+	reqURL := fmt.Sprintf("%s:%d/%s", api.baseURL, api.port, "message")
+
+	logger, err := mylogger.Get()
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	body, err := json.Marshal(messageRequest)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.WithCtx(ctx).Infow("Got response", "status", resp.StatusCode)
+
+	if 200 <= resp.StatusCode && resp.StatusCode < 300 {
+		return nil
+	}
+
+	return fmt.Errorf("bad status code: %d", resp.StatusCode)
+}
+
+func (api *BotAPI) CommentCreated(ctx context.Context, commentRequest models.CommentBotRequest) error {
+	reqURL := fmt.Sprintf("%s:%d/%s", api.baseURL, api.port, "comment")
+
+	logger, err := mylogger.Get()
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	body, err := json.Marshal(commentRequest)
+	if err != nil {
+		return fmt.Errorf("marshal comment: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.WithCtx(ctx).Infow("Got response", "status", resp.StatusCode)
+
+	if 200 <= resp.StatusCode && resp.StatusCode < 300 {
+		return nil
+	}
+
+	return fmt.Errorf("bad status code: %d", resp.StatusCode)
+}
+
+func (api *BotAPI) SendChatMessage(ctx context.Context, messageRequest models.ChatMessageBotRequest) error {
+	reqURL := fmt.Sprintf("%s:%d/%s", api.baseURL, api.port, "chat/message")
+
+	logger, err := mylogger.Get()
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	body, err := json.Marshal(messageRequest)
+	if err != nil {
+		return fmt.Errorf("marshal chat message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.WithCtx(ctx).Infow("Got response", "status", resp.StatusCode)
+
+	if 200 <= resp.StatusCode && resp.StatusCode < 300 {
+		return nil
+	}
+
+	return fmt.Errorf("bad status code: %d", resp.StatusCode)
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type CalendarStorage interface {
+	GetCalendarToken(ctx context.Context, userID uuid.UUID) (*string, error)
+	SetCalendarToken(ctx context.Context, userID uuid.UUID, token string) error
+}
+
+var _ CalendarStorage = (*db.PostgresStorage)(nil)
+
+const calendarTokenLen = 32
+
+var ErrForbiddenCalendarToken = errors.New("Неверная ссылка на календарь")
+
+// randomToken returns hex of size random bytes, it is used for secrets in urls and one-time codes.
+func randomToken(size int) (string, error) {
+	token := make([]byte, size)
+
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", fmt.Errorf("to read random: %w", err)
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// GetCalendarToken returns secret of personal calendar feed, it is made on first request.
+func (a *App) GetCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := a.calendarStorage.GetCalendarToken(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("to get calendar token: %w", err)
+	}
+
+	if token != nil {
+		return *token, nil
+	}
+
+	return a.ResetCalendarToken(ctx, userID)
+}
+
+// ResetCalendarToken makes new secret, so leaked link stops working.
+func (a *App) ResetCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := randomToken(calendarTokenLen)
+	if err != nil {
+		return "", fmt.Errorf("to make calendar token: %w", err)
+	}
+
+	err = a.calendarStorage.SetCalendarToken(ctx, userID, token)
+	if err != nil {
+		return "", fmt.Errorf("to set calendar token: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetCalendarFeed returns upcoming events which user joined or organizes, cancelled ones are
+// kept, so calendar apps mark them cancelled instead of silent removal.
+func (a *App) GetCalendarFeed(ctx context.Context, userID uuid.UUID, token string) ([]models.ShortEvent, error) {
+	savedToken, err := a.calendarStorage.GetCalendarToken(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to get calendar token: %w", err)
+	}
+
+	if savedToken == nil || subtle.ConstantTimeCompare([]byte(*savedToken), []byte(token)) != 1 {
+		return nil, ErrForbiddenCalendarToken
+	}
+
+	now := models.MoscowNow()
+	upcoming := squirrel.GtOrEq{"start_time": now.Add(-1 * time.Hour * 24)}
+
+	joined, err := a.FindEvents(ctx, &models.FilterParams{ //nolint:exhaustruct
+		SubscriberIDs:  []uuid.UUID{userID},
+		WithCancelled:  true,
+		DateExpression: upcoming,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to find joined events: %w", err)
+	}
+
+	organized, err := a.FindEvents(ctx, &models.FilterParams{ //nolint:exhaustruct
+		CreatorID:      &userID,
+		WithCancelled:  true,
+		DateExpression: upcoming,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to find organized events: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(joined)+len(organized))
+	result := make([]models.ShortEvent, 0, len(joined)+len(organized))
+
+	for _, event := range append(joined, organized...) {
+		if _, ok := seen[event.ID]; ok {
+			continue
+		}
+
+		seen[event.ID] = struct{}{}
+		result = append(result, event)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DateAndTime.StartTime.Before(result[j].DateAndTime.StartTime)
+	})
+
+	return result, nil
+}
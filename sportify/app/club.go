@@ -0,0 +1,402 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type ClubStorage interface {
+	CreateClub(ctx context.Context, club *models.Club) error
+	EditClub(ctx context.Context, club *models.Club) error
+	GetClub(ctx context.Context, id uuid.UUID) (*models.Club, error)
+	GetClubByTgChatID(ctx context.Context, tgChatID int64) (*models.Club, error)
+	FindClubs(ctx context.Context, query string) ([]models.Club, error)
+	GetClubMembers(ctx context.Context, clubID uuid.UUID) ([]models.ClubMember, error)
+	GetClubRole(ctx context.Context, clubID, userID uuid.UUID) (models.ClubRole, error)
+	AddClubMember(ctx context.Context, clubID, userID uuid.UUID, role models.ClubRole) error
+	SetClubMemberRole(ctx context.Context, clubID, userID uuid.UUID, role models.ClubRole) error
+	RemoveClubMember(ctx context.Context, clubID, userID uuid.UUID) error
+	CountClubAdmins(ctx context.Context, clubID uuid.UUID) (int, error)
+	CreateClubTgLinkCode(ctx context.Context, clubID uuid.UUID, code string, expiresAt time.Time) error
+	LinkClubTgChatByCode(ctx context.Context, code string, tgChatID int64) (uuid.UUID, error)
+}
+
+var _ ClubStorage = (*db.PostgresStorage)(nil)
+
+var (
+	ErrValidationRequestClub   = errors.New("Неправильные параметры клуба")
+	ErrForbiddenNotClubAdmin   = errors.New("Только администратор клуба может это сделать")
+	ErrForbiddenLastClubAdmin  = errors.New("Нельзя убрать последнего администратора клуба")
+	ErrClubTgChatAlreadyLinked = errors.New("Этот телеграм чат уже привязан к другому клубу")
+	ErrClubTgChatNotVerified   = errors.New("Новый телеграм чат привязывается только кодом, отправленным в этот чат")
+)
+
+const (
+	clubTgLinkCodeTTL   = 30 * time.Minute
+	clubTgLinkCodeBytes = 12
+)
+
+func (a *App) checkClubAdmin(ctx context.Context, clubID, userID uuid.UUID) error {
+	role, err := a.clubStorage.GetClubRole(ctx, clubID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFoundClubMember) {
+			return ErrForbiddenNotClubAdmin
+		}
+
+		return fmt.Errorf("to get club role: %w", err)
+	}
+
+	if role != models.ClubRoleAdmin {
+		return ErrForbiddenNotClubAdmin
+	}
+
+	return nil
+}
+
+// checkTgChatsOnlyUnlinked allows edit of club to remove chats but not to add them,
+// otherwise anyone could claim chat of another community.
+func checkTgChatsOnlyUnlinked(current, requested []int64) error {
+	for _, tgChatID := range requested {
+		if !slices.Contains(current, tgChatID) {
+			return fmt.Errorf("%w: %d", ErrClubTgChatNotVerified, tgChatID)
+		}
+	}
+
+	return nil
+}
+
+func (a *App) CreateClub(ctx context.Context, userID uuid.UUID, request *models.RequestClubCreate) (*models.Club, error) {
+	err := request.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationRequestClub, err)
+	}
+
+	club := &models.Club{
+		ID:          uuid.New(),
+		CreatorID:   userID,
+		Name:        request.Name,
+		Description: request.Description,
+		AvatarURL:   request.AvatarURL,
+		TgChatIDs:   []int64{},
+		CountMember: 1,
+		CreatedAt:   time.Now(),
+	}
+
+	err = a.clubStorage.CreateClub(ctx, club)
+	if err != nil {
+		return nil, fmt.Errorf("to create club: %w", err)
+	}
+
+	return club, nil
+}
+
+func (a *App) EditClub(ctx context.Context, userID, clubID uuid.UUID, request *models.RequestClubEdit) (*models.Club, error) {
+	err := a.checkClubAdmin(ctx, clubID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	clubFromDB, err := a.clubStorage.GetClub(ctx, clubID)
+	if err != nil {
+		return nil, fmt.Errorf("to get club: %w", err)
+	}
+
+	requestCreate := models.RequestClubCreate{
+		Name:        common.NewValWithFallback(request.Name, &clubFromDB.Name),
+		Description: request.Description,
+		AvatarURL:   request.AvatarURL,
+	}
+
+	err = requestCreate.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationRequestClub, err)
+	}
+
+	clubFromDB.Name = requestCreate.Name
+
+	if request.Description != nil {
+		clubFromDB.Description = request.Description
+	}
+
+	if request.AvatarURL != nil {
+		clubFromDB.AvatarURL = request.AvatarURL
+	}
+
+	if request.TgChatIDs != nil {
+		err = checkTgChatsOnlyUnlinked(clubFromDB.TgChatIDs, request.TgChatIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		clubFromDB.TgChatIDs = request.TgChatIDs
+	}
+
+	err = a.clubStorage.EditClub(ctx, clubFromDB)
+	if err != nil {
+		return nil, fmt.Errorf("to edit club: %w", err)
+	}
+
+	return clubFromDB, nil
+}
+
+func (a *App) SaveClubAvatar(ctx context.Context, userID, clubID uuid.UUID, file []byte) (string, error) {
+	err := a.checkClubAdmin(ctx, clubID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	variants, err := a.SaveImage(ctx, file)
+	if err != nil {
+		return "", fmt.Errorf("to save image: %w", err)
+	}
+
+	// avatar is small everywhere, full variant is not needed
+	url := variants.Medium
+
+	_, err = a.EditClub(ctx, userID, clubID, &models.RequestClubEdit{AvatarURL: &url}) //nolint:exhaustruct
+	if err != nil {
+		return "", fmt.Errorf("to edit club avatar: %w", err)
+	}
+
+	return url, nil
+}
+
+func (a *App) GetClub(ctx context.Context, clubID uuid.UUID) (*models.Club, error) {
+	return a.clubStorage.GetClub(ctx, clubID)
+}
+
+func (a *App) GetClubMembers(ctx context.Context, clubID uuid.UUID) ([]models.ClubMember, error) {
+	return a.clubStorage.GetClubMembers(ctx, clubID)
+}
+
+func (a *App) FindClubs(ctx context.Context, query string) ([]models.Club, error) {
+	return a.clubStorage.FindClubs(ctx, query)
+}
+
+func (a *App) JoinClub(ctx context.Context, userID, clubID uuid.UUID) error {
+	_, err := a.clubStorage.GetClub(ctx, clubID)
+	if err != nil {
+		return fmt.Errorf("to get club: %w", err)
+	}
+
+	err = a.clubStorage.AddClubMember(ctx, clubID, userID, models.ClubRoleMember)
+	if err != nil {
+		return fmt.Errorf("to add club member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveClubMember removes member from club. User can leave club by himself
+// or be removed by admin, but club can't stay without admins.
+func (a *App) RemoveClubMember(ctx context.Context, userID, clubID, memberID uuid.UUID) error {
+	if userID != memberID {
+		err := a.checkClubAdmin(ctx, clubID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	role, err := a.clubStorage.GetClubRole(ctx, clubID, memberID)
+	if err != nil {
+		return fmt.Errorf("to get club role: %w", err)
+	}
+
+	if role == models.ClubRoleAdmin {
+		countAdmins, err := a.clubStorage.CountClubAdmins(ctx, clubID)
+		if err != nil {
+			return fmt.Errorf("to count club admins: %w", err)
+		}
+
+		if countAdmins <= 1 {
+			return ErrForbiddenLastClubAdmin
+		}
+	}
+
+	err = a.clubStorage.RemoveClubMember(ctx, clubID, memberID)
+	if err != nil {
+		return fmt.Errorf("to remove club member: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) SetClubMemberRole(
+	ctx context.Context,
+	userID, clubID, memberID uuid.UUID,
+	request *models.RequestClubMemberRole,
+) error {
+	err := request.Valid()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrValidationRequestClub, err)
+	}
+
+	err = a.checkClubAdmin(ctx, clubID, userID)
+	if err != nil {
+		return err
+	}
+
+	if request.Role == models.ClubRoleMember {
+		role, err := a.clubStorage.GetClubRole(ctx, clubID, memberID)
+		if err != nil {
+			return fmt.Errorf("to get club role: %w", err)
+		}
+
+		if role == models.ClubRoleAdmin {
+			countAdmins, err := a.clubStorage.CountClubAdmins(ctx, clubID)
+			if err != nil {
+				return fmt.Errorf("to count club admins: %w", err)
+			}
+
+			if countAdmins <= 1 {
+				return ErrForbiddenLastClubAdmin
+			}
+		}
+	}
+
+	err = a.clubStorage.SetClubMemberRole(ctx, clubID, memberID, request.Role)
+	if err != nil {
+		return fmt.Errorf("to set club member role: %w", err)
+	}
+
+	return nil
+}
+
+// FindClubUpcomingEvents returns events owned by club that have not started yet.
+func (a *App) FindClubUpcomingEvents(ctx context.Context, clubID uuid.UUID) ([]models.ShortEvent, error) {
+	filterParams := &models.FilterParams{ //nolint:exhaustruct
+		ClubID:         &clubID,
+		OrderBy:        models.OrderByStartTime,
+		SortOrder:      "asc",
+		DateExpression: squirrel.GtOrEq{"start_time": models.MoscowNow()},
+	}
+
+	return a.FindEvents(ctx, filterParams)
+}
+
+// CreateClubTgLinkCode makes one-time code, club admin posts it to telegram chat to link chat to club.
+func (a *App) CreateClubTgLinkCode(ctx context.Context, userID, clubID uuid.UUID) (*models.ResponseClubTgLinkCode, error) {
+	err := a.checkClubAdmin(ctx, clubID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := randomToken(clubTgLinkCodeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("to generate code: %w", err)
+	}
+
+	expiresAt := time.Now().Add(clubTgLinkCodeTTL)
+
+	err = a.clubStorage.CreateClubTgLinkCode(ctx, clubID, code, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("to create club tg link code: %w", err)
+	}
+
+	return &models.ResponseClubTgLinkCode{
+		Command:   models.ClubTgLinkCommand + " " + code,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// clubTgLinkReply returns text bot posts to chat after link command, so admin sees the result.
+func clubTgLinkReply(club *models.Club, err error) string {
+	switch {
+	case err == nil:
+		return fmt.Sprintf("Чат привязан к клубу «%s»", club.Name)
+	case errors.Is(err, db.ErrNotFoundClubTgCode):
+		return db.ErrNotFoundClubTgCode.Error()
+	case errors.Is(err, db.ErrNotFoundClub):
+		return db.ErrNotFoundClub.Error()
+	case errors.Is(err, ErrClubTgChatAlreadyLinked):
+		return ErrClubTgChatAlreadyLinked.Error()
+	default:
+		return "Не удалось привязать чат, попробуйте позже"
+	}
+}
+
+// replyClubTgLink posts result of link command to chat. Bot waits for answer of the request
+// with the command, so reply is sent in background.
+func (a *App) replyClubTgLink(ctx context.Context, tgChatID int64, club *models.Club, errLink error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if pan := recover(); pan != nil {
+				a.logger.Errorf("panic: %v", pan)
+			}
+		}()
+
+		err := a.botAPI.SendChatMessage(ctx, models.ChatMessageBotRequest{
+			TgChatID: tgChatID,
+			Text:     clubTgLinkReply(club, errLink),
+		})
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to reply to link command", "tg_chat_id", tgChatID, "error", err)
+		}
+	}()
+}
+
+// TryLinkClubTgChat links chat to club if message is link command with valid code and replies to chat.
+// It returns false if message is not link command, so message is handled as usual.
+func (a *App) TryLinkClubTgChat(ctx context.Context, message string, tgChatID int64) (bool, error) {
+	code, ok := models.ParseClubTgLinkCommand(message)
+	if !ok {
+		return false, nil
+	}
+
+	club, err := a.linkClubTgChat(ctx, code, tgChatID)
+	a.replyClubTgLink(ctx, tgChatID, club, err)
+
+	if err != nil {
+		return true, err
+	}
+
+	a.logger.WithCtx(ctx).Infow("Telegram chat linked to club", "club_id", club.ID, "tg_chat_id", tgChatID)
+
+	return true, nil
+}
+
+func (a *App) linkClubTgChat(ctx context.Context, code string, tgChatID int64) (*models.Club, error) {
+	clubID, err := a.clubStorage.LinkClubTgChatByCode(ctx, code, tgChatID)
+	if err != nil {
+		if errors.Is(err, db.ErrClubTgChatTaken) {
+			return nil, ErrClubTgChatAlreadyLinked
+		}
+
+		return nil, fmt.Errorf("to link club tg chat: %w", err)
+	}
+
+	club, err := a.clubStorage.GetClub(ctx, clubID)
+	if err != nil {
+		return nil, fmt.Errorf("to get club: %w", err)
+	}
+
+	return club, nil
+}
+
+// attributeEventToClub sets club of event by telegram chat from which it came.
+// Creator stays dummy: code in chat proves access to chat, not authorship of messages in it.
+func (a *App) attributeEventToClub(ctx context.Context, fullEvent *models.FullEvent, tgChatID int64) {
+	club, err := a.clubStorage.GetClubByTgChatID(ctx, tgChatID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFoundClub) {
+			a.logger.WithCtx(ctx).Warnw("Unable to get club by tg chat", "tg_chat_id", tgChatID, "error", err)
+		}
+
+		return
+	}
+
+	fullEvent.ClubID = &club.ID
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTgChatsOnlyUnlinked(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkTgChatsOnlyUnlinked([]int64{1, 2}, []int64{2}))
+	assert.NoError(t, checkTgChatsOnlyUnlinked([]int64{1, 2}, []int64{}))
+	assert.ErrorIs(t, checkTgChatsOnlyUnlinked([]int64{1}, []int64{1, 3}), ErrClubTgChatNotVerified)
+}
+
+func TestClubTgLinkReply(t *testing.T) {
+	t.Parallel()
+
+	club := &models.Club{Name: "Футбол по средам"} //nolint:exhaustruct
+
+	assert.Equal(t, "Чат привязан к клубу «Футбол по средам»", clubTgLinkReply(club, nil))
+	assert.Equal(t, db.ErrNotFoundClubTgCode.Error(),
+		clubTgLinkReply(nil, fmt.Errorf("to link club tg chat: %w", db.ErrNotFoundClubTgCode)))
+	assert.Equal(t, db.ErrNotFoundClub.Error(),
+		clubTgLinkReply(nil, fmt.Errorf("to link club tg chat: %w", db.ErrNotFoundClub)))
+	assert.Equal(t, ErrClubTgChatAlreadyLinked.Error(), clubTgLinkReply(nil, ErrClubTgChatAlreadyLinked))
+	assert.Equal(t, "Не удалось привязать чат, попробуйте позже", clubTgLinkReply(nil, errors.New("connection reset")))
+}
@@ -0,0 +1,286 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type CommentStorage interface {
+	CreateComment(ctx context.Context, comment *models.Comment) error
+	GetComment(ctx context.Context, id uuid.UUID) (*models.Comment, error)
+	GetEventComments(ctx context.Context, eventID uuid.UUID) ([]models.Comment, error)
+	EditComment(ctx context.Context, id uuid.UUID, text string, editedAt time.Time) error
+	DeleteComment(ctx context.Context, id uuid.UUID) error
+	SetCommentsMirrorToTg(ctx context.Context, eventID uuid.UUID, mirror bool) error
+	GetCommentsMirrorToTg(ctx context.Context, eventID uuid.UUID) (bool, error)
+}
+
+var _ CommentStorage = (*db.PostgresStorage)(nil)
+
+var (
+	ErrForbiddenEditNotYourComment   = errors.New("Вы не можете изменять чужой комментарий")
+	ErrForbiddenDeleteNotYourComment = errors.New("Удалить комментарий может только автор или организатор")
+	ErrCommentParentNotFound         = errors.New("Комментарий, на который вы отвечаете, не найден")
+	ErrForbiddenCommentSettings      = errors.New("Настройки обсуждения может менять только организатор")
+)
+
+func (a *App) GetEventComments(ctx context.Context, eventID uuid.UUID) ([]models.Comment, error) {
+	_, err := a.eventStorage.GetCreatorID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("to get creator id: %w", err)
+	}
+
+	comments, err := a.commentStorage.GetEventComments(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("to get comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// getCommentParent checks that parent is in the same event, reply to reply goes to its top comment.
+func (a *App) getCommentParent(ctx context.Context, eventID uuid.UUID, parentID *uuid.UUID) (*models.Comment, error) {
+	if parentID == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	parent, err := a.commentStorage.GetComment(ctx, *parentID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFoundComment) {
+			return nil, ErrCommentParentNotFound
+		}
+
+		return nil, fmt.Errorf("to get parent comment: %w", err)
+	}
+
+	if parent.EventID != eventID {
+		return nil, ErrCommentParentNotFound
+	}
+
+	return parent, nil
+}
+
+func (a *App) CreateComment(
+	ctx context.Context,
+	userID, eventID uuid.UUID,
+	request *models.RequestCommentCreate,
+) (*models.Comment, error) {
+	event, err := a.eventStorage.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("to get event: %w", err)
+	}
+
+	parent, err := a.getCommentParent(ctx, eventID, request.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		AuthorID:  userID,
+		ParentID:  nil,
+		Text:      request.Text,
+		Deleted:   false,
+		EditedAt:  nil,
+		CreatedAt: time.Now(),
+	}
+
+	if parent != nil {
+		comment.ParentID = &parent.ID
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
+	err = a.commentStorage.CreateComment(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("to create comment: %w", err)
+	}
+
+	a.onCommentCreate(ctx, event, parent, comment)
+	a.publishCommentChange(ctx, comment, models.EventStreamCommentCreated)
+
+	return comment, nil
+}
+
+// onCommentCreate tells organizer, author of parent comment and mentioned users about comment.
+// Every user gets one notification, mention is the most important one. Comment is sent to telegram
+// only if organizer turned it on for event.
+func (a *App) onCommentCreate(ctx context.Context, event *models.FullEvent, parent, comment *models.Comment) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if pan := recover(); pan != nil {
+				a.logger.Errorf("panic: %v", pan)
+			}
+		}()
+
+		author, err := a.authStorage.GetUserFullByID(ctx, comment.AuthorID)
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to get author of comment", "comment_id", comment.ID, "error", err)
+			return
+		}
+
+		title := eventTitle(&event.ShortEvent)
+		notified := map[uuid.UUID]struct{}{comment.AuthorID: {}}
+
+		for _, username := range models.ParseMentions(comment.Text) {
+			user, err := a.authStorage.GetUserFullByUsername(ctx, username)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := notified[user.ID]; ok {
+				continue
+			}
+
+			notified[user.ID] = struct{}{}
+
+			a.notify(ctx, models.NotificationCommentMention, []uuid.UUID{user.ID}, &event.ID,
+				fmt.Sprintf("%s упомянул вас в обсуждении «%s»: %s", author.Username, title, comment.Text))
+		}
+
+		if parent != nil {
+			if _, ok := notified[parent.AuthorID]; !ok {
+				notified[parent.AuthorID] = struct{}{}
+
+				a.notify(ctx, models.NotificationCommentReply, []uuid.UUID{parent.AuthorID}, &event.ID,
+					fmt.Sprintf("%s ответил на ваш комментарий к «%s»: %s", author.Username, title, comment.Text))
+			}
+		}
+
+		if _, ok := notified[event.CreatorID]; !ok {
+			a.notify(ctx, models.NotificationEventComment, []uuid.UUID{event.CreatorID}, &event.ID,
+				fmt.Sprintf("Новый комментарий к «%s» от %s: %s", title, author.Username, comment.Text))
+		}
+
+		if event.TgChatID == nil || event.TgMessageID == nil {
+			return
+		}
+
+		mirrorToTg, err := a.commentStorage.GetCommentsMirrorToTg(ctx, event.ID)
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to get comments mirror", "event_id", event.ID, "error", err)
+			return
+		}
+
+		if !mirrorToTg {
+			return
+		}
+
+		err = a.botAPI.CommentCreated(ctx, models.CommentBotRequest{
+			TgChatID:    *event.TgChatID,
+			TgMessageID: *event.TgMessageID,
+			Author:      author.Username,
+			Text:        comment.Text,
+		})
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to mirror comment", "comment_id", comment.ID, "error", err)
+		}
+	}()
+}
+
+// SetCommentSettings is allowed only to organizer, commenters can't send to chat of organizer.
+func (a *App) SetCommentSettings(
+	ctx context.Context,
+	userID, eventID uuid.UUID,
+	request *models.RequestCommentSettings,
+) error {
+	creatorID, err := a.eventStorage.GetCreatorID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("to get creator id: %w", err)
+	}
+
+	if creatorID != userID {
+		return ErrForbiddenCommentSettings
+	}
+
+	err = a.commentStorage.SetCommentsMirrorToTg(ctx, eventID, request.MirrorToTg)
+	if err != nil {
+		return fmt.Errorf("to set comments mirror: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) EditComment(
+	ctx context.Context,
+	userID, commentID uuid.UUID,
+	request *models.RequestCommentEdit,
+) (*models.Comment, error) {
+	comment, err := a.commentStorage.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("to get comment: %w", err)
+	}
+
+	if comment.AuthorID != userID {
+		return nil, ErrForbiddenEditNotYourComment
+	}
+
+	editedAt := time.Now()
+
+	err = a.commentStorage.EditComment(ctx, commentID, request.Text, editedAt)
+	if err != nil {
+		return nil, fmt.Errorf("to edit comment: %w", err)
+	}
+
+	comment.Text = request.Text
+	comment.EditedAt = &editedAt
+
+	a.publishCommentChange(ctx, comment, models.EventStreamCommentEdited)
+
+	return comment, nil
+}
+
+// DeleteComment is allowed to author and to organizer of event, who moderates discussion.
+func (a *App) DeleteComment(ctx context.Context, userID, commentID uuid.UUID) error {
+	comment, err := a.commentStorage.GetComment(ctx, commentID)
+	if err != nil {
+		return fmt.Errorf("to get comment: %w", err)
+	}
+
+	moderation := comment.AuthorID != userID
+
+	if moderation {
+		creatorID, err := a.eventStorage.GetCreatorID(ctx, comment.EventID)
+		if err != nil {
+			return fmt.Errorf("to get creator id: %w", err)
+		}
+
+		if creatorID != userID {
+			return ErrForbiddenDeleteNotYourComment
+		}
+	}
+
+	err = a.commentStorage.DeleteComment(ctx, commentID)
+	if err != nil {
+		return fmt.Errorf("to delete comment: %w", err)
+	}
+
+	// author removes own words, only removal by organizer is kept in history
+	if moderation {
+		a.audit(ctx, &models.AuditRecord{ //nolint:exhaustruct
+			EntityType: models.AuditEntityComment,
+			EntityID:   comment.ID,
+			EventID:    &comment.EventID,
+			Action:     models.AuditActionDelete,
+			ActorID:    &userID,
+			Source:     models.AuditSourceSite,
+			Before:     auditJSON(comment),
+		})
+	}
+
+	a.publishCommentChange(ctx, comment, models.EventStreamCommentDeleted)
+
+	return nil
+}
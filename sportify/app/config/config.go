@@ -18,6 +18,11 @@ const (
 	// vaultConfigPath = "config/vault"
 )
 
+const (
+	FileStorageTypeFS = "fs"
+	FileStorageTypeS3 = "s3"
+)
+
 var (
 	// This is a global variable that contains the configuration for the Sportify application.
 	// It is initialized in the init function and can oly be accessed through the GetGlobalConfig function.
@@ -106,6 +111,48 @@ type Config struct {
 		Port    int    `mapstructure:"port"`
 	} `mapstructure:"bot_api"`
 
+	Geocoder struct {
+		UserAgent        string        `mapstructure:"user_agent"`
+		NominatimRPS     float64       `mapstructure:"nominatim_rps"`
+		YandexRPS        float64       `mapstructure:"yandex_rps"`
+		StaticFile       string        `mapstructure:"static_file"`
+		SyncTimeout      time.Duration `mapstructure:"sync_timeout"`
+		CacheTTL         time.Duration `mapstructure:"cache_ttl"`
+		NegativeCacheTTL time.Duration `mapstructure:"negative_cache_ttl"`
+	} `mapstructure:"geocoder"`
+
+	Recommendations struct {
+		SportType  float64 `mapstructure:"sport_type"`
+		Level      float64 `mapstructure:"level"`
+		Distance   float64 `mapstructure:"distance"`
+		Weekday    float64 `mapstructure:"weekday"`
+		TimeOfDay  float64 `mapstructure:"time_of_day"`
+		Venue      float64 `mapstructure:"venue"`
+		Friends    float64 `mapstructure:"friends"`
+		FreePlaces float64 `mapstructure:"free_places"`
+	} `mapstructure:"recommendations"`
+
+	FileStorage struct {
+		// Type is FileStorageTypeFS for directory path_photos or FileStorageTypeS3 for bucket.
+		Type string `mapstructure:"type"`
+		S3   struct {
+			Endpoint  string `mapstructure:"endpoint"`
+			Region    string `mapstructure:"region"`
+			Bucket    string `mapstructure:"bucket"`
+			AccessKey string `mapstructure:"access_key"`
+			SecretKey string `mapstructure:"secret_key"`
+			PathStyle bool   `mapstructure:"path_style"`
+			// PublicURL is prefix of public bucket, files of private bucket are read by presigned urls.
+			PublicURL      string        `mapstructure:"public_url"`
+			PresignExpires time.Duration `mapstructure:"presign_expires"`
+		} `mapstructure:"s3"`
+	} `mapstructure:"file_storage"`
+
+	Audit struct {
+		Retention    time.Duration `mapstructure:"retention"`
+		ModeratorIDs []string      `mapstructure:"moderator_ids"`
+	} `mapstructure:"audit"`
+
 	// Consul struct {
 	// 	Address string `mapstructure:"address"`
 	// }
@@ -148,6 +195,28 @@ func initDefaults() {
 	viper.SetDefault("bot_api.port", 8081)
 	viper.SetDefault("bot_api.base_url", "http://host.docker.internal")
 
+	viper.SetDefault("geocoder.user_agent", "SportifyApp/1.0")
+	viper.SetDefault("geocoder.nominatim_rps", 1)
+	viper.SetDefault("geocoder.yandex_rps", 5)
+	viper.SetDefault("geocoder.sync_timeout", 3*time.Second)
+	viper.SetDefault("geocoder.cache_ttl", 30*24*time.Hour)
+	viper.SetDefault("geocoder.negative_cache_ttl", 24*time.Hour)
+
+	viper.SetDefault("recommendations.sport_type", 3)
+	viper.SetDefault("recommendations.level", 1.5)
+	viper.SetDefault("recommendations.distance", 2)
+	viper.SetDefault("recommendations.weekday", 1)
+	viper.SetDefault("recommendations.time_of_day", 1)
+	viper.SetDefault("recommendations.venue", 1)
+	viper.SetDefault("recommendations.friends", 2)
+	viper.SetDefault("recommendations.free_places", 0.5)
+	viper.SetDefault("file_storage.type", FileStorageTypeFS)
+	viper.SetDefault("file_storage.s3.region", "us-east-1")
+	viper.SetDefault("file_storage.s3.presign_expires", time.Hour)
+
+	viper.SetDefault("audit.retention", 365*24*time.Hour)
+	viper.SetDefault("audit.moderator_ids", []string{})
+
 	viper.SetDefault("consul.address", "localhost:8500")
 }
 
@@ -157,6 +226,8 @@ func initEnvironment() {
 	viper.MustBindEnv("postgres.db", "POSTGRES_DB")
 	viper.MustBindEnv("postgres.user", "POSTGRES_USER")
 	viper.MustBindEnv("postgres.password", "POSTGRES_PASSWORD")
+	viper.MustBindEnv("file_storage.s3.access_key", "S3_ACCESS_KEY")
+	viper.MustBindEnv("file_storage.s3.secret_key", "S3_SECRET_KEY")
 }
 
 // InitConfigFile searches for the config file in the given paths and initializes the viper config with it.
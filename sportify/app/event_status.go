@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrForbiddenStatusNotYourEvent = errors.New("Вы не можете менять статус чужого события")
+	ErrInvalidStatusChange         = errors.New("Нельзя перевести событие в этот статус")
+)
+
+func eventStatusText(event *models.ShortEvent) string {
+	title := eventTitle(event)
+
+	switch event.Status {
+	case models.EventStatusCancelled:
+		if event.CancelReason != nil {
+			return fmt.Sprintf("Событие «%s» отменено: %s", title, *event.CancelReason)
+		}
+
+		return fmt.Sprintf("Событие «%s» отменено", title)
+	case models.EventStatusPostponed:
+		return fmt.Sprintf("Событие «%s» перенесено, организатор сообщит новое время", title)
+	case models.EventStatusScheduled:
+		return fmt.Sprintf("Событие «%s» снова в расписании", title)
+	case models.EventStatusFinished:
+		return fmt.Sprintf("Событие «%s» завершено", title)
+	}
+
+	return ""
+}
+
+// refundTimeout bounds refunds started on cancellation, unfinished ones are left to RefundPendingPayments.
+const refundTimeout = time.Minute
+
+// refundEventPayments marks money of cancelled event to be returned and tells payers about it.
+// Yookassa is asked in background, so organizer doesn't wait for refund of every payer.
+func (a *App) refundEventPayments(ctx context.Context, event *models.ShortEvent) {
+	payments, err := a.paymentPayoutStorage.RefundEventPayments(ctx, event.ID)
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to mark payments for refund", "event_id", event.ID, "error", err)
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(payments))
+	for i := range payments {
+		userIDs = append(userIDs, payments[i].UserID)
+		a.auditPayment(ctx, models.AuditActionStatus, models.AuditSourceSystem, nil, &payments[i])
+	}
+
+	a.notify(ctx, models.NotificationPaymentStatus, userIDs, &event.ID,
+		fmt.Sprintf("Деньги за событие «%s» будут возвращены", eventTitle(event)))
+
+	if len(payments) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refundTimeout)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if pan := recover(); pan != nil {
+				a.logger.Errorf("panic: %v", pan)
+			}
+		}()
+
+		a.refundPayments(ctx, payments)
+	}()
+}
+
+// refundPayments asks yookassa to return money. Payment stays refund_pending until refund succeeded,
+// key of idempotency is id of payment, so repeated request doesn't return money twice.
+func (a *App) refundPayments(ctx context.Context, payments []models.Payment) {
+	if len(payments) == 0 {
+		return
+	}
+
+	if a.yookassaClient == nil {
+		a.logger.WithCtx(ctx).Warnw("Yookassa is not configured, refunds are left pending", "count", len(payments))
+		return
+	}
+
+	for i := range payments {
+		payment := &payments[i]
+
+		succeeded, err := a.yookassaClient.DoRefund(ctx, payment.ID.String(), payment.ID, float64(payment.Amount))
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to refund payment", "payment_id", payment.ID, "error", err)
+			continue
+		}
+
+		if !succeeded {
+			continue
+		}
+
+		err = a.paymentPayoutStorage.UpdateStatusPayment(ctx, payment.ID, models.PaymentStatusRefunded)
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to update refunded payment", "payment_id", payment.ID, "error", err)
+			continue
+		}
+
+		payment.Status = models.PaymentStatusRefunded
+		a.auditPayment(ctx, models.AuditActionStatus, models.AuditSourceSystem, nil, payment)
+	}
+}
+
+// RefundPendingPayments retries refunds which failed or were pending in yookassa.
+func (a *App) RefundPendingPayments(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(period)
+		}
+
+		payments, err := a.paymentPayoutStorage.GetPaymentsByStatus(ctx, models.PaymentStatusRefundPending)
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to get payments for refund", "error", err)
+			continue
+		}
+
+		a.refundPayments(ctx, payments)
+	}
+}
+
+// ChangeEventStatus cancels, postpones or finishes event. Unlike deletion, event stays
+// in lists of participants and its telegram post shows new status.
+func (a *App) ChangeEventStatus(
+	ctx context.Context,
+	userID, eventID uuid.UUID,
+	request *models.RequestEventStatus,
+) (*models.FullEvent, error) {
+	event, err := a.eventStorage.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("to get event: %w", err)
+	}
+
+	if event.CreatorID != userID {
+		return nil, ErrForbiddenStatusNotYourEvent
+	}
+
+	if !event.Status.CanChangeTo(request.Status) {
+		return nil, ErrInvalidStatusChange
+	}
+
+	cancelReason := request.Reason
+	if request.Status != models.EventStatusCancelled {
+		cancelReason = nil
+	}
+
+	err = a.eventStorage.SetEventStatus(ctx, eventID, event.Status, request.Status, cancelReason)
+	if err != nil {
+		if errors.Is(err, db.ErrEventStatusChanged) {
+			return nil, ErrInvalidStatusChange
+		}
+
+		return nil, fmt.Errorf("to set event status: %w", err)
+	}
+
+	before := *event
+	event.Status = request.Status
+	event.CancelReason = cancelReason
+
+	a.auditEvent(ctx, models.AuditActionStatus, models.AuditSourceSite, &userID, &before, event)
+
+	a.onEventUpdate(ctx, eventID, nil)
+	a.publishEventChange(ctx, eventID, models.EventStreamStatus, models.EventStreamStatusData{
+		Status:       event.Status,
+		CancelReason: event.CancelReason,
+	})
+
+	kind := models.NotificationEventUpdated
+	if event.Status == models.EventStatusCancelled {
+		kind = models.NotificationEventCancelled
+
+		a.refundEventPayments(ctx, &event.ShortEvent)
+	}
+
+	if event.Status != models.EventStatusFinished {
+		a.notifyParticipants(ctx, kind, &event.ShortEvent, userID, eventStatusText(&event.ShortEvent))
+	}
+
+	return event, nil
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type EventStreamStorage interface {
+	NotifyEventChange(ctx context.Context, payload []byte) error
+	ListenEventChanges(ctx context.Context, handle func(payload []byte)) error
+}
+
+var _ EventStreamStorage = (*db.PostgresStorage)(nil)
+
+const (
+	eventStreamBuffer         = 16
+	eventStreamReconnectDelay = 5 * time.Second
+)
+
+// eventHub fans out changes of events to streams opened on this instance of app.
+type eventHub struct {
+	mu      sync.Mutex
+	streams map[uuid.UUID]map[chan models.EventStreamMessage]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		mu:      sync.Mutex{},
+		streams: make(map[uuid.UUID]map[chan models.EventStreamMessage]struct{}),
+	}
+}
+
+func (h *eventHub) subscribe(eventID uuid.UUID) (<-chan models.EventStreamMessage, func()) {
+	stream := make(chan models.EventStreamMessage, eventStreamBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.streams[eventID] == nil {
+		h.streams[eventID] = make(map[chan models.EventStreamMessage]struct{})
+	}
+
+	h.streams[eventID][stream] = struct{}{}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.streams[eventID][stream]; !ok {
+			return
+		}
+
+		delete(h.streams[eventID], stream)
+		close(stream)
+
+		if len(h.streams[eventID]) == 0 {
+			delete(h.streams, eventID)
+		}
+	}
+
+	return stream, unsubscribe
+}
+
+// publish doesn't wait for slow stream, it misses message and page gets event again on the next one.
+func (h *eventHub) publish(message models.EventStreamMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for stream := range h.streams[message.EventID] {
+		select {
+		case stream <- message:
+		default:
+		}
+	}
+}
+
+// publishEventChange sends change through postgres, so streams of all instances of app get it.
+func (a *App) publishEventChange(ctx context.Context, eventID uuid.UUID, kind models.EventStreamKind, data any) {
+	message := models.EventStreamMessage{EventID: eventID, Kind: kind, Data: nil}
+
+	if data != nil {
+		rawData, err := json.Marshal(data)
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to marshal event change", "event_id", eventID, "error", err)
+			return
+		}
+
+		message.Data = rawData
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to marshal event change", "event_id", eventID, "error", err)
+		return
+	}
+
+	err = a.eventStreamStorage.NotifyEventChange(ctx, payload)
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to publish event change", "event_id", eventID, "kind", kind, "error", err)
+	}
+}
+
+func (a *App) publishSubscribersChange(ctx context.Context, response *models.ResponseSubscribeEvent) {
+	a.publishEventChange(ctx, response.ID, models.EventStreamSubscribers, models.EventStreamSubscribersData{
+		Capacity: response.Capacity,
+		Busy:     response.Busy,
+	})
+}
+
+func (a *App) publishCommentChange(ctx context.Context, comment *models.Comment, action string) {
+	a.publishEventChange(ctx, comment.EventID, models.EventStreamComment, models.EventStreamCommentData{
+		CommentID: comment.ID,
+		Action:    action,
+	})
+}
+
+// ListenEventChanges bridges postgres notifications to streams of this instance, it reconnects when
+// connection is lost.
+func (a *App) ListenEventChanges(ctx context.Context) {
+	for {
+		err := a.eventStreamStorage.ListenEventChanges(ctx, func(payload []byte) {
+			var message models.EventStreamMessage
+
+			err := json.Unmarshal(payload, &message)
+			if err != nil {
+				a.logger.WithCtx(ctx).Warnw("Unable to unmarshal event change", "error", err)
+				return
+			}
+
+			a.eventHub.publish(message)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		a.logger.WithCtx(ctx).Warnw("Listening of event changes stopped", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventStreamReconnectDelay):
+		}
+	}
+}
+
+// SubscribeEventChanges opens stream of changes of event. Stream is visible to the same users
+// as GET /event/{id}: event is public until it is deleted, so stream is checked by GetEvent
+// and new rules of it apply to stream too.
+func (a *App) SubscribeEventChanges(ctx context.Context, eventID uuid.UUID) (
+	<-chan models.EventStreamMessage, func(), error,
+) {
+	_, err := a.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream, unsubscribe := a.eventHub.subscribe(eventID)
+
+	return stream, unsubscribe, nil
+}
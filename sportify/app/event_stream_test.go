@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventHub(t *testing.T) {
+	t.Parallel()
+
+	hub := newEventHub()
+	eventID, otherEventID := uuid.New(), uuid.New()
+
+	first, unsubscribeFirst := hub.subscribe(eventID)
+	second, unsubscribeSecond := hub.subscribe(eventID)
+	other, unsubscribeOther := hub.subscribe(otherEventID)
+
+	defer unsubscribeSecond()
+	defer unsubscribeOther()
+
+	message := models.EventStreamMessage{EventID: eventID, Kind: models.EventStreamUpdated, Data: nil}
+	hub.publish(message)
+
+	assert.Equal(t, message, <-first)
+	assert.Equal(t, message, <-second)
+	assert.Empty(t, other)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+
+	_, ok := <-first
+	assert.False(t, ok)
+
+	// slow stream doesn't block publish
+	for range eventStreamBuffer + 1 {
+		hub.publish(message)
+	}
+
+	assert.Len(t, second, eventStreamBuffer)
+}
+
+type missingEventStorage struct {
+	EventStorage
+}
+
+func (missingEventStorage) GetEvent(context.Context, uuid.UUID) (*models.FullEvent, error) {
+	return nil, db.ErrNotFoundEvent
+}
+
+func TestSubscribeEventChangesChecksEvent(t *testing.T) {
+	t.Parallel()
+
+	a := &App{eventStorage: missingEventStorage{}, eventHub: newEventHub()} //nolint:exhaustruct
+
+	_, _, err := a.SubscribeEventChanges(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, db.ErrNotFoundEvent)
+	assert.Empty(t, a.eventHub.streams)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+var ErrDistanceWithoutPoint = errors.New("Не удалось определить точку для сортировки по расстоянию")
+
+// FindEventsPage returns one page of events and cursor of the next page.
+func (a *App) FindEventsPage(ctx context.Context, filterParams *models.FilterParams) (*models.EventsPage, error) {
+	a.applyAddressSearch(ctx, filterParams)
+
+	if filterParams.OrderBy == models.OrderByDistance && filterParams.Latitude == nil {
+		return nil, ErrDistanceWithoutPoint
+	}
+
+	events, err := a.eventStorage.FindEvents(ctx, filterParams)
+	if err != nil {
+		return nil, fmt.Errorf("to find events: %w", err)
+	}
+
+	result := &models.EventsPage{Events: events} //nolint:exhaustruct
+
+	// storage returns one extra event when there is next page
+	if filterParams.Limit > 0 && len(events) > filterParams.Limit {
+		result.Events = events[:filterParams.Limit]
+		last := result.Events[len(result.Events)-1]
+
+		result.NextCursor = (&models.EventsCursor{
+			OrderBy:   filterParams.OrderBy,
+			SortOrder: filterParams.SortOrder,
+			Value:     last.SortValue,
+			ID:        last.ID,
+		}).Encode()
+	}
+
+	if filterParams.WithTotal {
+		total, err := a.mapStorage.CountEvents(ctx, filterParams)
+		if err != nil {
+			return nil, fmt.Errorf("to count events: %w", err)
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+type FacetStorage interface {
+	FindEventFacets(ctx context.Context, filterParams *models.FilterParams, today time.Time) (*models.EventFacets, error)
+}
+
+var _ FacetStorage = (*db.PostgresStorage)(nil)
+
+func (a *App) FindEventFacets(ctx context.Context, filterParams *models.FilterParams) (*models.EventFacets, error) {
+	a.applyAddressSearch(ctx, filterParams)
+
+	now := models.MoscowNow()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	facets, err := a.facetStorage.FindEventFacets(ctx, filterParams, today)
+	if err != nil {
+		return nil, fmt.Errorf("to find event facets: %w", err)
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,129 @@
+// Package filemigrate copies files from one storage to another, e.g. from disk to s3 bucket,
+// and checks that copy has the same content.
+package filemigrate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+)
+
+type SourceStorage interface {
+	ListFiles(ctx context.Context) ([]string, error)
+	ReadFile(ctx context.Context, fileName string) ([]byte, error)
+}
+
+var _ SourceStorage = (*db.FileSystemStorage)(nil)
+
+type TargetStorage interface {
+	Check(ctx context.Context, files []string) ([]bool, error)
+	SaveFile(ctx context.Context, content []byte, fileName string) error
+	ReadFile(ctx context.Context, fileName string) ([]byte, error)
+}
+
+var _ TargetStorage = (*db.S3Storage)(nil)
+
+var ErrHashMismatch = errors.New("hash of copied file differs from original")
+
+const logEvery = 100
+
+type Stats struct {
+	Found    int
+	Uploaded int
+	Skipped  int
+	Failed   int
+}
+
+// Migrate copies all files of source to target. Files which already are in target
+// with the same hash are skipped, other files are rewritten. Failed file doesn't stop migration.
+func Migrate(ctx context.Context, source SourceStorage, target TargetStorage, logger *mylogger.MyLogger) (*Stats, error) {
+	stats := &Stats{}
+
+	files, err := source.ListFiles(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("to list files: %w", err)
+	}
+
+	stats.Found = len(files)
+
+	for i, fileName := range files {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		uploaded, err := migrateFile(ctx, source, target, fileName)
+		switch {
+		case err != nil:
+			stats.Failed++
+
+			logger.Errorf("to migrate file %s: %v", fileName, err)
+		case uploaded:
+			stats.Uploaded++
+		default:
+			stats.Skipped++
+		}
+
+		if (i+1)%logEvery == 0 {
+			logger.Infof("migrated %d of %d files", i+1, stats.Found)
+		}
+	}
+
+	return stats, nil
+}
+
+func migrateFile(ctx context.Context, source SourceStorage, target TargetStorage, fileName string) (bool, error) {
+	content, err := source.ReadFile(ctx, fileName)
+	if err != nil {
+		return false, fmt.Errorf("to read source: %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+
+	exists, err := target.Check(ctx, []string{fileName})
+	if err != nil {
+		return false, fmt.Errorf("to check target: %w", err)
+	}
+
+	if exists[0] {
+		equal, err := hasHash(ctx, target, fileName, hash)
+		if err != nil {
+			return false, err
+		}
+
+		if equal {
+			return false, nil
+		}
+	}
+
+	err = target.SaveFile(ctx, content, fileName)
+	if err != nil {
+		return false, fmt.Errorf("to save target: %w", err)
+	}
+
+	equal, err := hasHash(ctx, target, fileName, hash)
+	if err != nil {
+		return false, err
+	}
+
+	if !equal {
+		return false, ErrHashMismatch
+	}
+
+	return true, nil
+}
+
+func hasHash(ctx context.Context, target TargetStorage, fileName string, hash [sha256.Size]byte) (bool, error) {
+	content, err := target.ReadFile(ctx, fileName)
+	if err != nil {
+		return false, fmt.Errorf("to read target: %w", err)
+	}
+
+	targetHash := sha256.Sum256(content)
+
+	return bytes.Equal(hash[:], targetHash[:]), nil
+}
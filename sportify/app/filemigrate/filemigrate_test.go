@@ -0,0 +1,90 @@
+package filemigrate_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/filemigrate"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errNotFound = errors.New("not found")
+
+type mapStorage struct {
+	files map[string][]byte
+	// corrupt makes saved content differ from given.
+	corrupt bool
+}
+
+func (m *mapStorage) ListFiles(_ context.Context) ([]string, error) {
+	result := make([]string, 0, len(m.files))
+	for fileName := range m.files {
+		result = append(result, fileName)
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func (m *mapStorage) ReadFile(_ context.Context, fileName string) ([]byte, error) {
+	content, ok := m.files[fileName]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return content, nil
+}
+
+func (m *mapStorage) Check(_ context.Context, files []string) ([]bool, error) {
+	result := make([]bool, len(files))
+	for i, fileName := range files {
+		_, result[i] = m.files[fileName]
+	}
+
+	return result, nil
+}
+
+func (m *mapStorage) SaveFile(_ context.Context, content []byte, fileName string) error {
+	if m.corrupt {
+		content = append([]byte("x"), content...)
+	}
+
+	m.files[fileName] = content
+
+	return nil
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	source := &mapStorage{files: map[string][]byte{
+		"a.jpg": []byte("a"),
+		"b.jpg": []byte("b"),
+		"c.jpg": []byte("c"),
+	}}
+	target := &mapStorage{files: map[string][]byte{
+		"a.jpg": []byte("a"),
+		"b.jpg": []byte("old"),
+	}}
+
+	stats, err := filemigrate.Migrate(context.Background(), source, target, mylogger.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, &filemigrate.Stats{Found: 3, Uploaded: 2, Skipped: 1, Failed: 0}, stats)
+	assert.Equal(t, source.files, target.files)
+}
+
+func TestMigrateHashMismatch(t *testing.T) {
+	t.Parallel()
+
+	source := &mapStorage{files: map[string][]byte{"a.jpg": []byte("a")}}
+	target := &mapStorage{files: map[string][]byte{}, corrupt: true}
+
+	stats, err := filemigrate.Migrate(context.Background(), source, target, mylogger.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, &filemigrate.Stats{Found: 1, Uploaded: 0, Skipped: 0, Failed: 1}, stats)
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/geocoder"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
+)
+
+var ErrAddressNotFound = errors.New("Не удалось найти адрес по координатам")
+
+// resolveEventAddress finds coordinates and canonical address while event is saved.
+// Providers can be slow, so it waits only geocodeTimeout, and if address is not
+// resolved in time, coordinates queue will do it later.
+func (a *App) resolveEventAddress(ctx context.Context, event *models.FullEvent) {
+	if event.VenueID != nil || strings.TrimSpace(event.Address) == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.geocodeTimeout)
+	defer cancel()
+
+	coordinates, err := a.geocoder.Geocode(ctx, reformat_url_open_map.ReformatURLOpenMap(event.Address))
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to resolve event address",
+			"event_id", event.ID, "address", event.Address, "error", err)
+
+		return
+	}
+
+	event.Latitude = &coordinates.Latitude
+	event.Longitude = &coordinates.Longitude
+	event.AddressDetails = coordinates.Address
+}
+
+func (a *App) ReverseGeocode(ctx context.Context, latitude, longitude float64) (*models.ResponseReverseGeocode, error) {
+	details, err := a.geocoder.Reverse(ctx, latitude, longitude)
+	if err != nil {
+		if errors.Is(err, geocoder.ErrNotFound) {
+			return nil, ErrAddressNotFound
+		}
+
+		return nil, fmt.Errorf("to reverse geocode: %w", err)
+	}
+
+	return &models.ResponseReverseGeocode{
+		Latitude:  strconv.FormatFloat(latitude, 'f', -1, 64),
+		Longitude: strconv.FormatFloat(longitude, 'f', -1, 64),
+		Address:   details,
+	}, nil
+}
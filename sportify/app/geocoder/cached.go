@@ -0,0 +1,141 @@
+package geocoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+)
+
+type CacheStorage interface {
+	GetGeocodeCache(ctx context.Context, address string) (*models.GeocodeCacheEntry, error)
+	SetGeocodeCache(ctx context.Context, entry *models.GeocodeCacheEntry) error
+}
+
+var _ CacheStorage = (*db.PostgresStorage)(nil)
+
+// Cached keeps results of geocoder in storage by normalized address.
+// Not found addresses are cached too, but for shorter negativeTTL,
+// so typos are not sent to providers again and again.
+type Cached struct {
+	geocoder    Geocoder
+	storage     CacheStorage
+	ttl         time.Duration
+	negativeTTL time.Duration
+	logger      *mylogger.MyLogger
+}
+
+func NewCached(
+	geocoder Geocoder,
+	storage CacheStorage,
+	ttl, negativeTTL time.Duration,
+	logger *mylogger.MyLogger,
+) *Cached {
+	return &Cached{
+		geocoder:    geocoder,
+		storage:     storage,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		logger:      logger,
+	}
+}
+
+func (c *Cached) Name() string {
+	return "cached(" + c.geocoder.Name() + ")"
+}
+
+// get returns entry found in cache, entry can be negative.
+func (c *Cached) get(ctx context.Context, key string) (*models.GeocodeCacheEntry, bool) {
+	entry, err := c.storage.GetGeocodeCache(ctx, key)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFoundGeocodeCache) {
+			// cache is optimization, providers still can answer
+			c.logger.WithCtx(ctx).Warnw("Unable to get geocode cache", "address", key, "error", err)
+		}
+
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (c *Cached) set(ctx context.Context, entry *models.GeocodeCacheEntry) {
+	entry.Provider = c.geocoder.Name()
+	entry.ExpiresAt = time.Now().Add(c.ttl)
+
+	if entry.IsNotFound() {
+		entry.ExpiresAt = time.Now().Add(c.negativeTTL)
+	}
+
+	err := c.storage.SetGeocodeCache(ctx, entry)
+	if err != nil {
+		c.logger.WithCtx(ctx).Warnw("Unable to set geocode cache", "address", entry.Address, "error", err)
+	}
+}
+
+func (c *Cached) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	key := NormalizeAddress(address)
+	if key == "" {
+		return nil, ErrNotFound
+	}
+
+	if entry, ok := c.get(ctx, key); ok {
+		if entry.IsNotFound() {
+			return nil, fmt.Errorf("%w: cached %s", ErrNotFound, key)
+		}
+
+		return &Coordinates{Latitude: *entry.Latitude, Longitude: *entry.Longitude, Address: entry.Details}, nil
+	}
+
+	coordinates, err := c.geocoder.Geocode(ctx, address)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	entry := &models.GeocodeCacheEntry{Address: key} //nolint:exhaustruct
+	if coordinates != nil {
+		entry.Latitude = &coordinates.Latitude
+		entry.Longitude = &coordinates.Longitude
+		entry.Details = coordinates.Address
+	}
+
+	c.set(ctx, entry)
+
+	return coordinates, err
+}
+
+// Reverse caches points rounded to about a meter, pins dropped on map never match exactly.
+func (c *Cached) Reverse(ctx context.Context, latitude, longitude float64) (*models.AddressDetails, error) {
+	rawLatitude := strconv.FormatFloat(latitude, 'f', 5, 64)
+	rawLongitude := strconv.FormatFloat(longitude, 'f', 5, 64)
+	key := "reverse:" + rawLatitude + "," + rawLongitude
+
+	if entry, ok := c.get(ctx, key); ok {
+		if entry.IsNotFound() || entry.Details == nil {
+			return nil, fmt.Errorf("%w: cached %s", ErrNotFound, key)
+		}
+
+		return entry.Details, nil
+	}
+
+	details, err := c.geocoder.Reverse(ctx, latitude, longitude)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	entry := &models.GeocodeCacheEntry{Address: key} //nolint:exhaustruct
+	if details != nil {
+		entry.Latitude = &rawLatitude
+		entry.Longitude = &rawLongitude
+		entry.Details = details
+	}
+
+	c.set(ctx, entry)
+
+	return details, err
+}
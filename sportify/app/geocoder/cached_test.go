@@ -0,0 +1,131 @@
+package geocoder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/geocoder"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapCacheStorage map[string]models.GeocodeCacheEntry
+
+func (m mapCacheStorage) GetGeocodeCache(_ context.Context, address string) (*models.GeocodeCacheEntry, error) {
+	entry, ok := m[address]
+	if !ok || entry.ExpiresAt.Before(time.Now()) {
+		return nil, db.ErrNotFoundGeocodeCache
+	}
+
+	return &entry, nil
+}
+
+func (m mapCacheStorage) SetGeocodeCache(_ context.Context, entry *models.GeocodeCacheEntry) error {
+	m[entry.Address] = *entry
+
+	return nil
+}
+
+type countingGeocoder struct {
+	calls       int
+	coordinates *geocoder.Coordinates
+	details     *models.AddressDetails
+	err         error
+}
+
+func (g *countingGeocoder) Name() string {
+	return "counting"
+}
+
+func (g *countingGeocoder) Geocode(context.Context, string) (*geocoder.Coordinates, error) {
+	g.calls++
+
+	return g.coordinates, g.err
+}
+
+func (g *countingGeocoder) Reverse(context.Context, float64, float64) (*models.AddressDetails, error) {
+	g.calls++
+
+	return g.details, g.err
+}
+
+func TestCachedStoresFoundAddress(t *testing.T) {
+	t.Parallel()
+
+	storage := mapCacheStorage{}
+	provider := &countingGeocoder{coordinates: &geocoder.Coordinates{
+		Latitude:  "55.75",
+		Longitude: "37.61",
+		Address:   models.NewAddressDetails("Москва", "Воротынская улица", "9", ""),
+	}}
+	cached := geocoder.NewCached(provider, storage, time.Hour, time.Minute, mylogger.NewNop())
+
+	for range 2 {
+		coordinates, err := cached.Geocode(context.Background(), "г Москва, ул  Воротынская, д 9")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "55.75", coordinates.Latitude)
+		assert.Equal(t, "Москва, Воротынская улица, 9", coordinates.Address.Formatted)
+	}
+
+	assert.Equal(t, 1, provider.calls)
+	assert.Contains(t, storage, "москва, улица воротынская, 9")
+}
+
+func TestCachedStoresNotFoundAddress(t *testing.T) {
+	t.Parallel()
+
+	storage := mapCacheStorage{}
+	provider := &countingGeocoder{err: geocoder.ErrNotFound}
+	cached := geocoder.NewCached(provider, storage, time.Hour, time.Minute, mylogger.NewNop())
+
+	for range 2 {
+		_, err := cached.Geocode(context.Background(), "нет такого адреса")
+		assert.ErrorIs(t, err, geocoder.ErrNotFound)
+	}
+
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestCachedDoesNotStoreProviderFailure(t *testing.T) {
+	t.Parallel()
+
+	storage := mapCacheStorage{}
+	provider := &countingGeocoder{err: errors.New("timeout")}
+	cached := geocoder.NewCached(provider, storage, time.Hour, time.Minute, mylogger.NewNop())
+
+	for range 2 {
+		_, err := cached.Geocode(context.Background(), "Москва")
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 2, provider.calls)
+	assert.Empty(t, storage)
+}
+
+func TestCachedReverseRoundsPoint(t *testing.T) {
+	t.Parallel()
+
+	storage := mapCacheStorage{}
+	provider := &countingGeocoder{details: models.NewAddressDetails("Москва", "Бауманская улица", "5", "Бауманская")}
+	cached := geocoder.NewCached(provider, storage, time.Hour, time.Minute, mylogger.NewNop())
+
+	for _, latitude := range []float64{55.772301, 55.772304} {
+		details, err := cached.Reverse(context.Background(), latitude, 37.678901)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "Бауманская", details.Metro)
+	}
+
+	assert.Equal(t, 1, provider.calls)
+	assert.Contains(t, storage, "reverse:55.77230,37.67890")
+}
@@ -0,0 +1,94 @@
+// Package geocoder turns addresses into coordinates and back using external
+// providers, static files and persistent cache.
+package geocoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
+)
+
+var ErrNotFound = errors.New("address not found")
+
+type Coordinates struct {
+	Latitude  string
+	Longitude string
+	// Address is nil when provider doesn't return address components.
+	Address *models.AddressDetails
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*Coordinates, error)
+	Reverse(ctx context.Context, latitude, longitude float64) (*models.AddressDetails, error)
+	Name() string
+}
+
+// NormalizeAddress makes cache key from address typed by user.
+func NormalizeAddress(address string) string {
+	address = reformat_url_open_map.ReformatURLOpenMap(strings.TrimSpace(address))
+
+	return strings.ToLower(strings.Join(strings.Fields(address), " "))
+}
+
+// Chain asks geocoders one by one and returns first found coordinates.
+type Chain struct {
+	geocoders []Geocoder
+}
+
+func NewChain(geocoders ...Geocoder) *Chain {
+	return &Chain{geocoders: geocoders}
+}
+
+func (c *Chain) Name() string {
+	names := make([]string, 0, len(c.geocoders))
+	for _, geocoder := range c.geocoders {
+		names = append(names, geocoder.Name())
+	}
+
+	return strings.Join(names, ",")
+}
+
+// firstFound returns ErrNotFound only when every geocoder didn't find address,
+// so temporary failures of one provider are not cached as missing address.
+func firstFound[T any](geocoders []Geocoder, call func(geocoder Geocoder) (T, error)) (T, error) {
+	var (
+		errs        []error
+		allNotFound = true
+		empty       T
+	)
+
+	for _, geocoder := range geocoders {
+		result, err := call(geocoder)
+		if err == nil {
+			return result, nil
+		}
+
+		if !errors.Is(err, ErrNotFound) {
+			allNotFound = false
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", geocoder.Name(), err))
+	}
+
+	if allNotFound {
+		return empty, fmt.Errorf("%w: %w", ErrNotFound, errors.Join(errs...))
+	}
+
+	return empty, errors.Join(errs...)
+}
+
+func (c *Chain) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	return firstFound(c.geocoders, func(geocoder Geocoder) (*Coordinates, error) {
+		return geocoder.Geocode(ctx, address)
+	})
+}
+
+func (c *Chain) Reverse(ctx context.Context, latitude, longitude float64) (*models.AddressDetails, error) {
+	return firstFound(c.geocoders, func(geocoder Geocoder) (*models.AddressDetails, error) {
+		return geocoder.Reverse(ctx, latitude, longitude)
+	})
+}
@@ -0,0 +1,159 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	nominatimSearchURL = "https://nominatim.openstreetmap.org/search" +
+		"?&limit=1&accept-language=ru-RU&countrycodes=RU&format=jsonv2&addressdetails=1"
+	nominatimReverseURL = "https://nominatim.openstreetmap.org/reverse" +
+		"?accept-language=ru-RU&format=jsonv2&addressdetails=1&zoom=18"
+)
+
+type nominatimAddress struct {
+	HouseNumber string `json:"house_number"`
+	Road        string `json:"road"`
+	Pedestrian  string `json:"pedestrian"`
+	City        string `json:"city"`
+	Town        string `json:"town"`
+	Village     string `json:"village"`
+}
+
+func (a *nominatimAddress) details() *models.AddressDetails {
+	city := firstNotEmpty(a.City, a.Town, a.Village)
+	street := firstNotEmpty(a.Road, a.Pedestrian)
+
+	// nominatim has no metro in address components
+	return models.NewAddressDetails(city, street, a.HouseNumber, "")
+}
+
+type nominatimPlace struct {
+	Latitude  string            `json:"lat"`
+	Longitude string            `json:"lon"`
+	Address   *nominatimAddress `json:"address"`
+	// Error is set by reverse when there is nothing at the point.
+	Error string `json:"error"`
+}
+
+func firstNotEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Nominatim is OpenStreetMap geocoder. Its usage policy allows
+// at most one request per second, so requests wait for limiter.
+type Nominatim struct {
+	httpClient *http.Client
+	userAgent  string
+	limiter    *rate.Limiter
+}
+
+func NewNominatim(httpClient *http.Client, userAgent string, requestsPerSecond float64) *Nominatim {
+	return &Nominatim{
+		httpClient: httpClient,
+		userAgent:  userAgent,
+		limiter:    rate.NewLimiter(rate.Limit(requestsPerSecond), 1),
+	}
+}
+
+func (n *Nominatim) Name() string {
+	return "nominatim"
+}
+
+func (n *Nominatim) request(ctx context.Context, rawURL string, params url.Values, result any) error {
+	err := n.limiter.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("to wait rate limiter: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("to new request: %w", err)
+	}
+
+	values := req.URL.Query()
+
+	for key := range params {
+		values.Set(key, params.Get(key))
+	}
+
+	req.URL.RawQuery = values.Encode()
+
+	req.Header.Set("User-Agent", n.userAgent)
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("to do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("to read body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return fmt.Errorf("to unmarshal body: %w", err)
+	}
+
+	return nil
+}
+
+func (n *Nominatim) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	var places []nominatimPlace
+
+	err := n.request(ctx, nominatimSearchURL, url.Values{"q": {address}}, &places)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(places) == 0 {
+		return nil, ErrNotFound
+	}
+
+	coordinates := &Coordinates{Latitude: places[0].Latitude, Longitude: places[0].Longitude} //nolint:exhaustruct
+	if places[0].Address != nil {
+		coordinates.Address = places[0].Address.details()
+	}
+
+	return coordinates, nil
+}
+
+func (n *Nominatim) Reverse(ctx context.Context, latitude, longitude float64) (*models.AddressDetails, error) {
+	var place nominatimPlace
+
+	err := n.request(ctx, nominatimReverseURL, url.Values{
+		"lat": {strconv.FormatFloat(latitude, 'f', -1, 64)},
+		"lon": {strconv.FormatFloat(longitude, 'f', -1, 64)},
+	}, &place)
+	if err != nil {
+		return nil, err
+	}
+
+	if place.Error != "" || place.Address == nil {
+		return nil, ErrNotFound
+	}
+
+	return place.Address.details(), nil
+}
@@ -0,0 +1,64 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+type staticAddress struct {
+	Address   string `json:"address"`
+	Latitude  string `json:"lat"`
+	Longitude string `json:"lon"`
+}
+
+// Static resolves addresses from JSON file like [{"address": "...", "lat": "55.75", "lon": "37.61"}].
+// It is useful for local development and for places providers can't find.
+type Static struct {
+	addresses map[string]Coordinates
+}
+
+func NewStatic(path string) (*Static, error) {
+	rawFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("to read static geocoder file: %w", err)
+	}
+
+	var addresses []staticAddress
+
+	err = json.Unmarshal(rawFile, &addresses)
+	if err != nil {
+		return nil, fmt.Errorf("to unmarshal static geocoder file: %w", err)
+	}
+
+	result := &Static{addresses: make(map[string]Coordinates, len(addresses))}
+
+	for _, address := range addresses {
+		result.addresses[NormalizeAddress(address.Address)] = Coordinates{
+			Latitude:  address.Latitude,
+			Longitude: address.Longitude,
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Static) Name() string {
+	return "static"
+}
+
+func (s *Static) Geocode(_ context.Context, address string) (*Coordinates, error) {
+	coordinates, ok := s.addresses[NormalizeAddress(address)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &coordinates, nil
+}
+
+func (s *Static) Reverse(context.Context, float64, float64) (*models.AddressDetails, error) {
+	return nil, ErrNotFound
+}
@@ -0,0 +1,198 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"golang.org/x/time/rate"
+)
+
+const yandexGeocodeURL = "https://geocode-maps.yandex.ru/1.x?lang=ru_RU&format=json"
+
+type yandexComponent struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type yandexGeoObject struct {
+	MetaDataProperty struct {
+		GeocoderMetaData struct {
+			Address struct {
+				Components []yandexComponent `json:"Components"`
+			} `json:"Address"`
+		} `json:"GeocoderMetaData"`
+	} `json:"metaDataProperty"`
+	Name  string `json:"name"`
+	Point struct {
+		Pos string `json:"pos"`
+	} `json:"Point"`
+}
+
+type responseYandexAPI struct {
+	Response struct {
+		GeoObjectCollection struct {
+			FeatureMember []struct {
+				GeoObject yandexGeoObject `json:"GeoObject"`
+			} `json:"featureMember"`
+		} `json:"GeoObjectCollection"`
+	} `json:"response"`
+}
+
+func (r *responseYandexAPI) first() (*yandexGeoObject, error) {
+	if len(r.Response.GeoObjectCollection.FeatureMember) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &r.Response.GeoObjectCollection.FeatureMember[0].GeoObject, nil
+}
+
+func (o *yandexGeoObject) coordinates() (*Coordinates, error) {
+	twoCoordinates := strings.Split(o.Point.Pos, " ")
+
+	if len(twoCoordinates) != 2 {
+		return nil, fmt.Errorf("format yandex coordinates: %s", o.Point.Pos)
+	}
+
+	// yandex returns "longitude latitude"
+	return &Coordinates{Latitude: twoCoordinates[1], Longitude: twoCoordinates[0]}, nil
+}
+
+func (o *yandexGeoObject) details() *models.AddressDetails {
+	var city, street, house string
+
+	for _, component := range o.MetaDataProperty.GeocoderMetaData.Address.Components {
+		switch component.Kind {
+		case "locality":
+			city = component.Name
+		case "street":
+			street = component.Name
+		case "house":
+			house = component.Name
+		}
+	}
+
+	return models.NewAddressDetails(city, street, house, "")
+}
+
+type Yandex struct {
+	httpClient *http.Client
+	apiKey     string
+	limiter    *rate.Limiter
+}
+
+func NewYandex(httpClient *http.Client, apiKey string, requestsPerSecond float64) *Yandex {
+	return &Yandex{
+		httpClient: httpClient,
+		apiKey:     apiKey,
+		limiter:    rate.NewLimiter(rate.Limit(requestsPerSecond), 1),
+	}
+}
+
+func (y *Yandex) Name() string {
+	return "yandex"
+}
+
+func (y *Yandex) request(ctx context.Context, params url.Values) (*yandexGeoObject, error) {
+	err := y.limiter.Wait(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("to wait rate limiter: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, yandexGeocodeURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("to new request: %w", err)
+	}
+
+	values := req.URL.Query()
+
+	values.Add("apikey", y.apiKey)
+
+	for key := range params {
+		values.Set(key, params.Get(key))
+	}
+
+	req.URL.RawQuery = values.Encode()
+
+	resp, err := y.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("to do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("to read body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response responseYandexAPI
+
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("to unmarshal body: %w", err)
+	}
+
+	return response.first()
+}
+
+func yandexPoint(latitude, longitude string) string {
+	return longitude + "," + latitude
+}
+
+// nearestMetro is best effort, address is still useful without metro.
+func (y *Yandex) nearestMetro(ctx context.Context, point string) string {
+	metro, err := y.request(ctx, url.Values{"geocode": {point}, "kind": {"metro"}, "results": {"1"}})
+	if err != nil {
+		return ""
+	}
+
+	return metro.Name
+}
+
+func (y *Yandex) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	// search near Moscow first, most of events are there
+	geoObject, err := y.request(ctx, url.Values{
+		"geocode": {address},
+		"ll":      {"37.623150,55.752508"},
+		"spn":     {"6,6"},
+		"rspn":    {"1"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coordinates, err := geoObject.coordinates()
+	if err != nil {
+		return nil, err
+	}
+
+	coordinates.Address = geoObject.details()
+	coordinates.Address.Metro = y.nearestMetro(ctx, yandexPoint(coordinates.Latitude, coordinates.Longitude))
+
+	return coordinates, nil
+}
+
+func (y *Yandex) Reverse(ctx context.Context, latitude, longitude float64) (*models.AddressDetails, error) {
+	point := yandexPoint(strconv.FormatFloat(latitude, 'f', -1, 64), strconv.FormatFloat(longitude, 'f', -1, 64))
+
+	geoObject, err := y.request(ctx, url.Values{"geocode": {point}, "kind": {"house"}, "results": {"1"}})
+	if err != nil {
+		return nil, err
+	}
+
+	details := geoObject.details()
+	details.Metro = y.nearestMetro(ctx, point)
+
+	return details, nil
+}
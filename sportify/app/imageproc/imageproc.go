@@ -0,0 +1,181 @@
+// Package imageproc normalizes uploaded photos: turns them by EXIF, drops metadata and makes
+// variants of different sizes, every size is made in jpeg or png and in WebP.
+//
+// WebP is encoded by nativewebp, it is pure go and makes only lossless WebP, lossy encoder
+// needs libwebp and cgo, which are not in our image. Lossless WebP of photo is often larger
+// than jpeg, so it is kept only when it is smaller.
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Add webp format for upload
+)
+
+const (
+	// MaxSide and MaxPixels are checked before decoding, so small file with huge picture doesn't eat memory.
+	MaxSide   = 10000
+	MaxPixels = 40_000_000
+
+	jpegQuality = 85
+
+	ExtWebP = ".webp"
+)
+
+var (
+	ErrDecode   = errors.New("unable to decode image")
+	ErrTooLarge = fmt.Errorf("Изображение слишком большое, максимум %d пикселей по стороне и %d Мп",
+		MaxSide, MaxPixels/1_000_000)
+)
+
+type SizeName string
+
+const (
+	SizeThumbnail SizeName = "thumbnail"
+	SizeMedium    SizeName = "medium"
+	SizeFull      SizeName = "full"
+)
+
+type Size struct {
+	Name SizeName
+	// MaxSide is limit of the longest side, smaller images are not enlarged.
+	MaxSide int
+}
+
+// Sizes are ordered from the biggest one, every next variant is made from the previous.
+var Sizes = []Size{ //nolint:gochecknoglobals
+	{Name: SizeFull, MaxSide: 2048},     //nolint:mnd
+	{Name: SizeMedium, MaxSide: 1024},   //nolint:mnd
+	{Name: SizeThumbnail, MaxSide: 400}, //nolint:mnd
+}
+
+type Variant struct {
+	Size SizeName
+	// Ext is .jpg for opaque images and .png for images with transparency.
+	Ext     string
+	Content []byte
+	// WebP is the same picture in WebP, it is saved near Content with ExtWebP.
+	// It is nil if it is not smaller than Content.
+	WebP []byte
+}
+
+func checkConfig(file []byte) error {
+	config, _, err := image.DecodeConfig(bytes.NewReader(file))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	if config.Width > MaxSide || config.Height > MaxSide || config.Width*config.Height > MaxPixels {
+		return ErrTooLarge
+	}
+
+	return nil
+}
+
+// fit scales image down, so its the longest side is not bigger than maxSide.
+func fit(img *image.RGBA, maxSide int) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		width, height = maxSide, max(height*maxSide/width, 1)
+	} else {
+		width, height = max(width*maxSide/height, 1), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+// encode writes image again, encoders of go don't write metadata, so EXIF with GPS is dropped.
+func encode(img *image.RGBA) (string, []byte, error) {
+	buf := &bytes.Buffer{}
+
+	if img.Opaque() {
+		err := jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality})
+		if err != nil {
+			return "", nil, fmt.Errorf("to encode jpeg: %w", err)
+		}
+
+		return ".jpg", buf.Bytes(), nil
+	}
+
+	err := png.Encode(buf, img)
+	if err != nil {
+		return "", nil, fmt.Errorf("to encode png: %w", err)
+	}
+
+	return ".png", buf.Bytes(), nil
+}
+
+func encodeWebP(img *image.RGBA) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	err := nativewebp.Encode(buf, img, nil)
+	if err != nil {
+		return nil, fmt.Errorf("to encode webp: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Process makes variants of uploaded image for every size from Sizes.
+func Process(file []byte) ([]Variant, error) {
+	err := checkConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, format, err := image.Decode(bytes.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	bounds := decoded.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), decoded, bounds.Min, draw.Src)
+
+	// image is made smaller before turning, the longest side is the same after it
+	img = fit(img, Sizes[0].MaxSide)
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(file))
+	}
+
+	result := make([]Variant, 0, len(Sizes))
+
+	for _, size := range Sizes {
+		img = fit(img, size.MaxSide)
+
+		ext, content, err := encode(img)
+		if err != nil {
+			return nil, err
+		}
+
+		webp, err := encodeWebP(img)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(webp) >= len(content) {
+			webp = nil
+		}
+
+		result = append(result, Variant{Size: size.Name, Ext: ext, Content: content, WebP: webp})
+	}
+
+	return result, nil
+}
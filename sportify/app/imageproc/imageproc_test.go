@@ -0,0 +1,125 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+)
+
+// withOrientation adds EXIF with orientation to JPEG.
+func withOrientation(file []byte, orientation uint16) []byte {
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	entry := make([]byte, ifdEntryLen)
+	binary.LittleEndian.PutUint16(entry[0:2], tagOrientation)
+	binary.LittleEndian.PutUint16(entry[2:4], typeShort)
+	binary.LittleEndian.PutUint32(entry[4:8], 1)
+	binary.LittleEndian.PutUint16(entry[8:10], orientation)
+	tiff = append(append(tiff, entry...), 0, 0, 0, 0)
+
+	segment := append(append([]byte{}, exifHeader...), tiff...)
+	app1 := []byte{0xFF, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:4], uint16(len(segment)+2))
+
+	result := append([]byte{}, file[:2]...)
+	result = append(result, app1...)
+	result = append(result, segment...)
+
+	return append(result, file[2:]...)
+}
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, jpeg.Encode(buf, img, nil))
+
+	return buf.Bytes()
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	t.Parallel()
+
+	file := encodeJPEG(t, 4, 2)
+
+	assert.Equal(t, 1, jpegOrientation(file))
+	assert.Equal(t, 6, jpegOrientation(withOrientation(file, 6)))
+	assert.Equal(t, 1, jpegOrientation(withOrientation(file, 42)))
+	assert.Equal(t, 1, jpegOrientation([]byte("not jpeg")))
+}
+
+func TestOrient(t *testing.T) {
+	t.Parallel()
+
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	// red left pixel goes up when photo is turned clockwise
+	rotated := orient(src, 6)
+	assert.Equal(t, image.Rect(0, 0, 1, 2), rotated.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, rotated.RGBAAt(0, 0))
+
+	rotated = orient(src, 8)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, rotated.RGBAAt(0, 1))
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	variants, err := Process(withOrientation(encodeJPEG(t, 3000, 1000), 6))
+	assert.NoError(t, err)
+	assert.Len(t, variants, len(Sizes))
+
+	for i, variant := range variants {
+		assert.Equal(t, Sizes[i].Name, variant.Size)
+		assert.Equal(t, ".jpg", variant.Ext)
+		assert.Equal(t, 1, jpegOrientation(variant.Content))
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(variant.Content))
+		assert.NoError(t, err)
+		assert.Equal(t, Sizes[i].MaxSide, config.Height)
+		assert.Less(t, config.Width, config.Height)
+
+		// lossless WebP of photo is larger than jpeg
+		assert.Nil(t, variant.WebP)
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, image.NewNRGBA(image.Rect(0, 0, 100, 50))))
+
+	variants, err = Process(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, ".png", variants[0].Ext)
+
+	// flat picture is compressed well by lossless WebP, so it is kept
+	for _, variant := range variants {
+		assert.Less(t, len(variant.WebP), len(variant.Content))
+
+		webpConfig, err := webp.DecodeConfig(bytes.NewReader(variant.WebP))
+		assert.NoError(t, err)
+		assert.Equal(t, 100, webpConfig.Width)
+		assert.Equal(t, 50, webpConfig.Height)
+	}
+
+	buf.Reset()
+	assert.NoError(t, png.Encode(buf, image.NewGray(image.Rect(0, 0, MaxSide+1, 1))))
+
+	_, err = Process(buf.Bytes())
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = Process([]byte("not image"))
+	assert.ErrorIs(t, err, ErrDecode)
+}
@@ -0,0 +1,145 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const (
+	markerSOS        = 0xDA
+	markerEOI        = 0xD9
+	markerAPP1       = 0xE1
+	tagOrientation   = 0x0112
+	typeShort        = 3
+	ifdEntryLen      = 12
+	tiffHeaderLen    = 8
+	orientationUpper = 8
+)
+
+var exifHeader = []byte("Exif\x00\x00") //nolint:gochecknoglobals
+
+// jpegOrientation reads orientation of photo from EXIF, 1 is returned when photo is not rotated
+// or EXIF is missing or broken.
+func jpegOrientation(file []byte) int {
+	if len(file) < 4 || file[0] != 0xFF || file[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(file); {
+		if file[i] != 0xFF {
+			return 1
+		}
+
+		marker := file[i+1]
+		if marker == markerSOS || marker == markerEOI {
+			return 1
+		}
+
+		segmentLen := int(binary.BigEndian.Uint16(file[i+2 : i+4]))
+		if segmentLen < 2 || i+2+segmentLen > len(file) {
+			return 1
+		}
+
+		segment := file[i+4 : i+2+segmentLen]
+		if marker == markerAPP1 && bytes.HasPrefix(segment, exifHeader) {
+			return tiffOrientation(segment[len(exifHeader):])
+		}
+
+		i += 2 + segmentLen
+	}
+
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < tiffHeaderLen {
+		return 1
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < tiffHeaderLen || offset+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[offset : offset+2]))
+
+	for i := range count {
+		entryStart := offset + 2 + i*ifdEntryLen
+		if entryStart+ifdEntryLen > len(tiff) {
+			return 1
+		}
+
+		entry := tiff[entryStart : entryStart+ifdEntryLen]
+		if order.Uint16(entry[0:2]) != tagOrientation || order.Uint16(entry[2:4]) != typeShort {
+			continue
+		}
+
+		orientation := int(order.Uint16(entry[8:10]))
+		if orientation < 1 || orientation > orientationUpper {
+			return 1
+		}
+
+		return orientation
+	}
+
+	return 1
+}
+
+// orient turns image, so it looks like camera showed it, orientation is value of EXIF tag.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > orientationUpper {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// orientations from 5 to 8 swap sides
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 { //nolint:mnd
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := range dstHeight {
+		for x := range dstWidth {
+			var srcX, srcY int
+
+			switch orientation {
+			case 2: //nolint:mnd
+				srcX, srcY = width-1-x, y
+			case 3: //nolint:mnd
+				srcX, srcY = width-1-x, height-1-y
+			case 4: //nolint:mnd
+				srcX, srcY = x, height-1-y
+			case 5: //nolint:mnd
+				srcX, srcY = y, x
+			case 6: //nolint:mnd
+				srcX, srcY = y, height-1-x
+			case 7: //nolint:mnd
+				srcX, srcY = width-1-y, height-1-x
+			default:
+				srcX, srcY = width-1-y, x
+			}
+
+			srcStart := src.PixOffset(bounds.Min.X+srcX, bounds.Min.Y+srcY)
+			dstStart := dst.PixOffset(x, y)
+			copy(dst.Pix[dstStart:dstStart+4], src.Pix[srcStart:srcStart+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+type MapStorage interface {
+	CountEvents(ctx context.Context, filterParams *models.FilterParams) (int, error)
+	FindMapClusters(ctx context.Context, filterParams *models.FilterParams, cellSize float64) ([]models.MapCluster, error)
+}
+
+var _ MapStorage = (*db.PostgresStorage)(nil)
+
+const (
+	MaxMapZoom = 22
+	// mapMaxEvents is how many events are shown without clustering.
+	mapMaxEvents = 300
+	// mapClusterMaxZoom is zoom of streets, events are never clustered there.
+	mapClusterMaxZoom = 15
+	// mapCellsPerTile sets cluster size: map tile of 256px is split to cells of 64px.
+	mapCellsPerTile = 4
+)
+
+var ErrValidationMapRequest = errors.New("Для карты нужны bbox и zoom от 0 до 22")
+
+// mapCellSize returns size of cluster cell in degrees, on zoom 0 whole world is one tile.
+func mapCellSize(zoom int) float64 {
+	return 360 / math.Exp2(float64(zoom)) / mapCellsPerTile //nolint:mnd
+}
+
+// applyAddressSearch turns address from filters into search around point,
+// explicit lat and lon have priority.
+func (a *App) applyAddressSearch(ctx context.Context, filterParams *models.FilterParams) {
+	if filterParams.Address == "" || filterParams.Latitude != nil {
+		return
+	}
+
+	coordinates, err := a.geocoder.Geocode(ctx, filterParams.Address)
+	if err != nil {
+		a.logger.WithCtx(ctx).Errorf("to find address from=%s FindEvents: %v", filterParams.Address, err)
+		return
+	}
+
+	latitude, errLatitude := strconv.ParseFloat(coordinates.Latitude, 64)
+	longitude, errLongitude := strconv.ParseFloat(coordinates.Longitude, 64)
+
+	if errLatitude != nil || errLongitude != nil {
+		a.logger.WithCtx(ctx).Errorf("to parse coordinates of address=%s: %v", filterParams.Address,
+			errors.Join(errLatitude, errLongitude))
+		return
+	}
+
+	filterParams.Latitude = &latitude
+	filterParams.Longitude = &longitude
+}
+
+func (a *App) FindMapEvents(ctx context.Context, filterParams *models.FilterParams, zoom int) (*models.MapEvents, error) {
+	if filterParams.BBox == nil || zoom < 0 || zoom > MaxMapZoom {
+		return nil, ErrValidationMapRequest
+	}
+
+	a.applyAddressSearch(ctx, filterParams)
+
+	if filterParams.OrderBy == models.OrderByDistance && filterParams.Latitude == nil {
+		return nil, ErrDistanceWithoutPoint
+	}
+
+	// map shows all events of bbox at once, it has no pages
+	filterParams.Limit = 0
+	filterParams.Cursor = nil
+
+	count, err := a.mapStorage.CountEvents(ctx, filterParams)
+	if err != nil {
+		return nil, fmt.Errorf("to count events: %w", err)
+	}
+
+	result := &models.MapEvents{Events: []models.ShortEvent{}, Clusters: []models.MapCluster{}}
+
+	if zoom >= mapClusterMaxZoom || count <= mapMaxEvents {
+		result.Events, err = a.eventStorage.FindEvents(ctx, filterParams)
+		if err != nil {
+			return nil, fmt.Errorf("to find events: %w", err)
+		}
+
+		return result, nil
+	}
+
+	result.Clusters, err = a.mapStorage.FindMapClusters(ctx, filterParams, mapCellSize(zoom))
+	if err != nil {
+		return nil, fmt.Errorf("to find map clusters: %w", err)
+	}
+
+	return result, nil
+}
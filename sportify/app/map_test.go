@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/geocoder"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapCellSize(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 90.0, mapCellSize(0), 1e-9)
+	assert.InDelta(t, 45.0, mapCellSize(1), 1e-9)
+	// about 1 km near Moscow
+	assert.InDelta(t, 0.0055, mapCellSize(14), 1e-4)
+}
+
+type failingGeocoder struct {
+	Geocoder
+}
+
+func (failingGeocoder) Geocode(context.Context, string) (*geocoder.Coordinates, error) {
+	return nil, errors.New("address is not found")
+}
+
+func TestFindMapEventsDistanceWithoutPoint(t *testing.T) {
+	t.Parallel()
+
+	// storages are not set, search must stop before them
+	a := &App{geocoder: failingGeocoder{}, logger: mylogger.NewNop()} //nolint:exhaustruct
+
+	filterParams := &models.FilterParams{ //nolint:exhaustruct
+		BBox:    &models.BBox{MinLatitude: 55.5, MinLongitude: 37.3, MaxLatitude: 56, MaxLongitude: 37.9},
+		Address: "нет такого адреса",
+		OrderBy: models.OrderByDistance,
+	}
+
+	_, err := a.FindMapEvents(context.Background(), filterParams, 16)
+	assert.ErrorIs(t, err, ErrDistanceWithoutPoint)
+}
@@ -0,0 +1,331 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type NotificationStorage interface {
+	CreateNotifications(ctx context.Context, notifications []models.Notification) error
+	GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	GetNotificationPreferences(
+		ctx context.Context,
+		userIDs []uuid.UUID,
+		kind models.NotificationKind,
+	) (map[uuid.UUID]models.NotificationPreference, error)
+	GetUsersTgIDs(ctx context.Context, userIDs []uuid.UUID) ([]int64, error)
+	GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+	SetNotificationPreferences(ctx context.Context, userID uuid.UUID, preferences []models.NotificationPreference) error
+}
+
+var _ NotificationStorage = (*db.PostgresStorage)(nil)
+
+const notifyTimeout = time.Minute
+
+var (
+	ErrValidationNotifications          = fmt.Errorf("limit уведомлений должен быть от 1 до %d", models.MaxNotificationsLimit)
+	ErrValidationNotificationPreference = errors.New("Неправильные настройки уведомлений")
+)
+
+// eventTitle is short name of event in notifications: "Волейбол 08.03 20:00".
+func eventTitle(event *models.ShortEvent) string {
+	sportType, ok := models.EnToRuSportType(event.SportType)
+	if !ok {
+		sportType = string(event.SportType)
+	}
+
+	return fmt.Sprintf("%s %s", sportType, event.DateAndTime.StartTime.Format("02.01 15:04"))
+}
+
+// storeNotifications saves in-app notifications allowed by preferences of every user
+// and returns telegram ids of users who want the message in telegram too.
+func (a *App) storeNotifications(
+	ctx context.Context,
+	kind models.NotificationKind,
+	userIDs []uuid.UUID,
+	eventID *uuid.UUID,
+	text string,
+) ([]int64, error) {
+	preferences, err := a.notificationStorage.GetNotificationPreferences(ctx, userIDs, kind)
+	if err != nil {
+		return nil, fmt.Errorf("to get notification preferences: %w", err)
+	}
+
+	notifications := make([]models.Notification, 0, len(userIDs))
+	telegramUserIDs := make([]uuid.UUID, 0, len(userIDs))
+
+	for _, userID := range userIDs {
+		preference, ok := preferences[userID]
+		if !ok {
+			preference = models.DefaultNotificationPreference(kind)
+		}
+
+		if preference.InApp {
+			notifications = append(notifications, models.Notification{
+				ID:        uuid.New(),
+				UserID:    userID,
+				Kind:      kind,
+				EventID:   eventID,
+				Text:      text,
+				ReadAt:    nil,
+				CreatedAt: time.Now(),
+			})
+		}
+
+		if preference.Telegram {
+			telegramUserIDs = append(telegramUserIDs, userID)
+		}
+	}
+
+	if len(notifications) > 0 {
+		err = a.notificationStorage.CreateNotifications(ctx, notifications)
+		if err != nil {
+			return nil, fmt.Errorf("to create notifications: %w", err)
+		}
+	}
+
+	if len(telegramUserIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	tgUserIDs, err := a.notificationStorage.GetUsersTgIDs(ctx, telegramUserIDs)
+	if err != nil {
+		return nil, fmt.Errorf("to get tg ids: %w", err)
+	}
+
+	return tgUserIDs, nil
+}
+
+// deliverNotification sends notification to channels allowed by preferences of every user.
+func (a *App) deliverNotification(
+	ctx context.Context,
+	kind models.NotificationKind,
+	userIDs []uuid.UUID,
+	eventID *uuid.UUID,
+	text string,
+) error {
+	tgUserIDs, err := a.storeNotifications(ctx, kind, userIDs, eventID, text)
+	if err != nil {
+		return err
+	}
+
+	if len(tgUserIDs) > 0 {
+		err = a.botAPI.SendMessage(ctx, models.MessageBotRequest{TgUserIDs: tgUserIDs, Text: text})
+		if err != nil {
+			return fmt.Errorf("to send message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// notify delivers notification in background, action of user doesn't wait for bot.
+func (a *App) notify(
+	ctx context.Context,
+	kind models.NotificationKind,
+	userIDs []uuid.UUID,
+	eventID *uuid.UUID,
+	text string,
+) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if pan := recover(); pan != nil {
+				a.logger.Errorf("panic: %v", pan)
+			}
+		}()
+
+		err := a.deliverNotification(ctx, kind, userIDs, eventID, text)
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to deliver notification", "kind", kind, "error", err)
+		}
+	}()
+}
+
+func participantsExcept(event *models.ShortEvent, exceptUserID uuid.UUID) []uuid.UUID {
+	userIDs := make([]uuid.UUID, 0, len(event.Subscribers))
+
+	for _, subscriberID := range event.Subscribers {
+		if subscriberID != exceptUserID {
+			userIDs = append(userIDs, subscriberID)
+		}
+	}
+
+	return userIDs
+}
+
+// notifyParticipants notifies everyone subscribed to event except user who made change.
+func (a *App) notifyParticipants(
+	ctx context.Context,
+	kind models.NotificationKind,
+	event *models.ShortEvent,
+	exceptUserID uuid.UUID,
+	text string,
+) {
+	a.notify(ctx, kind, participantsExcept(event, exceptUserID), &event.ID, text)
+}
+
+// notifyEventChanges tells participants what was edited. Telegram message goes with update
+// of event post through TgUserIDsToNotify, so post is updated in background too.
+func (a *App) notifyEventChanges(
+	ctx context.Context,
+	event *models.ShortEvent,
+	exceptUserID uuid.UUID,
+	text string,
+) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if pan := recover(); pan != nil {
+				a.logger.Errorf("panic: %v", pan)
+			}
+		}()
+
+		var tgUserIDs []int64
+
+		userIDs := participantsExcept(event, exceptUserID)
+		if len(userIDs) > 0 {
+			var err error
+
+			tgUserIDs, err = a.storeNotifications(ctx, models.NotificationEventUpdated, userIDs, &event.ID, text)
+			if err != nil {
+				a.logger.WithCtx(ctx).Warnw("Unable to store notifications",
+					"kind", models.NotificationEventUpdated, "error", err)
+			}
+		}
+
+		a.onEventUpdate(ctx, event.ID, &models.MessageBotRequest{TgUserIDs: tgUserIDs, Text: text})
+	}()
+}
+
+// onSubscriptionChanged tells creator about participants and full event.
+func (a *App) onSubscriptionChanged(
+	ctx context.Context,
+	userID uuid.UUID,
+	subscribe bool,
+	response *models.ResponseSubscribeEvent,
+) {
+	event, err := a.eventStorage.GetEvent(ctx, response.ID)
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to get event", "event_id", response.ID, "error", err)
+		return
+	}
+
+	if event.CreatorID == userID {
+		return
+	}
+
+	creator := []uuid.UUID{event.CreatorID}
+	title := eventTitle(&event.ShortEvent)
+
+	if !subscribe {
+		a.notify(ctx, models.NotificationParticipantLeft, creator, &event.ID,
+			fmt.Sprintf("Участник отказался от события «%s»", title))
+
+		return
+	}
+
+	a.notify(ctx, models.NotificationParticipantJoined, creator, &event.ID,
+		fmt.Sprintf("Новый участник события «%s»", title))
+
+	if response.Capacity != nil && response.Busy >= *response.Capacity {
+		a.notify(ctx, models.NotificationEventFull, creator, &event.ID,
+			fmt.Sprintf("Все места на событие «%s» заняты", title))
+	}
+}
+
+func (a *App) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) (
+	*models.ResponseNotifications, error,
+) {
+	if limit < 1 || limit > models.MaxNotificationsLimit {
+		return nil, ErrValidationNotifications
+	}
+
+	notifications, err := a.notificationStorage.GetNotifications(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to get notifications: %w", err)
+	}
+
+	unreadCount, err := a.notificationStorage.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to count unread notifications: %w", err)
+	}
+
+	return &models.ResponseNotifications{Notifications: notifications, UnreadCount: unreadCount}, nil
+}
+
+func (a *App) MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+
+	err := a.notificationStorage.MarkNotificationsRead(ctx, userID, ids)
+	if err != nil {
+		return fmt.Errorf("to mark notifications read: %w", err)
+	}
+
+	return nil
+}
+
+// GetNotificationPreferences returns preferences for every kind, defaults are filled in.
+func (a *App) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (
+	[]models.NotificationPreference, error,
+) {
+	saved, err := a.notificationStorage.GetUserNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to get notification preferences: %w", err)
+	}
+
+	byKind := map[models.NotificationKind]models.NotificationPreference{}
+	for _, preference := range saved {
+		byKind[preference.Kind] = preference
+	}
+
+	result := make([]models.NotificationPreference, 0, len(models.NotificationKinds()))
+
+	for _, kind := range models.NotificationKinds() {
+		preference, ok := byKind[kind]
+		if !ok {
+			preference = models.DefaultNotificationPreference(kind)
+		}
+
+		result = append(result, preference)
+	}
+
+	return result, nil
+}
+
+func (a *App) SetNotificationPreferences(
+	ctx context.Context,
+	userID uuid.UUID,
+	preferences []models.NotificationPreference,
+) ([]models.NotificationPreference, error) {
+	err := models.ValidNotificationPreferences(preferences)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationNotificationPreference, err)
+	}
+
+	err = a.notificationStorage.SetNotificationPreferences(ctx, userID, preferences)
+	if err != nil {
+		return nil, fmt.Errorf("to set notification preferences: %w", err)
+	}
+
+	return a.GetNotificationPreferences(ctx, userID)
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNotificationStorage struct {
+	NotificationStorage
+	preferences   map[uuid.UUID]models.NotificationPreference
+	tgIDs         map[uuid.UUID]int64
+	created       []models.Notification
+	tgIDsRequests [][]uuid.UUID
+}
+
+func (s *fakeNotificationStorage) GetNotificationPreferences(
+	context.Context,
+	[]uuid.UUID,
+	models.NotificationKind,
+) (map[uuid.UUID]models.NotificationPreference, error) {
+	return s.preferences, nil
+}
+
+func (s *fakeNotificationStorage) CreateNotifications(_ context.Context, notifications []models.Notification) error {
+	s.created = append(s.created, notifications...)
+
+	return nil
+}
+
+func (s *fakeNotificationStorage) GetUsersTgIDs(_ context.Context, userIDs []uuid.UUID) ([]int64, error) {
+	s.tgIDsRequests = append(s.tgIDsRequests, userIDs)
+
+	result := make([]int64, 0, len(userIDs))
+
+	for _, userID := range userIDs {
+		if tgID, ok := s.tgIDs[userID]; ok {
+			result = append(result, tgID)
+		}
+	}
+
+	return result, nil
+}
+
+type recordingBotAPI struct {
+	BotAPI
+	messages []models.MessageBotRequest
+}
+
+func (b *recordingBotAPI) SendMessage(_ context.Context, messageRequest models.MessageBotRequest) error {
+	b.messages = append(b.messages, messageRequest)
+
+	return nil
+}
+
+func TestDeliverNotification(t *testing.T) {
+	t.Parallel()
+
+	// byDefault has no saved preferences, onlyInApp turned telegram off, onlyTg turned in-app off,
+	// noTg wants telegram but has no telegram account
+	byDefault, onlyInApp, onlyTg, noTg := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	kind := models.NotificationEventCancelled
+
+	storage := &fakeNotificationStorage{ //nolint:exhaustruct
+		preferences: map[uuid.UUID]models.NotificationPreference{
+			onlyInApp: {Kind: kind, InApp: true, Telegram: false},
+			onlyTg:    {Kind: kind, InApp: false, Telegram: true},
+		},
+		tgIDs: map[uuid.UUID]int64{byDefault: 1, onlyInApp: 2, onlyTg: 3},
+	}
+	botAPI := &recordingBotAPI{}                            //nolint:exhaustruct
+	a := &App{notificationStorage: storage, botAPI: botAPI} //nolint:exhaustruct
+
+	eventID := uuid.New()
+	err := a.deliverNotification(context.Background(), kind, []uuid.UUID{byDefault, onlyInApp, onlyTg, noTg},
+		&eventID, "Игра отменена")
+	assert.NoError(t, err)
+
+	inAppUserIDs := make([]uuid.UUID, 0, len(storage.created))
+	for _, notification := range storage.created {
+		inAppUserIDs = append(inAppUserIDs, notification.UserID)
+		assert.Equal(t, kind, notification.Kind)
+		assert.Equal(t, &eventID, notification.EventID)
+	}
+
+	assert.Equal(t, []uuid.UUID{byDefault, onlyInApp, noTg}, inAppUserIDs)
+
+	// telegram ids of all recipients are got by one request
+	assert.Equal(t, [][]uuid.UUID{{byDefault, onlyTg, noTg}}, storage.tgIDsRequests)
+	assert.Equal(t, []models.MessageBotRequest{{TgUserIDs: []int64{1, 3}, Text: "Игра отменена"}}, botAPI.messages)
+}
+
+func TestDeliverNotificationWithoutTelegram(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	kind := models.NotificationEventCancelled
+
+	storage := &fakeNotificationStorage{ //nolint:exhaustruct
+		preferences: map[uuid.UUID]models.NotificationPreference{
+			userID: {Kind: kind, InApp: true, Telegram: false},
+		},
+	}
+	botAPI := &recordingBotAPI{}                            //nolint:exhaustruct
+	a := &App{notificationStorage: storage, botAPI: botAPI} //nolint:exhaustruct
+
+	assert.NoError(t, a.deliverNotification(context.Background(), kind, []uuid.UUID{userID}, nil, "Игра отменена"))
+	assert.Len(t, storage.created, 1)
+	assert.Empty(t, storage.tgIDsRequests)
+	assert.Empty(t, botAPI.messages)
+}
+
+func TestParticipantsExcept(t *testing.T) {
+	t.Parallel()
+
+	creatorID, first, second := uuid.New(), uuid.New(), uuid.New()
+	event := &models.ShortEvent{Subscribers: []uuid.UUID{first, creatorID, second}} //nolint:exhaustruct
+
+	assert.Equal(t, []uuid.UUID{first, second}, participantsExcept(event, creatorID))
+	assert.Empty(t, participantsExcept(&models.ShortEvent{}, creatorID)) //nolint:exhaustruct
+}
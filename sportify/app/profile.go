@@ -27,10 +27,25 @@ func (a *App) UpdateProfile(ctx context.Context, userID uuid.UUID, reqUpdate mod
 		return fmt.Errorf("%w: %w", ErrValidationRequestUpdateProfile, err)
 	}
 
+	before, err := a.authStorage.GetUserFullByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("to get user: %w", err)
+	}
+
 	err = a.authStorage.UpdateProfile(ctx, userID, reqUpdate)
 	if err != nil {
 		return fmt.Errorf("to update profile: %w", err)
 	}
 
+	a.audit(ctx, &models.AuditRecord{ //nolint:exhaustruct
+		EntityType: models.AuditEntityProfile,
+		EntityID:   userID,
+		Action:     models.AuditActionUpdate,
+		ActorID:    &userID,
+		Source:     models.AuditSourceSite,
+		Before:     auditJSON(models.MapUserFullToProfileAPI(a.urlPrefixFile, userID, before)),
+		After:      auditJSON(reqUpdate),
+	})
+
 	return nil
 }
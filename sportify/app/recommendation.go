@@ -0,0 +1,324 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type RecommendationStorage interface {
+	FindCoAttendees(ctx context.Context, userID uuid.UUID, minTogether int) ([]uuid.UUID, error)
+}
+
+var _ RecommendationStorage = (*db.PostgresStorage)(nil)
+
+const (
+	DefaultRecommendationsLimit = 20
+	MaxRecommendationsLimit     = 100
+	// recommendationCandidates are the nearest upcoming events which are scored.
+	recommendationCandidates = 500
+	recommendationDays       = 14
+	// recommendationHistory is how many past events describe habits of user.
+	recommendationHistory = 100
+	// friendsMinTogether is how many events users should attend together to be friends.
+	friendsMinTogether  = 2
+	friendsForFullScore = 3
+	// nearbyDistanceM gives reason nearby, score of distance falls to zero at maxDistanceM.
+	nearbyDistanceM = 3000
+	maxDistanceM    = 15000
+	// usualHoursWindow is how far in hours start may be from start of attended events.
+	usualHoursWindow = 1
+	// usualShare is share of attended events which makes weekday or time usual.
+	usualShare   = 0.25
+	earthRadiusM = 6371000
+)
+
+var ErrValidationRecommendations = fmt.Errorf("limit рекомендаций должен быть от 1 до %d", MaxRecommendationsLimit)
+
+func parseCoordinate(raw *string) (float64, bool) {
+	if raw == nil {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(*raw, 64)
+
+	return value, err == nil
+}
+
+// distanceM is haversine distance between two points in meters.
+func distanceM(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	toRad := math.Pi / 180 //nolint:mnd
+	dLatitude := (latitude2 - latitude1) * toRad
+	dLongitude := (longitude2 - longitude1) * toRad
+
+	h := math.Pow(math.Sin(dLatitude/2), 2) + //nolint:mnd
+		math.Cos(latitude1*toRad)*math.Cos(latitude2*toRad)*math.Pow(math.Sin(dLongitude/2), 2) //nolint:mnd
+
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(h)) //nolint:mnd
+}
+
+func isoWeekday(date time.Time) int {
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		return 7 //nolint:mnd
+	}
+
+	return weekday
+}
+
+// levelScore is 1 if event has level near usual one, 0.5 for events without level, they are open to everyone.
+func levelScore(levels []models.GameLevel, usual *models.GameLevel) float64 {
+	if usual == nil {
+		return 0
+	}
+
+	if len(levels) == 0 {
+		return 0.5 //nolint:mnd
+	}
+
+	usualRank, _ := models.GameLevelRank(*usual)
+
+	for _, level := range levels {
+		rank, ok := models.GameLevelRank(level)
+		// one step up or down is still comfortable
+		if ok && rank >= usualRank-1 && rank <= usualRank+1 {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func freePlacesScore(event *models.ShortEvent) float64 {
+	if event.Capacity == nil || *event.Capacity <= 0 {
+		return 1
+	}
+
+	return math.Max(0, float64(*event.Capacity-event.Busy)/float64(*event.Capacity))
+}
+
+func timeOfDayShare(profile *models.RecommendationProfile, hour int) float64 {
+	count := 0
+	for usualHour, hourCount := range profile.Hours {
+		if usualHour >= hour-usualHoursWindow && usualHour <= hour+usualHoursWindow {
+			count += hourCount
+		}
+	}
+
+	return float64(count) / float64(profile.AttendedCount)
+}
+
+// scoreEvent sums signals multiplied by weights, every strong signal adds reason.
+//
+//nolint:cyclop
+func (a *App) scoreEvent(profile *models.RecommendationProfile, event *models.ShortEvent) models.RecommendedEvent {
+	weights := a.recommendationWeights
+	result := models.RecommendedEvent{ShortEvent: *event, Score: 0, Reasons: []string{}}
+
+	add := func(weight, signal float64, reason string, strong bool) {
+		result.Score += weight * signal
+		if strong && weight > 0 {
+			result.Reasons = append(result.Reasons, reason)
+		}
+	}
+
+	if profile.SportTypes[event.SportType] {
+		add(weights.SportType, 1, models.ReasonSportType, true)
+	}
+
+	level := levelScore(event.GameLevels, profile.UsualLevel)
+	add(weights.Level, level, models.ReasonLevel, level == 1)
+
+	latitude, okLatitude := parseCoordinate(event.Latitude)
+	longitude, okLongitude := parseCoordinate(event.Longitude)
+
+	if profile.HomeLatitude != nil && okLatitude && okLongitude {
+		distance := distanceM(*profile.HomeLatitude, *profile.HomeLongitude, latitude, longitude)
+		add(weights.Distance, math.Max(0, 1-distance/maxDistanceM), models.ReasonNearby, distance <= nearbyDistanceM)
+	}
+
+	if profile.AttendedCount > 0 {
+		weekdayShare := float64(profile.Weekdays[isoWeekday(event.DateAndTime.Date)]) / float64(profile.AttendedCount)
+		add(weights.Weekday, weekdayShare, models.ReasonUsualWeekday, weekdayShare >= usualShare)
+
+		timeShare := timeOfDayShare(profile, event.DateAndTime.StartTime.Hour())
+		add(weights.TimeOfDay, timeShare, models.ReasonUsualTime, timeShare >= usualShare)
+	}
+
+	if event.VenueID != nil && profile.Venues[*event.VenueID] {
+		add(weights.Venue, 1, models.ReasonFamiliarVenue, true)
+	}
+
+	friends := 0
+	for _, subscriberID := range event.Subscribers {
+		if profile.Friends[subscriberID] {
+			friends++
+		}
+	}
+
+	add(weights.Friends, math.Min(1, float64(friends)/friendsForFullScore), models.ReasonFriends, friends > 0)
+
+	freePlaces := freePlacesScore(event)
+	add(weights.FreePlaces, freePlaces, models.ReasonFreePlaces, freePlaces >= 0.5) //nolint:mnd
+
+	return result
+}
+
+// buildRecommendationProfile learns habits of user from profile and attended events.
+func (a *App) buildRecommendationProfile(
+	ctx context.Context,
+	userID uuid.UUID,
+	latitude, longitude *float64,
+	now time.Time,
+) (*models.RecommendationProfile, error) {
+	user, err := a.authStorage.GetUserFullByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to get user: %w", err)
+	}
+
+	attended, err := a.eventStorage.FindEvents(ctx, &models.FilterParams{ //nolint:exhaustruct
+		SubscriberIDs:  []uuid.UUID{userID},
+		DateExpression: squirrel.Lt{"start_time": now},
+		OrderBy:        models.OrderByStartTime,
+		SortOrder:      "desc",
+		Limit:          recommendationHistory,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to find attended events: %w", err)
+	}
+
+	friends, err := a.recommendationStorage.FindCoAttendees(ctx, userID, friendsMinTogether)
+	if err != nil {
+		return nil, fmt.Errorf("to find co attendees: %w", err)
+	}
+
+	profile := &models.RecommendationProfile{
+		SportTypes:    map[models.SportType]bool{},
+		UsualLevel:    nil,
+		HomeLatitude:  latitude,
+		HomeLongitude: longitude,
+		Weekdays:      map[int]int{},
+		Hours:         map[int]int{},
+		AttendedCount: len(attended),
+		Venues:        map[uuid.UUID]bool{},
+		Friends:       map[uuid.UUID]bool{},
+	}
+
+	for _, sportType := range user.SportTypes {
+		profile.SportTypes[sportType] = true
+	}
+
+	for _, friendID := range friends {
+		profile.Friends[friendID] = true
+	}
+
+	levels := map[models.GameLevel]int{}
+
+	var sumLatitude, sumLongitude, withCoordinates float64
+
+	for i := range attended {
+		event := &attended[i]
+
+		profile.SportTypes[event.SportType] = true
+		profile.Weekdays[isoWeekday(event.DateAndTime.Date)]++
+		profile.Hours[event.DateAndTime.StartTime.Hour()]++
+
+		if event.VenueID != nil {
+			profile.Venues[*event.VenueID] = true
+		}
+
+		for _, level := range event.GameLevels {
+			levels[level]++
+		}
+
+		eventLatitude, okLatitude := parseCoordinate(event.Latitude)
+		eventLongitude, okLongitude := parseCoordinate(event.Longitude)
+
+		if okLatitude && okLongitude {
+			sumLatitude += eventLatitude
+			sumLongitude += eventLongitude
+			withCoordinates++
+		}
+	}
+
+	// levels go in order, so lower level wins a tie
+	allLevels, _ := models.GameLevelsBetween("", "")
+	for _, level := range allLevels {
+		if levels[level] > 0 && (profile.UsualLevel == nil || levels[level] > levels[*profile.UsualLevel]) {
+			profile.UsualLevel = &level
+		}
+	}
+
+	// without point in request home is center of attended events
+	if profile.HomeLatitude == nil && withCoordinates > 0 {
+		homeLatitude, homeLongitude := sumLatitude/withCoordinates, sumLongitude/withCoordinates
+		profile.HomeLatitude = &homeLatitude
+		profile.HomeLongitude = &homeLongitude
+	}
+
+	return profile, nil
+}
+
+// RecommendEvents ranks upcoming events for user, latitude and longitude are optional position of user.
+func (a *App) RecommendEvents(
+	ctx context.Context,
+	userID uuid.UUID,
+	latitude, longitude *float64,
+	limit int,
+) ([]models.RecommendedEvent, error) {
+	if limit < 1 || limit > MaxRecommendationsLimit {
+		return nil, ErrValidationRecommendations
+	}
+
+	now := models.MoscowNow()
+
+	profile, err := a.buildRecommendationProfile(ctx, userID, latitude, longitude, now)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := a.eventStorage.FindEvents(ctx, &models.FilterParams{ //nolint:exhaustruct
+		DateExpression: squirrel.And{
+			squirrel.GtOrEq{"start_time": now},
+			squirrel.Lt{"start_time": now.AddDate(0, 0, recommendationDays)},
+		},
+		HasFreePlaces:       true,
+		ExcludeSubscriberID: &userID,
+		OrderBy:             models.OrderByStartTime,
+		SortOrder:           "asc",
+		Limit:               recommendationCandidates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to find candidates: %w", err)
+	}
+
+	result := make([]models.RecommendedEvent, 0, len(candidates))
+
+	for i := range candidates {
+		if candidates[i].CreatorID == userID {
+			continue
+		}
+
+		result = append(result, a.scoreEvent(profile, &candidates[i]))
+	}
+
+	// stable sort keeps earlier events first when scores are equal
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceM(t *testing.T) {
+	t.Parallel()
+
+	// one degree of meridian is 111.2 km
+	assert.InDelta(t, 111195, distanceM(55, 37.62, 56, 37.62), 10)
+	assert.InDelta(t, 0, distanceM(55.75, 37.62, 55.75, 37.62), 1e-6)
+}
+
+func TestLevelScore(t *testing.T) {
+	t.Parallel()
+
+	usual := models.GameLevelMid
+
+	assert.InDelta(t, 0.0, levelScore([]models.GameLevel{models.GameLevelMid}, nil), 1e-9)
+	assert.InDelta(t, 0.5, levelScore(nil, &usual), 1e-9)
+	assert.InDelta(t, 1.0, levelScore([]models.GameLevel{models.GameLevelMidPlus}, &usual), 1e-9)
+	assert.InDelta(t, 0.0, levelScore([]models.GameLevel{models.GameLevelHighPlus}, &usual), 1e-9)
+}
+
+func TestScoreEvent(t *testing.T) {
+	t.Parallel()
+
+	a := &App{recommendationWeights: models.RecommendationWeights{ //nolint:exhaustruct
+		SportType: 3, Level: 1, Distance: 2, Weekday: 1, TimeOfDay: 1, Venue: 1, Friends: 2, FreePlaces: 0.5,
+	}}
+
+	venueID, friendID := uuid.New(), uuid.New()
+	usual := models.GameLevelMid
+	homeLatitude, homeLongitude := 55.75, 37.62
+	// 2025-03-08 is Saturday
+	date := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	profile := &models.RecommendationProfile{
+		SportTypes:    map[models.SportType]bool{models.SportTypeVolleyball: true},
+		UsualLevel:    &usual,
+		HomeLatitude:  &homeLatitude,
+		HomeLongitude: &homeLongitude,
+		Weekdays:      map[int]int{6: 2, 2: 2},
+		Hours:         map[int]int{19: 4},
+		AttendedCount: 4,
+		Venues:        map[uuid.UUID]bool{venueID: true},
+		Friends:       map[uuid.UUID]bool{friendID: true},
+	}
+
+	matched := a.scoreEvent(profile, &models.ShortEvent{ //nolint:exhaustruct
+		SportType:   models.SportTypeVolleyball,
+		VenueID:     &venueID,
+		GameLevels:  []models.GameLevel{models.GameLevelMid},
+		DateAndTime: models.DateAndTime{Date: date, StartTime: date.Add(20 * time.Hour)}, //nolint:exhaustruct
+		Latitude:    common.Ref("55.751"),
+		Longitude:   common.Ref("37.621"),
+		Capacity:    common.Ref(10),
+		Busy:        2,
+		Subscribers: []uuid.UUID{friendID},
+	})
+
+	assert.Equal(t, []string{
+		models.ReasonSportType, models.ReasonLevel, models.ReasonNearby, models.ReasonUsualWeekday,
+		models.ReasonUsualTime, models.ReasonFamiliarVenue, models.ReasonFriends, models.ReasonFreePlaces,
+	}, matched.Reasons)
+
+	other := a.scoreEvent(profile, &models.ShortEvent{ //nolint:exhaustruct
+		SportType:   models.SportTypeFootball,
+		GameLevels:  []models.GameLevel{models.GameLevelHighPlus},
+		DateAndTime: models.DateAndTime{Date: date.AddDate(0, 0, 2), StartTime: date.Add(9 * time.Hour)}, //nolint:exhaustruct
+		Capacity:    common.Ref(10),
+		Busy:        9,
+	})
+
+	assert.Empty(t, other.Reasons)
+	assert.Greater(t, matched.Score, other.Score)
+}
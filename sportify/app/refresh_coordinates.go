@@ -2,282 +2,148 @@ package app
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
-	"io"
-	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
+	"github.com/TheVovchenskiy/sportify-backend/db"
 	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
+
+	"github.com/google/uuid"
 )
 
-type responseYandexAPI struct {
-	Response struct {
-		GeoObjectCollection struct {
-			//MetaDataProperty struct {
-			//	GeocoderResponseMetaData struct {
-			//		BoundedBy struct {
-			//			Envelope struct {
-			//				LowerCorner string `json:"lowerCorner"`
-			//				UpperCorner string `json:"upperCorner"`
-			//			} `json:"Envelope"`
-			//		} `json:"boundedBy"`
-			//		Request string `json:"request"`
-			//		Results string `json:"results"`
-			//		Found   string `json:"found"`
-			//	} `json:"GeocoderResponseMetaData"`
-			//} `json:"metaDataProperty"`
-			FeatureMember []struct {
-				GeoObject struct {
-					//MetaDataProperty struct {
-					//	GeocoderMetaData struct {
-					//		Precision string `json:"precision"`
-					//		Text      string `json:"text"`
-					//		Kind      string `json:"kind"`
-					//		Address   struct {
-					//			CountryCode string `json:"country_code"`
-					//			Formatted   string `json:"formatted"`
-					//			Components  []struct {
-					//				Kind string `json:"kind"`
-					//				Name string `json:"name"`
-					//			} `json:"Components"`
-					//		} `json:"Address"`
-					//		AddressDetails struct {
-					//			Country struct {
-					//				AddressLine        string `json:"AddressLine"`
-					//				CountryNameCode    string `json:"CountryNameCode"`
-					//				CountryName        string `json:"CountryName"`
-					//				AdministrativeArea struct {
-					//					AdministrativeAreaName string `json:"AdministrativeAreaName"`
-					//					Locality               struct {
-					//					} `json:"Locality"`
-					//				} `json:"AdministrativeArea"`
-					//			} `json:"Country"`
-					//		} `json:"AddressDetails"`
-					//	} `json:"GeocoderMetaData"`
-					//} `json:"metaDataProperty"`
-					//Name        string `json:"name"`
-					//Description string `json:"description"`
-					//BoundedBy   struct {
-					//	Envelope struct {
-					//		LowerCorner string `json:"lowerCorner"`
-					//		UpperCorner string `json:"upperCorner"`
-					//	} `json:"Envelope"`
-					//} `json:"boundedBy"`
-					//Uri   string `json:"uri"`
-					Point struct {
-						Pos string `json:"pos"`
-					} `json:"Point"`
-				} `json:"GeoObject"`
-			} `json:"featureMember"`
-		} `json:"GeoObjectCollection"`
-	} `json:"response"`
+type CoordinatesJobStorage interface {
+	EnqueueCoordinatesJobs(ctx context.Context) (int64, error)
+	ClaimCoordinatesJobs(ctx context.Context, limit int, lease time.Duration) ([]models.CoordinatesJob, error)
+	CompleteCoordinatesJob(
+		ctx context.Context,
+		eventID uuid.UUID,
+		latitude, longitude string,
+		details *models.AddressDetails,
+	) error
+	FailCoordinatesJob(
+		ctx context.Context,
+		eventID uuid.UUID,
+		state models.CoordinatesJobState,
+		nextAttemptAt time.Time,
+		lastError string,
+	) error
 }
 
-func (r *responseYandexAPI) GetCoordinates() (string, string, error) {
-	if len(r.Response.GeoObjectCollection.FeatureMember) == 0 {
-		return "", "", fmt.Errorf("yandex coordinate response with 0 result")
-	}
+var _ CoordinatesJobStorage = (*db.PostgresStorage)(nil)
 
-	rawCoordinates := r.Response.GeoObjectCollection.FeatureMember[0].GeoObject.Point.Pos
-	twoCoordinates := strings.Split(rawCoordinates, " ")
+const (
+	coordinatesJobBatch       = 10
+	coordinatesJobLease       = 5 * time.Minute
+	coordinatesJobMaxAttempts = 8
+	coordinatesJobBaseBackoff = time.Minute
+	coordinatesJobMaxBackoff  = 24 * time.Hour
+)
 
-	if len(twoCoordinates) != 2 {
-		return "", "", fmt.Errorf("format yandex coordinates: %s", rawCoordinates)
-	}
+// coordinatesJobBackoff doubles delay after every failed attempt: 1m, 2m, 4m... up to a day.
+func coordinatesJobBackoff(attempts int) time.Duration {
+	backoff := coordinatesJobBaseBackoff
 
-	return twoCoordinates[1], twoCoordinates[0], nil
-}
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= coordinatesJobMaxBackoff {
+			return coordinatesJobMaxBackoff
+		}
+	}
 
-func (a *App) requestYandexAPI() string {
-	return "https://geocode-maps.yandex.ru/1.x?&&lang=ru_RU&format=json&rspn=1&ll=37.623150,55.752508&spn=6,6"
+	return backoff
 }
 
-func (a *App) getCoordinatesByAddressFromYandex(ctx context.Context, address string) (string, string, error) {
-	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "GET", a.requestYandexAPI(), nil)
-	if err != nil {
-		return "", "", fmt.Errorf("to new request: %w", err)
+// wakeUpRefreshCoordinates asks worker to look for new jobs without waiting for period,
+// so coordinates of just created event appear on map quickly.
+func (a *App) wakeUpRefreshCoordinates() {
+	select {
+	case a.wakeUpCoordinates <- struct{}{}:
+	default:
 	}
+}
 
-	values := req.URL.Query()
-
-	values.Add("apikey", a.yandexAPIKey)
-	values.Add("geocode", address)
-	req.URL.RawQuery = values.Encode()
+func (a *App) processCoordinatesJob(ctx context.Context, job *models.CoordinatesJob) {
+	address := reformat_url_open_map.ReformatURLOpenMap(job.Address)
+
+	coordinates, err := a.geocoder.Geocode(ctx, address)
+	if err == nil {
+		err = a.coordinatesJobStorage.CompleteCoordinatesJob(
+			ctx, job.EventID, coordinates.Latitude, coordinates.Longitude, coordinates.Address,
+		)
+		if err == nil {
+			a.logger.Infof("set coordinates for event %s: %s, %s",
+				job.EventID.String(), coordinates.Latitude, coordinates.Longitude)
+
+			a.audit(ctx, &models.AuditRecord{ //nolint:exhaustruct
+				EntityType: models.AuditEntityEvent,
+				EntityID:   job.EventID,
+				EventID:    &job.EventID,
+				Action:     models.AuditActionUpdate,
+				Source:     models.AuditSourceSystem,
+				Before:     auditJSON(job),
+				After:      auditJSON(coordinates),
+			})
 
-	resp, err := a.httpClient.Do(req)
-	if err != nil {
-		return "", "", fmt.Errorf("to do request: %w", err)
+			return
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", fmt.Errorf("to read body: %w", err)
+	state := models.CoordinatesJobStatePending
+	if job.Attempts >= coordinatesJobMaxAttempts {
+		state = models.CoordinatesJobStateFailed
 	}
 
-	var response responseYandexAPI
-
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return "", "", fmt.Errorf("to unmarshal body: %w", err)
-	}
+	a.logger.WithCtx(ctx).Warnw("Unable to refresh coordinates",
+		"event_id", job.EventID, "address", address, "attempts", job.Attempts, "state", state, "error", err)
 
-	lat, long, err := response.GetCoordinates()
+	err = a.coordinatesJobStorage.FailCoordinatesJob(
+		ctx, job.EventID, state, time.Now().Add(coordinatesJobBackoff(job.Attempts)), err.Error(),
+	)
 	if err != nil {
-		a.logger.WithCtx(ctx).Info("body yandex api" + string(body))
-		return "", "", err
+		a.logger.WithCtx(ctx).Error(err)
 	}
-
-	return lat, long, nil
-}
-
-func requestURLOpenMap() string {
-	return fmt.Sprintf("https://nominatim.openstreetmap.org/search" +
-		"?&limit=1&accept-language=ru-RU&countrycodes=RU&format=jsonv2")
 }
 
-const (
-	UserAgentRefresh = "SportifyApp/1.0"
-	UserAgentFind    = "Move-life-App/1.0"
-)
-
-func (a *App) getCoordinatesByAddressOpenMap(ctx context.Context, address string, userAgent string) (string, string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", requestURLOpenMap(), nil)
+// refreshCoordinatesOnce processes jobs until there are no ready ones.
+func (a *App) refreshCoordinatesOnce(ctx context.Context) {
+	_, err := a.coordinatesJobStorage.EnqueueCoordinatesJobs(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("to new request: %w", err)
+		a.logger.WithCtx(ctx).Error(err)
+		return
 	}
 
-	values := req.URL.Query()
-
-	values.Add("q", address)
-	req.URL.RawQuery = values.Encode()
-
-	a.logger.Debug(req.URL.String())
-
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := a.httpClient.Do(req)
-	if err != nil {
-		return "", "", fmt.Errorf("to do request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", fmt.Errorf("to read body: %w", err)
-	}
-
-	a.logger.Debug("GET COORDINATES: ", address, string(body))
-
-	var coordinates []models.ResponseOpenMapCoordinates
-
-	err = json.Unmarshal(body, &coordinates)
-	if err != nil {
-		return "", "", fmt.Errorf("to unmarshal body: %w", err)
-	}
-
-	if len(coordinates) != 1 {
-		return "", "", fmt.Errorf("invalid coordinates count: %d", len(coordinates))
-	}
-
-	return coordinates[0].Latitude, coordinates[0].Longitude, nil
-}
+	for ctx.Err() == nil {
+		jobs, err := a.coordinatesJobStorage.ClaimCoordinatesJobs(ctx, coordinatesJobBatch, coordinatesJobLease)
+		if err != nil {
+			a.logger.WithCtx(ctx).Error(err)
+			return
+		}
 
-func (a *App) getCoordinatesByAddress(ctx context.Context, address string, userAgent string) (string, string, error) {
-	latitude, longitude, errOpenMap := a.getCoordinatesByAddressOpenMap(ctx, address, userAgent)
-	if errOpenMap != nil {
-		var errYandex error
+		if len(jobs) == 0 {
+			return
+		}
 
-		latitude, longitude, errYandex = a.getCoordinatesByAddressFromYandex(ctx, address)
-		if errYandex != nil {
-			return "", "", fmt.Errorf("open map: %w, yandex: %w", errOpenMap, errYandex)
+		for i := range jobs {
+			a.processCoordinatesJob(ctx, &jobs[i])
 		}
 	}
-
-	return latitude, longitude, nil
 }
 
+// RefreshCoordinates runs worker of postgres coordinates queue. Jobs are claimed
+// with SKIP LOCKED, so several instances of app can run it at the same time.
 func (a *App) RefreshCoordinates(ctx context.Context, period time.Duration) {
-	go func() {
-		tickerQueueCoordinates := time.NewTicker(time.Millisecond * 1500)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-tickerQueueCoordinates.C:
-				if len(a.queueCoordinates.queue) != 0 {
-					func() {
-						a.queueCoordinates.mu.Lock()
-						defer a.queueCoordinates.mu.Unlock()
-
-						curCoordinate := a.queueCoordinates.queue[0]
-						a.queueCoordinates.queue = a.queueCoordinates.queue[1:]
-						delete(a.queueCoordinates.idsInQueue, curCoordinate.ID)
-
-						latitude, longitude, err := a.getCoordinatesByAddress(ctx, curCoordinate.Address, UserAgentRefresh)
-						if err != nil {
-							a.logger.WithCtx(ctx).Error(
-								fmt.Sprintf("address: %s, err: %s", curCoordinate.Address, err.Error()),
-							)
-							a.queueCoordinates.idsInQueue[curCoordinate.ID] = struct{}{}
-							return
-						}
-
-						err = a.eventStorage.SetCoordinates(ctx, latitude, longitude, curCoordinate.ID)
-						if err != nil {
-							a.logger.WithCtx(ctx).Error(err)
-							a.queueCoordinates.idsInQueue[curCoordinate.ID] = struct{}{}
-							return
-						}
-
-						a.logger.Infof("set coordinates for event %s: %s, %s", curCoordinate.ID.String(), latitude, longitude)
-					}()
-				}
-			}
-		}
-	}()
-
 	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			ticker.Reset(period)
-
-			params, err := models.ParseFilterParams(make(url.Values))
-			if err != nil {
-				a.logger.WithCtx(ctx).Error(err)
-				continue
-			}
-
-			// TODO optimize from read all db to read only WHERE coordinates IS NULL
-			events, err := a.eventStorage.FindEvents(ctx, params)
-			if err != nil {
-				a.logger.WithCtx(ctx).Error(err)
-				continue
-			}
-
-			for _, event := range events {
-				a.queueCoordinates.mu.Lock()
-				_, ok := a.queueCoordinates.idsInQueue[event.ID]
-				if event.Latitude == nil && event.Longitude == nil && !ok {
-					address := reformat_url_open_map.ReformatURLOpenMap(event.Address)
-					a.queueCoordinates.queue = append(a.queueCoordinates.queue, coordinates{
-						ID:      event.ID,
-						Address: address,
-					})
-					a.queueCoordinates.idsInQueue[event.ID] = struct{}{}
-				}
-				a.queueCoordinates.mu.Unlock()
-			}
+		case <-a.wakeUpCoordinates:
 		}
+
+		a.refreshCoordinatesOnce(ctx)
 	}
 }
@@ -0,0 +1,17 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinatesJobBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Minute, coordinatesJobBackoff(1))
+	assert.Equal(t, 2*time.Minute, coordinatesJobBackoff(2))
+	assert.Equal(t, 8*time.Minute, coordinatesJobBackoff(4))
+	assert.Equal(t, 24*time.Hour, coordinatesJobBackoff(20))
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type ReminderStorage interface {
+	ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]models.EventReminder, error)
+	SetEventReminderHours(ctx context.Context, eventID uuid.UUID, hours *int) error
+	GetUserReminderHours(ctx context.Context, userID uuid.UUID) (*int, error)
+	SetUserReminderHours(ctx context.Context, userID uuid.UUID, hours *int) error
+}
+
+var _ ReminderStorage = (*db.PostgresStorage)(nil)
+
+const reminderBatch = 100
+
+var ErrForbiddenReminderNotYourEvent = errors.New("Вы не можете менять напоминание чужого события")
+
+// reminderStillDue checks claimed reminder against event, it could be cancelled or moved
+// after reminder was claimed. Moved event gets reminder for new start time.
+func reminderStillDue(event *models.FullEvent, startTime, now time.Time) bool {
+	return event.Status == models.EventStatusScheduled &&
+		event.DateAndTime.StartTime.Equal(startTime) && event.DateAndTime.StartTime.After(now)
+}
+
+func (a *App) sendEventReminder(
+	ctx context.Context,
+	reminder models.EventReminder,
+	userIDs []uuid.UUID,
+	now time.Time,
+) {
+	eventID := reminder.EventID
+
+	event, err := a.eventStorage.GetEvent(ctx, eventID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFoundEvent) {
+			a.logger.WithCtx(ctx).Error(err)
+		}
+
+		return
+	}
+
+	if !reminderStillDue(event, reminder.StartTime, now) {
+		return
+	}
+
+	text := fmt.Sprintf("Напоминание: «%s» скоро начнется, адрес: %s", eventTitle(&event.ShortEvent), event.Address)
+
+	err = a.deliverNotification(ctx, models.NotificationEventReminder, userIDs, &eventID, text)
+	if err != nil {
+		a.logger.WithCtx(ctx).Error(err)
+	}
+}
+
+// remindEventsOnce sends reminders until there are no due ones.
+func (a *App) remindEventsOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := models.MoscowNow()
+
+		reminders, err := a.reminderStorage.ClaimDueReminders(ctx, now, reminderBatch)
+		if err != nil {
+			a.logger.WithCtx(ctx).Error(err)
+			return
+		}
+
+		if len(reminders) == 0 {
+			return
+		}
+
+		// reminders of one event have the same start time, it is claimed with event
+		eventReminders := make([]models.EventReminder, 0)
+		usersByEvent := make(map[uuid.UUID][]uuid.UUID)
+
+		for _, reminder := range reminders {
+			if _, ok := usersByEvent[reminder.EventID]; !ok {
+				eventReminders = append(eventReminders, reminder)
+			}
+
+			usersByEvent[reminder.EventID] = append(usersByEvent[reminder.EventID], reminder.UserID)
+		}
+
+		for _, reminder := range eventReminders {
+			a.sendEventReminder(ctx, reminder, usersByEvent[reminder.EventID], now)
+		}
+	}
+}
+
+// RemindEvents runs worker of event reminders. Sent reminders are written to postgres before
+// sending, so restarts do not repeat them and several instances of app can run it at the same time.
+// Reminder is keyed by start time of event, so edited time schedules it again
+// and deleted events are skipped.
+func (a *App) RemindEvents(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(period)
+		}
+
+		a.remindEventsOnce(ctx)
+	}
+}
+
+func (a *App) SetEventReminder(ctx context.Context, userID, eventID uuid.UUID, request *models.RequestReminder) error {
+	creatorID, err := a.eventStorage.GetCreatorID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("to get creator id: %w", err)
+	}
+
+	if creatorID != userID {
+		return ErrForbiddenReminderNotYourEvent
+	}
+
+	err = a.reminderStorage.SetEventReminderHours(ctx, eventID, request.HoursBefore)
+	if err != nil {
+		return fmt.Errorf("to set event reminder: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) GetUserReminder(ctx context.Context, userID uuid.UUID) (*models.RequestReminder, error) {
+	hours, err := a.reminderStorage.GetUserReminderHours(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to get user reminder: %w", err)
+	}
+
+	return &models.RequestReminder{HoursBefore: hours}, nil
+}
+
+func (a *App) SetUserReminder(ctx context.Context, userID uuid.UUID, request *models.RequestReminder) error {
+	err := a.reminderStorage.SetUserReminderHours(ctx, userID, request.HoursBefore)
+	if err != nil {
+		return fmt.Errorf("to set user reminder: %w", err)
+	}
+
+	return nil
+}
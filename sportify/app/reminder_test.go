@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type onceReminderStorage struct {
+	ReminderStorage
+	reminders []models.EventReminder
+	claimedAt []time.Time
+}
+
+func (s *onceReminderStorage) ClaimDueReminders(_ context.Context, now time.Time, _ int) (
+	[]models.EventReminder, error,
+) {
+	s.claimedAt = append(s.claimedAt, now)
+	reminders := s.reminders
+	s.reminders = nil
+
+	return reminders, nil
+}
+
+type mapEventStorage struct {
+	EventStorage
+	events map[uuid.UUID]*models.FullEvent
+}
+
+func (s mapEventStorage) GetEvent(_ context.Context, eventID uuid.UUID) (*models.FullEvent, error) {
+	event, ok := s.events[eventID]
+	if !ok {
+		return nil, db.ErrNotFoundEvent
+	}
+
+	return event, nil
+}
+
+func reminderEvent(start time.Time, status models.EventStatus) *models.FullEvent {
+	return &models.FullEvent{ShortEvent: models.ShortEvent{ //nolint:exhaustruct
+		SportType:   models.SportTypeFootball,
+		Address:     "Москва, Воротынская улица, 9",
+		DateAndTime: models.DateAndTime{Date: start, StartTime: start, EndTime: nil},
+		Status:      status,
+	}}
+}
+
+func TestReminderStillDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 4, 5, 12, 0, 0, 0, time.UTC)
+	start := now.Add(2 * time.Hour)
+
+	assert.True(t, reminderStillDue(reminderEvent(start, models.EventStatusScheduled), start, now))
+	assert.False(t, reminderStillDue(reminderEvent(start, models.EventStatusCancelled), start, now))
+
+	// event is moved after claim, reminder for new start time is claimed separately
+	assert.False(t, reminderStillDue(reminderEvent(start.Add(time.Hour), models.EventStatusScheduled), start, now))
+
+	// event has already started
+	assert.False(t, reminderStillDue(reminderEvent(start, models.EventStatusScheduled), start, start))
+	assert.False(t, reminderStillDue(reminderEvent(start, models.EventStatusScheduled), start, start.Add(time.Minute)))
+}
+
+func TestRemindEventsOnce(t *testing.T) {
+	t.Parallel()
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	scheduledID, cancelledID, deletedID := uuid.New(), uuid.New(), uuid.New()
+	start := time.Now().Add(5 * time.Hour)
+
+	reminderStorage := &onceReminderStorage{reminders: []models.EventReminder{ //nolint:exhaustruct
+		{EventID: scheduledID, UserID: first, StartTime: start},
+		{EventID: cancelledID, UserID: first, StartTime: start},
+		{EventID: deletedID, UserID: second, StartTime: start},
+		{EventID: scheduledID, UserID: second, StartTime: start},
+		{EventID: scheduledID, UserID: third, StartTime: start},
+	}}
+	notificationStorage := &fakeNotificationStorage{ //nolint:exhaustruct
+		tgIDs: map[uuid.UUID]int64{first: 1, second: 2, third: 3},
+	}
+	botAPI := &recordingBotAPI{} //nolint:exhaustruct
+	a := &App{                   //nolint:exhaustruct
+		reminderStorage: reminderStorage,
+		eventStorage: mapEventStorage{events: map[uuid.UUID]*models.FullEvent{ //nolint:exhaustruct
+			scheduledID: reminderEvent(start, models.EventStatusScheduled),
+			cancelledID: reminderEvent(start, models.EventStatusCancelled),
+		}},
+		notificationStorage: notificationStorage,
+		botAPI:              botAPI,
+		logger:              mylogger.NewNop(),
+	}
+
+	before := time.Now()
+	a.remindEventsOnce(context.Background())
+
+	// claims until there are no due reminders, now is in moscow time as start time of events
+	if assert.Len(t, reminderStorage.claimedAt, 2) {
+		assert.WithinRange(t, reminderStorage.claimedAt[0], before.Add(3*time.Hour), time.Now().Add(3*time.Hour))
+	}
+
+	// users of one event get one message, cancelled and deleted events are skipped
+	if assert.Len(t, botAPI.messages, 1) {
+		assert.Equal(t, []int64{1, 2, 3}, botAPI.messages[0].TgUserIDs)
+		assert.Contains(t, botAPI.messages[0].Text, "Воротынская")
+	}
+
+	assert.Len(t, notificationStorage.created, 3)
+
+	for _, notification := range notificationStorage.created {
+		assert.Equal(t, models.NotificationEventReminder, notification.Kind)
+		assert.Equal(t, &scheduledID, notification.EventID)
+	}
+}
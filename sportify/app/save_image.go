@@ -1,15 +1,14 @@
 package app
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"image"
-	_ "image/jpeg" // Add jpeg format for image
-	_ "image/png"  // Add png format for image
+
+	"github.com/TheVovchenskiy/sportify-backend/app/imageproc"
+	"github.com/TheVovchenskiy/sportify-backend/models"
 )
 
 func hashContent(content []byte) (string, error) {
@@ -25,30 +24,76 @@ func hashContent(content []byte) (string, error) {
 	return hex.EncodeToString(result), nil
 }
 
-var ErrWrongFormat = errors.New("Формат файла должен быть png или jpeg")
-
-func (a *App) SaveImage(ctx context.Context, file []byte) (string, error) {
+var ErrWrongFormat = errors.New("Формат файла должен быть png, jpeg или webp")
 
-	_, format, err := image.Decode(bytes.NewReader(file))
+// SaveImage saves normalized variants of uploaded image. Names of variants are made from hash
+// of uploaded file, so the same upload is not saved twice.
+func (a *App) SaveImage(ctx context.Context, file []byte) (*models.ImageVariants, error) {
+	variants, err := imageproc.Process(file)
 	if err != nil {
-		return "", fmt.Errorf("to decode(format %s): %w %w", format, err, ErrWrongFormat)
-		//return "", fmt.Errorf("вы используете формат %s %w", format, ErrWrongFormat)
+		if errors.Is(err, imageproc.ErrDecode) {
+			return nil, fmt.Errorf("to process image: %w %w", err, ErrWrongFormat)
+		}
+
+		return nil, fmt.Errorf("to process image: %w", err)
 	}
 
-	fileName, err := hashContent(file)
+	hash, err := hashContent(file)
 	if err != nil {
-		return "", fmt.Errorf("to hash content: %w", err)
+		return nil, fmt.Errorf("to hash content: %w", err)
 	}
 
-	existenceFile, err := a.fileStorage.Check(ctx, []string{fileName})
-	if existenceFile[0] {
-		return a.urlPrefixFile + fileName, nil
+	// WebP is saved only when it is smaller than original, its url is empty otherwise
+	fileNames := make([]string, 0, 2*len(variants))
+	contents := make([][]byte, 0, 2*len(variants))
+
+	for _, variant := range variants {
+		name := hash + "_" + string(variant.Size)
+
+		fileNames = append(fileNames, name+variant.Ext)
+		contents = append(contents, variant.Content)
+
+		if variant.WebP != nil {
+			fileNames = append(fileNames, name+imageproc.ExtWebP)
+			contents = append(contents, variant.WebP)
+		}
 	}
 
-	err = a.fileStorage.SaveFile(ctx, file, fileName)
+	existenceFiles, err := a.fileStorage.Check(ctx, fileNames)
 	if err != nil {
-		return "", fmt.Errorf("to save file: %w", err)
+		return nil, fmt.Errorf("to check files: %w", err)
+	}
+
+	for i, fileName := range fileNames {
+		if existenceFiles[i] {
+			continue
+		}
+
+		err = a.fileStorage.SaveFile(ctx, contents[i], fileName)
+		if err != nil {
+			return nil, fmt.Errorf("to save file: %w", err)
+		}
+	}
+
+	result := &models.ImageVariants{} //nolint:exhaustruct
+
+	for _, variant := range variants {
+		name := hash + "_" + string(variant.Size)
+		url, urlWebP := a.urlPrefixFile+name+variant.Ext, ""
+
+		if variant.WebP != nil {
+			urlWebP = a.urlPrefixFile + name + imageproc.ExtWebP
+		}
+
+		switch variant.Size {
+		case imageproc.SizeThumbnail:
+			result.Thumbnail, result.ThumbnailWebP = url, urlWebP
+		case imageproc.SizeMedium:
+			result.Medium, result.MediumWebP = url, urlWebP
+		case imageproc.SizeFull:
+			result.Full, result.FullWebP = url, urlWebP
+		}
 	}
 
-	return a.urlPrefixFile + fileName, nil
+	return result, nil
 }
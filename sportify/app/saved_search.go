@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type SavedSearchStorage interface {
+	CreateSavedSearch(ctx context.Context, savedSearch *models.SavedSearch) error
+	GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error
+	GetAlertingSavedSearches(ctx context.Context, eventID, exceptUserID uuid.UUID) ([]models.SavedSearch, error)
+	MatchSavedSearches(ctx context.Context, eventID uuid.UUID, savedSearches []models.SavedSearchFilter) ([]uuid.UUID, error)
+	AddSavedSearchAlert(ctx context.Context, savedSearchID, eventID uuid.UUID) (bool, error)
+	GetSavedSearchAlerts(ctx context.Context, userID uuid.UUID, limit int) ([]models.SavedSearchAlert, error)
+}
+
+var _ SavedSearchStorage = (*db.PostgresStorage)(nil)
+
+const (
+	maxSavedSearches      = 20
+	limitSavedSearchAlert = 100
+	savedSearchesTimeout  = time.Minute
+)
+
+var (
+	ErrValidationSavedSearch = errors.New("Неправильные параметры поиска")
+	ErrTooManySavedSearches  = fmt.Errorf("Можно сохранить не больше %d поисков", maxSavedSearches)
+)
+
+func (a *App) CreateSavedSearch(
+	ctx context.Context,
+	userID uuid.UUID,
+	request *models.RequestSavedSearchCreate,
+) (*models.SavedSearch, error) {
+	err := request.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationSavedSearch, err)
+	}
+
+	savedSearches, err := a.savedSearchStorage.GetSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to get saved searches: %w", err)
+	}
+
+	if len(savedSearches) >= maxSavedSearches {
+		return nil, ErrTooManySavedSearches
+	}
+
+	savedSearch := &models.SavedSearch{ //nolint:exhaustruct
+		ID:                   uuid.New(),
+		UserID:               userID,
+		Name:                 request.Name,
+		Params:               request.Params,
+		AlertsEnabled:        request.AlertsEnabled == nil || *request.AlertsEnabled,
+		AlertIntervalMinutes: models.DefaultAlertIntervalMinutes,
+		CreatedAt:            time.Now(),
+	}
+
+	if request.AlertIntervalMinutes != nil {
+		savedSearch.AlertIntervalMinutes = *request.AlertIntervalMinutes
+	}
+
+	a.resolveSavedSearchAddress(ctx, savedSearch)
+
+	err = a.savedSearchStorage.CreateSavedSearch(ctx, savedSearch)
+	if err != nil {
+		return nil, fmt.Errorf("to create saved search: %w", err)
+	}
+
+	return savedSearch, nil
+}
+
+func (a *App) GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error) {
+	savedSearches, err := a.savedSearchStorage.GetSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to get saved searches: %w", err)
+	}
+
+	return savedSearches, nil
+}
+
+func (a *App) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	err := a.savedSearchStorage.DeleteSavedSearch(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("to delete saved search: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) GetSavedSearchAlerts(ctx context.Context, userID uuid.UUID) ([]models.SavedSearchAlert, error) {
+	alerts, err := a.savedSearchStorage.GetSavedSearchAlerts(ctx, userID, limitSavedSearchAlert)
+	if err != nil {
+		return nil, fmt.Errorf("to get saved search alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+func savedSearchAlertText(savedSearch *models.SavedSearch, event *models.FullEvent) string {
+	return fmt.Sprintf("Новое событие по поиску «%s»: %s, %s",
+		savedSearch.Name, eventTitle(&event.ShortEvent), event.Address)
+}
+
+// resolveSavedSearchAddress geocodes address of search once on save, so matching of new events
+// doesn't call geocoder. Search is saved without point if address is not found, as GET /events does.
+func (a *App) resolveSavedSearchAddress(ctx context.Context, savedSearch *models.SavedSearch) {
+	filterParams, err := savedSearch.FilterParams()
+	if err != nil || filterParams.Address == "" || filterParams.Latitude != nil {
+		return
+	}
+
+	a.applyAddressSearch(ctx, filterParams)
+
+	if filterParams.Latitude == nil {
+		return
+	}
+
+	savedSearch.Params.Set("lat", strconv.FormatFloat(*filterParams.Latitude, 'f', -1, 64))
+	savedSearch.Params.Set("lon", strconv.FormatFloat(*filterParams.Longitude, 'f', -1, 64))
+}
+
+// savedSearchFilters makes filters of searches for one event, so matching has the same
+// semantics as GET /events.
+func savedSearchFilters(savedSearches []models.SavedSearch, now time.Time) []models.SavedSearchFilter {
+	result := make([]models.SavedSearchFilter, 0, len(savedSearches))
+
+	for i := range savedSearches {
+		filterParams, err := savedSearches[i].FilterParams()
+		if err != nil {
+			continue
+		}
+
+		filterParams.ExcludeSubscriberID = nil
+		if filterParams.ExcludeJoined {
+			filterParams.ExcludeSubscriberID = &savedSearches[i].UserID
+		}
+
+		filterParams.DateExpression = squirrel.GtOrEq{"start_time": now}
+		filterParams.Limit = 0
+		filterParams.Cursor = nil
+
+		result = append(result, models.SavedSearchFilter{ID: savedSearches[i].ID, FilterParams: filterParams})
+	}
+
+	return result
+}
+
+func (a *App) alertSavedSearch(ctx context.Context, savedSearch *models.SavedSearch, event *models.FullEvent) error {
+	notify, err := a.savedSearchStorage.AddSavedSearchAlert(ctx, savedSearch.ID, event.ID)
+	if err != nil {
+		return fmt.Errorf("to add saved search alert: %w", err)
+	}
+
+	// search was alerted recently, event is only added to feed
+	if !notify {
+		return nil
+	}
+
+	return a.deliverNotification(ctx, models.NotificationSavedSearch, []uuid.UUID{savedSearch.UserID}, &event.ID,
+		savedSearchAlertText(savedSearch, event))
+}
+
+// alertSavedSearches matches new event against saved searches of other users in background,
+// creation of event doesn't wait for it. Postgres picks searches by sport type and place,
+// the rest of filters is checked by queries of many searches at once.
+// Telegram events get coordinates later, so they match only searches without radius.
+func (a *App) alertSavedSearches(ctx context.Context, event *models.FullEvent) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), savedSearchesTimeout)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if pan := recover(); pan != nil {
+				a.logger.Errorf("panic: %v", pan)
+			}
+		}()
+
+		savedSearches, err := a.savedSearchStorage.GetAlertingSavedSearches(ctx, event.ID, event.CreatorID)
+		if err != nil {
+			a.logger.WithCtx(ctx).Error(err)
+			return
+		}
+
+		now := models.MoscowNow()
+
+		matchedIDs, err := a.savedSearchStorage.MatchSavedSearches(ctx, event.ID, savedSearchFilters(savedSearches, now))
+		if err != nil {
+			a.logger.WithCtx(ctx).Warnw("Unable to match saved searches", "event_id", event.ID, "error", err)
+			return
+		}
+
+		matched := make(map[uuid.UUID]struct{}, len(matchedIDs))
+		for _, id := range matchedIDs {
+			matched[id] = struct{}{}
+		}
+
+		for i := range savedSearches {
+			if _, ok := matched[savedSearches[i].ID]; !ok {
+				continue
+			}
+
+			err = a.alertSavedSearch(ctx, &savedSearches[i], event)
+			if err != nil {
+				a.logger.WithCtx(ctx).Warnw("Unable to alert saved search",
+					"saved_search_id", savedSearches[i].ID, "event_id", event.ID, "error", err)
+			}
+		}
+	}()
+}
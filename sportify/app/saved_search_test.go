@@ -0,0 +1,34 @@
+package app
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedSearchFilters(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	filters := savedSearchFilters([]models.SavedSearch{
+		{ID: uuid.New(), UserID: userID, Params: url.Values{"exclude_joined": {"true"}, "lat": {"55.7"}, "lon": {"37.6"}}}, //nolint:exhaustruct,lll
+		{ID: uuid.New(), UserID: userID, Params: url.Values{"weekday": {"8"}}},                                             //nolint:exhaustruct
+	}, now)
+
+	// search with broken params is skipped, not matched with all events
+	if !assert.Len(t, filters, 1) {
+		return
+	}
+
+	assert.Equal(t, &userID, filters[0].FilterParams.ExcludeSubscriberID)
+	assert.Zero(t, filters[0].FilterParams.Limit)
+	assert.NotNil(t, filters[0].FilterParams.Latitude)
+	assert.NotNil(t, filters[0].FilterParams.DateExpression)
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/sharecard"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+)
+
+// shareCardBackground is default photo of sport type, photos of organizers are not used,
+// text over them is often unreadable.
+func (a *App) shareCardBackground(ctx context.Context, fileName string) image.Image {
+	content, err := a.fileStorage.ReadFile(ctx, fileName)
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to read background of share card", "file", fileName, "error", err)
+		return nil
+	}
+
+	background, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		a.logger.WithCtx(ctx).Warnw("Unable to decode background of share card", "file", fileName, "error", err)
+		return nil
+	}
+
+	return background
+}
+
+// GetEventShareCard returns url of picture of event for previews in messengers. Name of card is hash
+// of its content, so card is drawn once and changed event gets new card.
+func (a *App) GetEventShareCard(ctx context.Context, event *models.ShortEvent) (string, error) {
+	share := models.NewEventShare(event)
+	backgroundName := strings.TrimPrefix(a.getDefaultEventPhoto(event.SportType), a.urlPrefixFile)
+
+	hash, err := hashContent([]byte(strings.Join([]string{
+		sharecard.Version, backgroundName,
+		share.Title, share.DateTime, share.Address, share.Price, share.FreePlaces,
+	}, "\n")))
+	if err != nil {
+		return "", fmt.Errorf("to hash share card: %w", err)
+	}
+
+	fileName := "share_" + hash + ".png"
+
+	exist, err := a.fileStorage.Check(ctx, []string{fileName})
+	if err != nil {
+		return "", fmt.Errorf("to check share card: %w", err)
+	}
+
+	if exist[0] {
+		return a.urlPrefixFile + fileName, nil
+	}
+
+	background := a.shareCardBackground(ctx, backgroundName)
+
+	card, err := sharecard.Render(&share, background)
+	if err != nil {
+		return "", fmt.Errorf("to render share card: %w", err)
+	}
+
+	err = a.fileStorage.SaveFile(ctx, card, fileName)
+	if err != nil {
+		return "", fmt.Errorf("to save share card: %w", err)
+	}
+
+	return a.urlPrefixFile + fileName, nil
+}
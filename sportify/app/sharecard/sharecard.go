@@ -0,0 +1,160 @@
+// Package sharecard draws picture of event shown in messengers when link to event is shared.
+package sharecard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Width and Height are size recommended for Open Graph images.
+const (
+	Width  = 1200
+	Height = 630
+
+	padding       = 64
+	titleSize     = 88
+	textSize      = 44
+	lineSpacing   = 1.5
+	overlayAlpha  = 150
+	ellipsis      = "…"
+	maxTextLength = Width - 2*padding
+)
+
+// Version is part of hash of card, it is changed with layout, so old cards are drawn again.
+const Version = "1"
+
+//nolint:gochecknoglobals
+var (
+	parseFontsOnce sync.Once
+	boldFont       *opentype.Font
+	regularFont    *opentype.Font
+	errParseFonts  error
+)
+
+func parseFonts() error {
+	parseFontsOnce.Do(func() {
+		boldFont, errParseFonts = opentype.Parse(gobold.TTF)
+		if errParseFonts != nil {
+			return
+		}
+
+		regularFont, errParseFonts = opentype.Parse(goregular.TTF)
+	})
+
+	return errParseFonts
+}
+
+// newFace is made for every card, faces keep buffers and can't be shared between goroutines.
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull}) //nolint:mnd
+	if err != nil {
+		return nil, fmt.Errorf("to make face: %w", err)
+	}
+
+	return face, nil
+}
+
+// drawCover fills card by background keeping its proportions, extra part is cut from the center.
+func drawCover(dst draw.Image, background image.Image) {
+	bounds := background.Bounds()
+	if bounds.Empty() {
+		return
+	}
+
+	src := bounds
+	if bounds.Dx()*Height > bounds.Dy()*Width {
+		cutWidth := bounds.Dy() * Width / Height
+		src.Min.X += (bounds.Dx() - cutWidth) / 2 //nolint:mnd
+		src.Max.X = src.Min.X + cutWidth
+	} else {
+		cutHeight := bounds.Dx() * Height / Width
+		src.Min.Y += (bounds.Dy() - cutHeight) / 2 //nolint:mnd
+		src.Max.Y = src.Min.Y + cutHeight
+	}
+
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), background, src, draw.Src, nil)
+}
+
+// fitText cuts text by words and adds ellipsis, so it fits into card.
+func fitText(face font.Face, text string) string {
+	limit := fixed.I(maxTextLength)
+	if font.MeasureString(face, text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && font.MeasureString(face, string(runes)+ellipsis) > limit {
+		runes = runes[:len(runes)-1]
+	}
+
+	return strings.TrimRight(string(runes), " ,") + ellipsis
+}
+
+// goFontsText replaces symbols which go fonts don't have.
+var goFontsText = strings.NewReplacer("₽", "руб.") //nolint:gochecknoglobals
+
+// Render draws card with text of event over background, background can be nil.
+func Render(share *models.EventShare, background image.Image) ([]byte, error) {
+	err := parseFonts()
+	if err != nil {
+		return nil, fmt.Errorf("to parse fonts: %w", err)
+	}
+
+	titleFace, err := newFace(boldFont, titleSize)
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+
+	textFace, err := newFace(regularFont, textSize)
+	if err != nil {
+		return nil, err
+	}
+	defer textFace.Close()
+
+	card := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(card, card.Bounds(), image.NewUniform(color.RGBA{R: 24, G: 32, B: 48, A: 255}), image.Point{}, draw.Src) //nolint:mnd
+
+	if background != nil {
+		drawCover(card, background)
+	}
+
+	// text must be readable over any photo
+	draw.Draw(card, card.Bounds(), image.NewUniform(color.RGBA{R: 0, G: 0, B: 0, A: overlayAlpha}), image.Point{}, draw.Over)
+
+	drawer := &font.Drawer{Dst: card, Src: image.White, Face: titleFace, Dot: fixed.P(padding, padding+titleSize)}
+	drawer.DrawString(fitText(titleFace, goFontsText.Replace(share.Title)))
+
+	lines := []string{share.DateTime, share.Address, share.Price, share.FreePlaces}
+	drawer.Face = textFace
+
+	y := Height - padding - int(float64(textSize)*lineSpacing)*(len(lines)-1)
+	for _, line := range lines {
+		drawer.Dot = fixed.P(padding, y)
+		drawer.DrawString(fitText(textFace, goFontsText.Replace(line)))
+		y += int(float64(textSize) * lineSpacing)
+	}
+
+	buf := &bytes.Buffer{}
+
+	err = png.Encode(buf, card)
+	if err != nil {
+		return nil, fmt.Errorf("to encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,38 @@
+package sharecard_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/sharecard"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	share := &models.EventShare{
+		Title:      "Волейбол",
+		DateTime:   "08.03 19:00–21:00",
+		Address:    "Москва, " + strings.Repeat("очень длинная улица, ", 10),
+		Price:      "500 ₽",
+		FreePlaces: "Свободных мест: 6",
+	}
+
+	// no background, tall and wide ones
+	backgrounds := []image.Image{nil, image.NewRGBA(image.Rect(0, 0, 300, 400)), image.NewRGBA(image.Rect(0, 0, 2000, 1000))}
+
+	for _, bg := range backgrounds {
+		card, err := sharecard.Render(share, bg)
+		assert.NoError(t, err)
+
+		decoded, err := png.Decode(bytes.NewReader(card))
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, sharecard.Width, sharecard.Height), decoded.Bounds())
+	}
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type VenueStorage interface {
+	CreateVenue(ctx context.Context, venue *models.Venue) error
+	EditVenue(ctx context.Context, venue *models.Venue) error
+	GetVenue(ctx context.Context, id uuid.UUID) (*models.Venue, error)
+	FindVenues(ctx context.Context, query string, sportType *models.SportType, limit int) ([]models.Venue, error)
+}
+
+var _ VenueStorage = (*db.PostgresStorage)(nil)
+
+const limitVenueSuggestions = 20
+
+var (
+	ErrValidationRequestVenue    = errors.New("Неправильные параметры площадки")
+	ErrForbiddenEditNotYourVenue = errors.New("Вы не можете изменять чужую площадку")
+	ErrVenueCoordinatesNotFound  = errors.New("Не удалось найти координаты площадки, укажите их явно")
+)
+
+func normalizeVenueAddress(address string) string {
+	return strings.TrimSpace(reformat_url_open_map.ReformatURLOpenMap(strings.TrimSpace(address)))
+}
+
+// geocodeVenue finds coordinates of venue once, events held there reuse them.
+func (a *App) geocodeVenue(ctx context.Context, venue *models.Venue) error {
+	coordinates, err := a.geocoder.Geocode(ctx, venue.NormalizedAddress)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVenueCoordinatesNotFound, err)
+	}
+
+	venue.Latitude = &coordinates.Latitude
+	venue.Longitude = &coordinates.Longitude
+
+	return nil
+}
+
+func (a *App) CreateVenue(ctx context.Context, userID uuid.UUID, request *models.RequestVenueCreate) (*models.Venue, error) {
+	err := request.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationRequestVenue, err)
+	}
+
+	venue := &models.Venue{
+		ID:                uuid.New(),
+		CreatorID:         &userID,
+		Name:              request.Name,
+		Address:           request.Address,
+		NormalizedAddress: normalizeVenueAddress(request.Address),
+		Latitude:          request.Latitude,
+		Longitude:         request.Longitude,
+		Surface:           request.Surface,
+		Indoor:            request.Indoor,
+		SportTypes:        request.SportTypes,
+		Amenities:         request.Amenities,
+		URLPhotos:         request.URLPhotos,
+		CreatedAt:         time.Now(),
+	}
+
+	if venue.URLPhotos == nil {
+		venue.URLPhotos = []string{}
+	}
+
+	if venue.Latitude == nil {
+		err = a.geocodeVenue(ctx, venue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = a.venueStorage.CreateVenue(ctx, venue)
+	if err != nil {
+		return nil, fmt.Errorf("to create venue: %w", err)
+	}
+
+	return venue, nil
+}
+
+//nolint:cyclop
+func (a *App) EditVenue(ctx context.Context, userID, venueID uuid.UUID, request *models.RequestVenueEdit) (*models.Venue, error) {
+	venueFromDB, err := a.venueStorage.GetVenue(ctx, venueID)
+	if err != nil {
+		return nil, fmt.Errorf("to get venue: %w", err)
+	}
+
+	if venueFromDB.CreatorID == nil || *venueFromDB.CreatorID != userID {
+		return nil, ErrForbiddenEditNotYourVenue
+	}
+
+	requestCreate := models.RequestVenueCreate{
+		Name:       common.NewValWithFallback(request.Name, &venueFromDB.Name),
+		Address:    common.NewValWithFallback(request.Address, &venueFromDB.Address),
+		Latitude:   request.Latitude,
+		Longitude:  request.Longitude,
+		Surface:    request.Surface,
+		Indoor:     common.NewValWithFallback(request.Indoor, &venueFromDB.Indoor),
+		SportTypes: request.SportTypes,
+		Amenities:  request.Amenities,
+		URLPhotos:  request.URLPhotos,
+	}
+
+	err = requestCreate.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationRequestVenue, err)
+	}
+
+	addressChanged := requestCreate.Address != venueFromDB.Address
+
+	venueFromDB.Name = requestCreate.Name
+	venueFromDB.Address = requestCreate.Address
+	venueFromDB.NormalizedAddress = normalizeVenueAddress(requestCreate.Address)
+	venueFromDB.Indoor = requestCreate.Indoor
+
+	if request.Surface != nil {
+		venueFromDB.Surface = request.Surface
+	}
+
+	if request.SportTypes != nil {
+		venueFromDB.SportTypes = request.SportTypes
+	}
+
+	if request.Amenities != nil {
+		venueFromDB.Amenities = request.Amenities
+	}
+
+	if request.URLPhotos != nil {
+		venueFromDB.URLPhotos = request.URLPhotos
+	}
+
+	switch {
+	case request.Latitude != nil:
+		venueFromDB.Latitude = request.Latitude
+		venueFromDB.Longitude = request.Longitude
+	case addressChanged:
+		err = a.geocodeVenue(ctx, venueFromDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = a.venueStorage.EditVenue(ctx, venueFromDB)
+	if err != nil {
+		return nil, fmt.Errorf("to edit venue: %w", err)
+	}
+
+	return venueFromDB, nil
+}
+
+func (a *App) GetVenue(ctx context.Context, venueID uuid.UUID) (*models.Venue, error) {
+	return a.venueStorage.GetVenue(ctx, venueID)
+}
+
+// SuggestVenues is autocomplete of venues by our own catalog without geocoder calls.
+func (a *App) SuggestVenues(ctx context.Context, query string, sportType *models.SportType) ([]models.Venue, error) {
+	return a.venueStorage.FindVenues(ctx, query, sportType, limitVenueSuggestions)
+}
+
+// FindVenueUpcomingEvents returns events held at venue that have not started yet.
+func (a *App) FindVenueUpcomingEvents(ctx context.Context, venueID uuid.UUID) ([]models.ShortEvent, error) {
+	filterParams := &models.FilterParams{ //nolint:exhaustruct
+		VenueID:        &venueID,
+		OrderBy:        models.OrderByStartTime,
+		SortOrder:      "asc",
+		DateExpression: squirrel.GtOrEq{"start_time": models.MoscowNow()},
+	}
+
+	return a.FindEvents(ctx, filterParams)
+}
+
+// applyVenueToEvent puts event at venue: address falls back to venue address
+// and coordinates are taken from venue instead of geocoding.
+func (a *App) applyVenueToEvent(ctx context.Context, event *models.FullEvent) error {
+	if event.VenueID == nil {
+		return nil
+	}
+
+	venue, err := a.venueStorage.GetVenue(ctx, *event.VenueID)
+	if err != nil {
+		return fmt.Errorf("to get venue: %w", err)
+	}
+
+	if strings.TrimSpace(event.Address) == "" {
+		event.Address = venue.Address
+	}
+
+	event.Latitude = venue.Latitude
+	event.Longitude = venue.Longitude
+
+	return nil
+}
@@ -0,0 +1,242 @@
+// Package venueimport fills venue catalog from local OpenStreetMap extracts
+// without requests to Nominatim.
+package venueimport
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/osm"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
+
+	"github.com/google/uuid"
+)
+
+type VenueStorage interface {
+	UpsertVenueByOSMID(ctx context.Context, osmID string, venue *models.Venue) (bool, error)
+}
+
+var _ VenueStorage = (*db.PostgresStorage)(nil)
+
+const (
+	maxLenVenueName = 256
+	logEvery        = 1000
+)
+
+// osmSportTypes maps values of OSM sport=* tag to our sport types.
+var osmSportTypes = map[string]models.SportType{ //nolint:gochecknoglobals
+	"soccer":               models.SportTypeFootball,
+	"basketball":           models.SportTypeBasketball,
+	"volleyball":           models.SportTypeVolleyball,
+	"beachvolleyball":      models.SportTypeVolleyball,
+	"tennis":               models.SportTypeTennis,
+	"table_tennis":         models.SportTypeTableTennis,
+	"running":              models.SportTypeRunning,
+	"athletics":            models.SportTypeRunning,
+	"ice_hockey":           models.SportTypeHockey,
+	"hockey":               models.SportTypeHockey,
+	"ice_skating":          models.SportTypeSkating,
+	"skating":              models.SportTypeSkating,
+	"skiing":               models.SportTypeSkiing,
+	"cross_country_skiing": models.SportTypeSkiing,
+}
+
+var osmSurfaces = map[string]models.VenueSurface{ //nolint:gochecknoglobals
+	"grass":           models.VenueSurfaceGrass,
+	"artificial_turf": models.VenueSurfaceArtificialGrass,
+	"wood":            models.VenueSurfaceParquet,
+	"tartan":          models.VenueSurfaceRubber,
+	"rubber":          models.VenueSurfaceRubber,
+	"asphalt":         models.VenueSurfaceAsphalt,
+	"ice":             models.VenueSurfaceIce,
+	"sand":            models.VenueSurfaceSand,
+	"clay":            models.VenueSurfaceClay,
+}
+
+// osmAmenities are OSM keys with value "yes" that mean amenity.
+var osmAmenities = []struct { //nolint:gochecknoglobals
+	key     string
+	amenity models.VenueAmenity
+}{
+	{key: "shower", amenity: models.VenueAmenityShowers},
+	{key: "changing_room", amenity: models.VenueAmenityChangingRooms},
+	{key: "drinking_water", amenity: models.VenueAmenityDrinkingWater},
+	{key: "lit", amenity: models.VenueAmenityLighting},
+}
+
+// IsSportObject selects leisure=pitch, leisure=sports_centre and sport=* objects.
+func IsSportObject(tags map[string]string) bool {
+	leisure := tags["leisure"]
+
+	return leisure == "pitch" || leisure == "sports_centre" || tags["sport"] != ""
+}
+
+func mapSportTypes(rawSport string) []models.SportType {
+	result := []models.SportType{}
+	seen := make(map[models.SportType]struct{})
+
+	// sport=soccer;basketball is used for multi-purpose pitches
+	for _, sport := range strings.Split(rawSport, ";") {
+		sportType, ok := osmSportTypes[strings.TrimSpace(sport)]
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[sportType]; ok {
+			continue
+		}
+
+		seen[sportType] = struct{}{}
+		result = append(result, sportType)
+	}
+
+	return result
+}
+
+func mapSurface(rawSurface string) *models.VenueSurface {
+	if rawSurface == "" {
+		return nil
+	}
+
+	surface, ok := osmSurfaces[rawSurface]
+	if !ok {
+		surface = models.VenueSurfaceOther
+	}
+
+	return &surface
+}
+
+func mapAddress(object *osm.Object) string {
+	var parts []string
+
+	for _, key := range []string{"addr:city", "addr:street", "addr:housenumber"} {
+		if value := strings.TrimSpace(object.Tags[key]); value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, strings.TrimSpace(object.Tags["addr:full"]))
+	}
+
+	address := reformat_url_open_map.ReformatURLOpenMap(strings.Join(parts, ", "))
+	if address != "" {
+		return address
+	}
+
+	// a lot of pitches don't have address tags, coordinates are still searchable
+	return strconv.FormatFloat(object.Lat, 'f', 6, 64) + ", " + strconv.FormatFloat(object.Lon, 'f', 6, 64)
+}
+
+func mapName(object *osm.Object, sportTypes []models.SportType) string {
+	name := strings.TrimSpace(object.Tags["name"])
+
+	switch {
+	case name != "":
+	case object.Tags["leisure"] == "sports_centre":
+		name = "Спортивный центр"
+	case len(sportTypes) == 1:
+		sportTypeRu, _ := models.EnToRuSportType(sportTypes[0])
+		name = "Площадка: " + sportTypeRu
+	default:
+		name = "Спортивная площадка"
+	}
+
+	if runes := []rune(name); len(runes) > maxLenVenueName {
+		name = string(runes[:maxLenVenueName])
+	}
+
+	return name
+}
+
+// VenueFromOSM maps OSM object to venue. Objects with sport=* tag that has
+// none of our sport types (chess, golf and so on) are skipped, pitches and
+// sports centres without sport tag are kept as multi-purpose venues.
+func VenueFromOSM(object *osm.Object) (*models.Venue, bool) {
+	sportTypes := mapSportTypes(object.Tags["sport"])
+	if object.Tags["sport"] != "" && len(sportTypes) == 0 {
+		return nil, false
+	}
+
+	amenities := []models.VenueAmenity{}
+
+	for _, osmAmenity := range osmAmenities {
+		if object.Tags[osmAmenity.key] == "yes" {
+			amenities = append(amenities, osmAmenity.amenity)
+		}
+	}
+
+	building := object.Tags["building"]
+	indoor := object.Tags["indoor"] == "yes" || object.Tags["covered"] == "yes" || (building != "" && building != "no")
+	address := mapAddress(object)
+
+	return &models.Venue{ //nolint:exhaustruct
+		ID:                uuid.New(),
+		Name:              mapName(object, sportTypes),
+		Address:           address,
+		NormalizedAddress: address,
+		Latitude:          common.Ref(strconv.FormatFloat(object.Lat, 'f', -1, 64)),
+		Longitude:         common.Ref(strconv.FormatFloat(object.Lon, 'f', -1, 64)),
+		Surface:           mapSurface(object.Tags["surface"]),
+		Indoor:            indoor,
+		SportTypes:        sportTypes,
+		Amenities:         amenities,
+		URLPhotos:         []string{},
+		CreatedAt:         time.Now(),
+	}, true
+}
+
+type Stats struct {
+	Found   int
+	Created int
+	Updated int
+	Skipped int
+}
+
+// Import reads extract and upserts venues by their OSM ids, so it can be rerun on fresh extracts.
+func Import(ctx context.Context, storage VenueStorage, logger *mylogger.MyLogger, path string) (*Stats, error) {
+	objects, err := osm.ReadFile(path, IsSportObject)
+	if err != nil {
+		return nil, fmt.Errorf("to read osm extract: %w", err)
+	}
+
+	stats := &Stats{Found: len(objects)} //nolint:exhaustruct
+
+	logger.Infof("found %d sport objects in %s", stats.Found, path)
+
+	for i := range objects {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		venue, ok := VenueFromOSM(&objects[i])
+		if !ok {
+			stats.Skipped++
+			continue
+		}
+
+		created, err := storage.UpsertVenueByOSMID(ctx, objects[i].Key(), venue)
+		if err != nil {
+			return stats, fmt.Errorf("to upsert venue %s: %w", objects[i].Key(), err)
+		}
+
+		if created {
+			stats.Created++
+		} else {
+			stats.Updated++
+		}
+
+		if (i+1)%logEvery == 0 {
+			logger.Infof("imported %d of %d objects", i+1, stats.Found)
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,57 @@
+package venueimport_test
+
+import (
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/venueimport"
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/osm"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenueFromOSM(t *testing.T) {
+	t.Parallel()
+
+	object := &osm.Object{
+		Type: osm.ObjectTypeWay,
+		ID:   10,
+		Tags: map[string]string{
+			"leisure":          "pitch",
+			"sport":            "soccer;basketball;soccer",
+			"surface":          "artificial_turf",
+			"lit":              "yes",
+			"addr:city":        "Москва",
+			"addr:street":      "ул Воротынская",
+			"addr:housenumber": "9",
+		},
+		Lat: 55.1,
+		Lon: 37.1,
+	}
+
+	venue, ok := venueimport.VenueFromOSM(object)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, "Спортивная площадка", venue.Name)
+	assert.Equal(t, "Москва, улица Воротынская, 9", venue.Address)
+	assert.Equal(t, []models.SportType{models.SportTypeFootball, models.SportTypeBasketball}, venue.SportTypes)
+	assert.Equal(t, models.VenueSurfaceArtificialGrass, *venue.Surface)
+	assert.Equal(t, []models.VenueAmenity{models.VenueAmenityLighting}, venue.Amenities)
+	assert.False(t, venue.Indoor)
+	assert.Equal(t, "55.1", *venue.Latitude)
+}
+
+func TestVenueFromOSMSkipsUnknownSport(t *testing.T) {
+	t.Parallel()
+
+	object := &osm.Object{
+		Type: osm.ObjectTypeNode,
+		ID:   5,
+		Tags: map[string]string{"leisure": "pitch", "sport": "golf"},
+	}
+
+	_, ok := venueimport.VenueFromOSM(object)
+	assert.False(t, ok)
+}
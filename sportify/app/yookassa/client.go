@@ -9,6 +9,8 @@ import (
 	"strconv"
 
 	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
 )
 
 type Client struct {
@@ -29,7 +31,10 @@ func NewClient(shopID, agentID, tokenPayment, tokenPayout string) *Client {
 	}
 }
 
-const urlPayment = "https://api.yookassa.ru/v3/payments"
+const (
+	urlPayment = "https://api.yookassa.ru/v3/payments"
+	urlRefund  = "https://api.yookassa.ru/v3/refunds"
+)
 
 //nolint:err113
 func (c *Client) DoPayment(
@@ -96,3 +101,47 @@ func (c *Client) DoPayment(
 		Amount:          int64(responseAmount),
 	}, nil
 }
+
+// DoRefund returns money of payment. It returns true when refund succeeded,
+// pending refund is asked again with same idempotency key later.
+//
+//nolint:err113
+func (c *Client) DoRefund(ctx context.Context, idempotencyKey string, paymentID uuid.UUID, amount float64) (bool, error) {
+	payload, err := json.Marshal(NewRequestRefund(paymentID, amount))
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal refund request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlRefund, bytes.NewBuffer(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.SetBasicAuth(c.shopID, c.tokenPayment)
+	req.Header.Set("Idempotence-Key", idempotencyKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("to do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var responseRefund ResponseRefund
+	if err := json.NewDecoder(resp.Body).Decode(&responseRefund); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	switch responseRefund.Status {
+	case "succeeded":
+		return true, nil
+	case "pending":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected refund status: %s", responseRefund.Status)
+	}
+}
@@ -50,3 +50,29 @@ type ResponsePayment struct {
 		ConfirmationURL string `json:"confirmation_url"`
 	} `json:"confirmation"`
 }
+
+type RequestRefund struct {
+	Amount struct {
+		Value    string `json:"value"`
+		Currency string `json:"currency"`
+	} `json:"amount"`
+	PaymentID uuid.UUID `json:"payment_id"`
+}
+
+func NewRequestRefund(paymentID uuid.UUID, amount float64) *RequestRefund {
+	return &RequestRefund{
+		Amount: struct {
+			Value    string `json:"value"`
+			Currency string `json:"currency"`
+		}{
+			Value:    fmt.Sprintf("%.2f", amount),
+			Currency: "RUB",
+		},
+		PaymentID: paymentID,
+	}
+}
+
+type ResponseRefund struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/config"
+	"github.com/TheVovchenskiy/sportify-backend/app/venueimport"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+
+	"github.com/spf13/cobra"
+)
+
+var importVenuesCmd = &cobra.Command{
+	Use:   "import-venues <extract.osm.pbf|extract.osm|extract.osm.bz2>",
+	Short: "Imports venues from OpenStreetMap extract.",
+	Long: "Use this command to fill venue catalog from local OpenStreetMap extract. " +
+		"It takes leisure=pitch, leisure=sports_centre and sport=* objects and upserts them by OSM id.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPaths, err := cmd.Flags().GetStringSlice("config-path")
+		if err != nil {
+			return err
+		}
+
+		err = config.InitConfig(configPaths)
+		if err != nil {
+			return err
+		}
+
+		cfg := config.GetGlobalConfig()
+
+		logger, err := mylogger.New(cfg.Logger.LoggerOutput, cfg.Logger.LoggerErrOutput, cfg.Logger.ProductionMode)
+		if err != nil {
+			return err
+		}
+		defer logger.Sync()
+
+		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		postgresStorage, pool, err := db.NewPostgresStorage(ctx, cfg.Postgres.URL)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+
+		stats, err := venueimport.Import(ctx, postgresStorage, logger, args[0])
+		if err != nil {
+			return fmt.Errorf("to import venues: %w", err)
+		}
+
+		logger.Infof("venues import finished: found=%d created=%d updated=%d skipped=%d",
+			stats.Found, stats.Created, stats.Updated, stats.Skipped)
+
+		return nil
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(importVenuesCmd)
+
+	//nolint:lll
+	importVenuesCmd.Flags().StringSliceP("config-path", "c", []string{}, "Path to config file dir to search in for config. Can be accepted multiple times.")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/TheVovchenskiy/sportify-backend/app/config"
+	"github.com/TheVovchenskiy/sportify-backend/app/filemigrate"
+	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+	"github.com/TheVovchenskiy/sportify-backend/server"
+
+	"github.com/spf13/cobra"
+)
+
+var errFilesNotMigrated = errors.New("some files are not migrated")
+
+var migrateFilesCmd = &cobra.Command{
+	Use:   "migrate-files",
+	Short: "Copies files from disk to s3 bucket.",
+	Long: "Use this command before switching file_storage.type to s3. " +
+		"It copies all files of app.path_photos to bucket from file_storage.s3 and compares sha256 of copies. " +
+		"Files already copied are skipped, so command can be run again after failure.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		configPaths, err := cmd.Flags().GetStringSlice("config-path")
+		if err != nil {
+			return err
+		}
+
+		err = config.InitConfig(configPaths)
+		if err != nil {
+			return err
+		}
+
+		cfg := config.GetGlobalConfig()
+
+		logger, err := mylogger.New(cfg.Logger.LoggerOutput, cfg.Logger.LoggerErrOutput, cfg.Logger.ProductionMode)
+		if err != nil {
+			return err
+		}
+		defer logger.Sync()
+
+		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		fsStorage, err := db.NewFileSystemStorage(cfg.App.PathPhotos)
+		if err != nil {
+			return fmt.Errorf("to new fs storage: %w", err)
+		}
+
+		s3Storage, err := server.NewS3Storage(cfg)
+		if err != nil {
+			return err
+		}
+
+		stats, err := filemigrate.Migrate(ctx, fsStorage, s3Storage, logger)
+		if err != nil {
+			return fmt.Errorf("to migrate files: %w", err)
+		}
+
+		logger.Infof("files migration finished: found=%d uploaded=%d skipped=%d failed=%d",
+			stats.Found, stats.Uploaded, stats.Skipped, stats.Failed)
+
+		if stats.Failed > 0 {
+			return fmt.Errorf("%w: %d", errFilesNotMigrated, stats.Failed)
+		}
+
+		return nil
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(migrateFilesCmd)
+
+	//nolint:lll
+	migrateFilesCmd.Flags().StringSliceP("config-path", "c", []string{}, "Path to config file dir to search in for config. Can be accepted multiple times.")
+}
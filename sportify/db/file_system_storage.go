@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 )
 
@@ -81,3 +82,27 @@ func (f *FileSystemStorage) SaveFile(_ context.Context, content []byte, fileName
 
 	return nil
 }
+
+func (f *FileSystemStorage) ReadFile(_ context.Context, fileName string) ([]byte, error) {
+	content, err := os.ReadFile(f.baseDir + "/" + fileName)
+	if err != nil {
+		return nil, fmt.Errorf("to read file: %w", err)
+	}
+
+	return content, nil
+}
+
+// ListFiles returns names of all files, it is used to move files to another storage.
+func (f *FileSystemStorage) ListFiles(_ context.Context) ([]string, error) {
+	f.muMapExistenceFiles.RLock()
+	defer f.muMapExistenceFiles.RUnlock()
+
+	result := make([]string, 0, len(f.mapExistenceFiles))
+	for fileName := range f.mapExistenceFiles {
+		result = append(result, fileName)
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS event_club_id_index;
+
+ALTER TABLE "public".event DROP COLUMN IF EXISTS club_id;
+
+DROP TABLE IF EXISTS "public".club_member;
+
+DROP TRIGGER IF EXISTS verify_updated_at_club ON public."club";
+
+DROP TABLE IF EXISTS "public".club;
+
+DROP TYPE IF EXISTS club_role_enum;
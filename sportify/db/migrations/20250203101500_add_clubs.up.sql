@@ -0,0 +1,47 @@
+DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT * FROM pg_type WHERE typname = 'club_role_enum') THEN
+            CREATE TYPE club_role_enum AS ENUM ('member', 'admin');
+        END IF;
+    END
+$$;
+
+CREATE TABLE IF NOT EXISTS "public".club
+(
+    id uuid NOT NULL PRIMARY KEY,
+    creator_id uuid NOT NULL,
+    name TEXT NOT NULL
+        CONSTRAINT max_len_name CHECK (LENGTH(name) <= 256 AND LENGTH(name) > 0),
+    description TEXT
+        CONSTRAINT max_len_description CHECK (LENGTH(description) <= 4096),
+    avatar_url TEXT,
+    tg_chat_ids BIGINT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS club_tg_chat_ids_index ON "public".club USING GIN (tg_chat_ids);
+CREATE INDEX IF NOT EXISTS club_name_lower_index ON "public".club (LOWER(name));
+
+DROP TRIGGER IF EXISTS verify_updated_at_club ON public."club";
+CREATE TRIGGER verify_updated_at_club
+    BEFORE UPDATE
+    ON public."club"
+    FOR EACH ROW
+EXECUTE PROCEDURE updated_at_now();
+
+CREATE TABLE IF NOT EXISTS "public".club_member
+(
+    club_id uuid NOT NULL REFERENCES "public".club (id) ON DELETE CASCADE,
+    user_id uuid NOT NULL,
+    role club_role_enum NOT NULL DEFAULT 'member',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (club_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS club_member_user_id_index ON "public".club_member (user_id);
+
+ALTER TABLE "public".event ADD COLUMN IF NOT EXISTS club_id uuid REFERENCES "public".club (id);
+
+CREATE INDEX IF NOT EXISTS event_club_id_index ON "public".event (club_id);
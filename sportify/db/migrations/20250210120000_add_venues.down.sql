@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS event_venue_id_index;
+
+ALTER TABLE "public".event DROP COLUMN IF EXISTS venue_id;
+
+DROP TRIGGER IF EXISTS verify_updated_at_venue ON public."venue";
+
+DROP TABLE IF EXISTS "public".venue;
+
+DROP TYPE IF EXISTS venue_surface_enum;
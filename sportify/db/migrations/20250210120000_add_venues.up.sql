@@ -0,0 +1,47 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT * FROM pg_type WHERE typname = 'venue_surface_enum') THEN
+            CREATE TYPE venue_surface_enum AS ENUM (
+                'grass', 'artificial_grass', 'parquet', 'rubber', 'asphalt', 'ice', 'sand', 'clay', 'other'
+            );
+        END IF;
+    END
+$$;
+
+CREATE TABLE IF NOT EXISTS "public".venue
+(
+    id uuid NOT NULL PRIMARY KEY,
+    creator_id uuid,
+    name TEXT NOT NULL
+        CONSTRAINT max_len_name CHECK (LENGTH(name) <= 256 AND LENGTH(name) > 0),
+    address TEXT NOT NULL
+        CONSTRAINT max_len_address CHECK (LENGTH(address) <= 512),
+    normalized_address TEXT NOT NULL,
+    coordinates geography(POINT,4326),
+    surface venue_surface_enum,
+    indoor BOOLEAN NOT NULL DEFAULT FALSE,
+    sport_types sport_type_enum[] NOT NULL DEFAULT '{}',
+    amenities TEXT[] NOT NULL DEFAULT '{}',
+    url_photos TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS venue_coordinates_index ON "public".venue USING GIST (coordinates);
+CREATE INDEX IF NOT EXISTS venue_name_trgm_index ON "public".venue USING GIN (LOWER(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS venue_normalized_address_trgm_index
+    ON "public".venue USING GIN (LOWER(normalized_address) gin_trgm_ops);
+
+DROP TRIGGER IF EXISTS verify_updated_at_venue ON public."venue";
+CREATE TRIGGER verify_updated_at_venue
+    BEFORE UPDATE
+    ON public."venue"
+    FOR EACH ROW
+EXECUTE PROCEDURE updated_at_now();
+
+ALTER TABLE "public".event ADD COLUMN IF NOT EXISTS venue_id uuid REFERENCES "public".venue (id);
+
+CREATE INDEX IF NOT EXISTS event_venue_id_index ON "public".event (venue_id);
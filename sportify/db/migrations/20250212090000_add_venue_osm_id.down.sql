@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS venue_osm_id_unique_index;
+
+ALTER TABLE "public".venue DROP COLUMN IF EXISTS osm_id;
@@ -0,0 +1,3 @@
+ALTER TABLE "public".venue ADD COLUMN IF NOT EXISTS osm_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS venue_osm_id_unique_index ON "public".venue (osm_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS verify_updated_at_geocode_cache ON public."geocode_cache";
+
+DROP TABLE IF EXISTS "public".geocode_cache;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "public".geocode_cache
+(
+    address TEXT NOT NULL PRIMARY KEY,
+    coordinates geography(POINT,4326),
+    provider TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS geocode_cache_expires_at_index ON "public".geocode_cache (expires_at);
+
+DROP TRIGGER IF EXISTS verify_updated_at_geocode_cache ON public."geocode_cache";
+CREATE TRIGGER verify_updated_at_geocode_cache
+    BEFORE UPDATE
+    ON public."geocode_cache"
+    FOR EACH ROW
+EXECUTE PROCEDURE updated_at_now();
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS verify_updated_at_coordinates_job ON public."coordinates_job";
+
+DROP TABLE IF EXISTS "public".coordinates_job;
+
+DROP TYPE IF EXISTS coordinates_job_state_enum;
@@ -0,0 +1,29 @@
+DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT * FROM pg_type WHERE typname = 'coordinates_job_state_enum') THEN
+            CREATE TYPE coordinates_job_state_enum AS ENUM ('pending', 'failed');
+        END IF;
+    END
+$$;
+
+CREATE TABLE IF NOT EXISTS "public".coordinates_job
+(
+    event_id uuid NOT NULL PRIMARY KEY REFERENCES "public".event (id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    state coordinates_job_state_enum NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS coordinates_job_next_attempt_at_index
+    ON "public".coordinates_job (next_attempt_at) WHERE state = 'pending';
+
+DROP TRIGGER IF EXISTS verify_updated_at_coordinates_job ON public."coordinates_job";
+CREATE TRIGGER verify_updated_at_coordinates_job
+    BEFORE UPDATE
+    ON public."coordinates_job"
+    FOR EACH ROW
+EXECUTE PROCEDURE updated_at_now();
@@ -0,0 +1,3 @@
+ALTER TABLE "public".geocode_cache DROP COLUMN IF EXISTS details;
+
+ALTER TABLE "public".event DROP COLUMN IF EXISTS address_details;
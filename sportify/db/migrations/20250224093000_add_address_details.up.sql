@@ -0,0 +1,3 @@
+ALTER TABLE "public".event ADD COLUMN IF NOT EXISTS address_details JSONB;
+
+ALTER TABLE "public".geocode_cache ADD COLUMN IF NOT EXISTS details JSONB;
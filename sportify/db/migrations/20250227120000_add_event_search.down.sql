@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS event_search_vector_index;
+
+DROP TRIGGER IF EXISTS search_vector_venue_name ON public."venue";
+
+DROP FUNCTION IF EXISTS venue_name_search_vector_update();
+
+DROP TRIGGER IF EXISTS search_vector_event ON public."event";
+
+DROP FUNCTION IF EXISTS event_search_vector_update();
+
+ALTER TABLE "public".event DROP COLUMN IF EXISTS search_vector;
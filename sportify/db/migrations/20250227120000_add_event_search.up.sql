@@ -0,0 +1,44 @@
+ALTER TABLE "public".event ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- venue name lives in another table, so vector is kept by trigger instead of generated column
+CREATE OR REPLACE FUNCTION event_search_vector_update()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.search_vector =
+        setweight(to_tsvector('russian', COALESCE(NEW.description, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(
+            (SELECT name FROM "public".venue WHERE id = NEW.venue_id), '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(NEW.address, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(NEW.raw_message, '')), 'C');
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_vector_event ON public."event";
+CREATE TRIGGER search_vector_event
+    BEFORE INSERT OR UPDATE OF description, address, raw_message, venue_id
+    ON public."event"
+    FOR EACH ROW
+EXECUTE PROCEDURE event_search_vector_update();
+
+CREATE OR REPLACE FUNCTION venue_name_search_vector_update()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE "public".event SET venue_id = venue_id WHERE venue_id = NEW.id;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_vector_venue_name ON public."venue";
+CREATE TRIGGER search_vector_venue_name
+    AFTER UPDATE OF name
+    ON public."venue"
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE PROCEDURE venue_name_search_vector_update();
+
+UPDATE "public".event SET venue_id = venue_id;
+
+CREATE INDEX IF NOT EXISTS event_search_vector_index ON "public".event USING GIN (search_vector);
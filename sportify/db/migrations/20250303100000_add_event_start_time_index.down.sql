@@ -0,0 +1 @@
+DROP INDEX IF EXISTS event_start_time_index;
@@ -0,0 +1,2 @@
+-- main page and facets always look for upcoming events which are not deleted
+CREATE INDEX IF NOT EXISTS event_start_time_index ON "public".event (start_time) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "public".saved_search_alert;
+
+DROP TRIGGER IF EXISTS verify_updated_at_saved_search ON public."saved_search";
+
+DROP TABLE IF EXISTS "public".saved_search;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS "public".saved_search
+(
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES "public".user (id) ON DELETE CASCADE,
+    name TEXT NOT NULL
+        CONSTRAINT max_len_name CHECK (LENGTH(name) <= 256 AND LENGTH(name) > 0),
+    -- query params of events search, they are parsed again on every match
+    filter_params JSONB NOT NULL,
+    alerts_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    alert_interval_minutes INTEGER NOT NULL DEFAULT 60
+        CONSTRAINT non_negative_alert_interval CHECK (alert_interval_minutes >= 0),
+    last_alert_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_user_id_index ON "public".saved_search (user_id);
+
+DROP TRIGGER IF EXISTS verify_updated_at_saved_search ON public."saved_search";
+CREATE TRIGGER verify_updated_at_saved_search
+    BEFORE UPDATE
+    ON public."saved_search"
+    FOR EACH ROW
+EXECUTE PROCEDURE updated_at_now();
+
+-- saved_search_alert is in-app feed of matched events, it also keeps event from being alerted twice
+CREATE TABLE IF NOT EXISTS "public".saved_search_alert
+(
+    saved_search_id uuid NOT NULL REFERENCES "public".saved_search (id) ON DELETE CASCADE,
+    event_id uuid NOT NULL REFERENCES "public".event (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (saved_search_id, event_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "public".notification_preference;
+
+DROP TABLE IF EXISTS "public".notification;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS "public".notification
+(
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES "public".user (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    event_id uuid REFERENCES "public".event (id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notification_user_id_created_at_index
+    ON "public".notification (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS notification_unread_index
+    ON "public".notification (user_id) WHERE read_at IS NULL;
+
+-- notification_preference keeps only changed preferences, by default every kind goes to every channel
+CREATE TABLE IF NOT EXISTS "public".notification_preference
+(
+    user_id uuid NOT NULL REFERENCES "public".user (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    telegram BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (user_id, kind)
+);
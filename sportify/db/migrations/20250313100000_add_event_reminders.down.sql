@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "public".event_reminder;
+
+ALTER TABLE "public".user DROP COLUMN IF EXISTS reminder_hours;
+
+ALTER TABLE "public".event DROP COLUMN IF EXISTS reminder_hours;
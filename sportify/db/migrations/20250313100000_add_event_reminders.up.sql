@@ -0,0 +1,19 @@
+-- reminder_hours is how many hours before start participants are reminded, 0 turns reminders off,
+-- setting of user is stronger than setting of event
+ALTER TABLE "public".event
+    ADD COLUMN IF NOT EXISTS reminder_hours INTEGER
+        CONSTRAINT reminder_hours_range CHECK (reminder_hours BETWEEN 0 AND 72);
+
+ALTER TABLE "public".user
+    ADD COLUMN IF NOT EXISTS reminder_hours INTEGER
+        CONSTRAINT reminder_hours_range CHECK (reminder_hours BETWEEN 0 AND 72);
+
+-- event_reminder is log of sent reminders, start_time is in key, so moved event is reminded again
+CREATE TABLE IF NOT EXISTS "public".event_reminder
+(
+    event_id uuid NOT NULL REFERENCES "public".event (id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES "public".user (id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id, start_time)
+);
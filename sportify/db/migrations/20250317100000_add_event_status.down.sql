@@ -0,0 +1,6 @@
+-- postgres can't drop value of enum, so refund_pending stays in payment_status_enum
+ALTER TABLE "public".event
+    DROP COLUMN IF EXISTS cancel_reason,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS event_status_enum;
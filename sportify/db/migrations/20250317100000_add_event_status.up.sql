@@ -0,0 +1,15 @@
+DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT * FROM pg_type WHERE typname = 'event_status_enum') THEN
+            CREATE TYPE event_status_enum AS ENUM ('scheduled', 'cancelled', 'finished', 'postponed');
+        END IF;
+    END
+$$;
+
+-- cancelled event is not deleted, participants still see it in archive
+ALTER TABLE "public".event
+    ADD COLUMN IF NOT EXISTS status event_status_enum NOT NULL DEFAULT 'scheduled',
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT;
+
+-- money of cancelled event is returned to participants
+ALTER TYPE payment_status_enum ADD VALUE IF NOT EXISTS 'refund_pending';
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS forbid_update_audit_log ON "public".audit_log;
+
+DROP FUNCTION IF EXISTS audit_log_forbid_update();
+
+DROP TABLE IF EXISTS "public".audit_log;
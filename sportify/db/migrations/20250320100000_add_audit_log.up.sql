@@ -0,0 +1,34 @@
+-- audit_log is append-only history of changes, event_id has no foreign key,
+-- so history stays after event is removed
+CREATE TABLE IF NOT EXISTS "public".audit_log
+(
+    id BIGSERIAL PRIMARY KEY,
+    entity_type TEXT NOT NULL,
+    entity_id uuid NOT NULL,
+    event_id uuid,
+    action TEXT NOT NULL,
+    actor_id uuid,
+    source TEXT NOT NULL CONSTRAINT audit_log_source CHECK (source IN ('site', 'tg', 'admin', 'system')),
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_event_id_index ON "public".audit_log (event_id, id DESC);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_index ON "public".audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_forbid_update()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+-- records are only added and removed by retention, never changed
+DROP TRIGGER IF EXISTS forbid_update_audit_log ON "public".audit_log;
+CREATE TRIGGER forbid_update_audit_log
+    BEFORE UPDATE
+    ON "public".audit_log
+    FOR EACH ROW
+EXECUTE PROCEDURE audit_log_forbid_update();
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS verify_updated_at_event_comment ON public."event_comment";
+
+DROP TABLE IF EXISTS "public".event_comment;
@@ -0,0 +1,23 @@
+-- replies have one level, parent_id always points to top comment of thread
+CREATE TABLE IF NOT EXISTS "public".event_comment
+(
+    id uuid NOT NULL PRIMARY KEY,
+    event_id uuid NOT NULL REFERENCES "public".event (id) ON DELETE CASCADE,
+    author_id uuid NOT NULL REFERENCES "public".user (id) ON DELETE CASCADE,
+    parent_id uuid REFERENCES "public".event_comment (id) ON DELETE CASCADE,
+    text TEXT NOT NULL
+        CONSTRAINT max_len_text CHECK (LENGTH(text) <= 2000),
+    edited_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS event_comment_event_id_index ON "public".event_comment (event_id, created_at);
+
+DROP TRIGGER IF EXISTS verify_updated_at_event_comment ON public."event_comment";
+CREATE TRIGGER verify_updated_at_event_comment
+    BEFORE UPDATE
+    ON public."event_comment"
+    FOR EACH ROW
+EXECUTE PROCEDURE updated_at_now();
@@ -0,0 +1,3 @@
+-- calendar_token is secret of personal calendar feed, it is in url, so calendar apps get feed without login
+ALTER TABLE "public".user
+    ADD COLUMN IF NOT EXISTS calendar_token TEXT UNIQUE;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "public".club_tg_link_code;
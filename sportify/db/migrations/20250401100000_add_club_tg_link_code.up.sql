@@ -0,0 +1,7 @@
+-- code is posted by club admin to telegram chat, chat is linked to club only after bot sees it
+CREATE TABLE IF NOT EXISTS "public".club_tg_link_code
+(
+    code TEXT NOT NULL PRIMARY KEY,
+    club_id uuid NOT NULL REFERENCES "public".club (id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,2 @@
+-- postgres can't drop value of enum, so refunded stays in payment_status_enum
+UPDATE "public".payment SET status = 'refund_pending' WHERE status = 'refunded';
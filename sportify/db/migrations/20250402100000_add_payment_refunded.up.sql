@@ -0,0 +1,2 @@
+-- refund_pending becomes refunded when yookassa returns money
+ALTER TYPE payment_status_enum ADD VALUE IF NOT EXISTS 'refunded';
@@ -0,0 +1 @@
+ALTER TABLE "public".event DROP COLUMN IF EXISTS mirror_comments_to_tg;
@@ -0,0 +1,3 @@
+-- organizer decides if comments of event are sent to chat of telegram post
+ALTER TABLE "public".event
+    ADD COLUMN IF NOT EXISTS mirror_comments_to_tg BOOLEAN NOT NULL DEFAULT FALSE;
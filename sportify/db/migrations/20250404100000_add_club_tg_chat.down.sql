@@ -0,0 +1,8 @@
+ALTER TABLE "public".club ADD COLUMN IF NOT EXISTS tg_chat_ids BIGINT[] NOT NULL DEFAULT '{}';
+
+UPDATE "public".club c SET tg_chat_ids = ARRAY(
+    SELECT t.tg_chat_id FROM "public".club_tg_chat t WHERE t.club_id = c.id ORDER BY t.created_at);
+
+CREATE INDEX IF NOT EXISTS club_tg_chat_ids_index ON "public".club USING GIN (tg_chat_ids);
+
+DROP TABLE IF EXISTS "public".club_tg_chat;
@@ -0,0 +1,22 @@
+-- telegram chat belongs to one club, primary key keeps it so when link codes are used at the same time
+CREATE TABLE IF NOT EXISTS "public".club_tg_chat
+(
+    tg_chat_id BIGINT NOT NULL PRIMARY KEY,
+    club_id uuid NOT NULL REFERENCES "public".club (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS club_tg_chat_club_id_index ON "public".club_tg_chat (club_id);
+
+-- chat linked to several clubs stays with the oldest one, it was chosen for events before
+INSERT INTO "public".club_tg_chat (tg_chat_id, club_id)
+SELECT DISTINCT ON (chat.id) chat.id, c.id
+FROM "public".club c
+    CROSS JOIN unnest(c.tg_chat_ids) AS chat(id)
+WHERE c.deleted_at IS NULL
+ORDER BY chat.id, c.created_at
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS club_tg_chat_ids_index;
+
+ALTER TABLE "public".club DROP COLUMN IF EXISTS tg_chat_ids;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS saved_search_area_index;
+
+ALTER TABLE "public".saved_search
+    DROP COLUMN IF EXISTS sport_types,
+    DROP COLUMN IF EXISTS area;
@@ -0,0 +1,12 @@
+-- filters of search which are cheap to check are kept in columns, so new event is matched
+-- only against searches which can find it
+ALTER TABLE "public".saved_search
+    ADD COLUMN IF NOT EXISTS sport_types TEXT[] NOT NULL DEFAULT '{}',
+    -- area covers point with radius or bbox of search, search without place has no area
+    ADD COLUMN IF NOT EXISTS area geography;
+
+UPDATE "public".saved_search
+SET sport_types = ARRAY(SELECT jsonb_array_elements_text(filter_params -> 'sport_type'))
+WHERE jsonb_typeof(filter_params -> 'sport_type') = 'array';
+
+CREATE INDEX IF NOT EXISTS saved_search_area_index ON "public".saved_search USING GIST (area) WHERE alerts_enabled;
@@ -75,3 +75,35 @@ func (p *PostgresPaymentPayoutStorage) UpdateStatusPayment(
 
 	return nil
 }
+
+// RefundEventPayments marks paid payments of event as waiting for refund and returns them.
+func (p *PostgresPaymentPayoutStorage) RefundEventPayments(
+	ctx context.Context,
+	eventID uuid.UUID,
+) ([]models.Payment, error) {
+	sqlUpdate := `
+	UPDATE public.payment SET status = $1 WHERE event_id = $2 AND status = $3
+		RETURNING id, user_id, event_id, confirmation_url, status, amount`
+
+	rows, err := p.pool.Query(ctx, sqlUpdate, models.PaymentStatusRefundPending, eventID, models.PaymentStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[models.Payment])
+}
+
+func (p *PostgresPaymentPayoutStorage) GetPaymentsByStatus(
+	ctx context.Context,
+	status models.PaymentStatus,
+) ([]models.Payment, error) {
+	sqlSelect := `
+	SELECT id, user_id, event_id, confirmation_url, status, amount FROM public.payment WHERE status = $1`
+
+	rows, err := p.pool.Query(ctx, sqlSelect, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[models.Payment])
+}
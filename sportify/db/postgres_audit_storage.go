@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+func (p *PostgresStorage) AddAuditRecord(ctx context.Context, record *models.AuditRecord) error {
+	sqlInsert := `
+	INSERT INTO "public".audit_log (entity_type, entity_id, event_id, action, actor_id, source, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+
+	_, err := p.pool.Exec(ctx, sqlInsert,
+		string(record.EntityType), record.EntityID, record.EventID, string(record.Action), record.ActorID,
+		string(record.Source), record.Before, record.After)
+	if err != nil {
+		return fmt.Errorf("to insert audit record: %w", err)
+	}
+
+	return nil
+}
+
+// GetEventHistory returns changes of event, its participants and payments from the latest one.
+func (p *PostgresStorage) GetEventHistory(ctx context.Context, eventID uuid.UUID, limit int) (
+	[]models.AuditRecord, error,
+) {
+	sqlSelect := `
+	SELECT id, entity_type, entity_id, event_id, action, actor_id, source, before, after, created_at
+	FROM "public".audit_log
+	WHERE event_id = $1
+	ORDER BY id DESC
+	LIMIT $2;`
+
+	rows, err := p.pool.Query(ctx, sqlSelect, eventID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to select audit records: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.AuditRecord, error) {
+		var record models.AuditRecord
+
+		err := row.Scan(&record.ID, &record.EntityType, &record.EntityID, &record.EventID, &record.Action,
+			&record.ActorID, &record.Source, &record.Before, &record.After, &record.CreatedAt)
+
+		return record, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to collect audit records: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) DeleteAuditRecordsBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM "public".audit_log WHERE created_at < $1;`, before)
+	if err != nil {
+		return 0, fmt.Errorf("to delete audit records: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
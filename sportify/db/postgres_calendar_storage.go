@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *PostgresStorage) GetCalendarToken(ctx context.Context, userID uuid.UUID) (*string, error) {
+	var token *string
+
+	err := p.pool.QueryRow(ctx, `SELECT calendar_token FROM "public".user WHERE id = $1;`, userID).Scan(&token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("to select calendar token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (p *PostgresStorage) SetCalendarToken(ctx context.Context, userID uuid.UUID, token string) error {
+	_, err := p.pool.Exec(ctx, `UPDATE "public".user SET calendar_token = $1 WHERE id = $2;`, token, userID)
+	if err != nil {
+		return fmt.Errorf("to update calendar token: %w", err)
+	}
+
+	return nil
+}
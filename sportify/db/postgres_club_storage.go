@@ -0,0 +1,347 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrNotFoundClub       = errors.New("Не найден клуб")
+	ErrNotFoundClubMember = errors.New("Пользователь не состоит в клубе")
+	ErrClubMemberExist    = errors.New("Пользователь уже состоит в клубе")
+	ErrNotFoundClubTgCode = errors.New("Код привязки чата не найден или просрочен")
+	ErrClubTgChatTaken    = errors.New("Телеграм чат привязан к другому клубу")
+)
+
+func (p *PostgresStorage) CreateClub(ctx context.Context, club *models.Club) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	sqlInsertClub := `
+	INSERT INTO "public".club (id, creator_id, name, description, avatar_url)
+		VALUES ($1, $2, $3, $4, $5);`
+
+	_, err = tx.Exec(ctx, sqlInsertClub, club.ID, club.CreatorID, club.Name, club.Description, club.AvatarURL)
+	if err != nil {
+		return fmt.Errorf("to insert club: %w", err)
+	}
+
+	sqlInsertMember := `
+	INSERT INTO "public".club_member (club_id, user_id, role) VALUES ($1, $2, $3);`
+
+	_, err = tx.Exec(ctx, sqlInsertMember, club.ID, club.CreatorID, models.ClubRoleAdmin)
+	if err != nil {
+		return fmt.Errorf("to insert club admin: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// EditClub updates club, telegram chats missing in club are unlinked, new ones are linked only by code.
+func (p *PostgresStorage) EditClub(ctx context.Context, club *models.Club) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	sqlUpdate := `
+	UPDATE "public".club SET name = $1, description = $2, avatar_url = $3
+		WHERE id = $4 AND deleted_at IS NULL;`
+
+	tag, err := tx.Exec(ctx, sqlUpdate, club.Name, club.Description, club.AvatarURL, club.ID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundClub
+	}
+
+	sqlDeleteChats := `
+	DELETE FROM "public".club_tg_chat WHERE club_id = $1 AND NOT tg_chat_id = ANY($2);`
+
+	_, err = tx.Exec(ctx, sqlDeleteChats, club.ID, club.TgChatIDs)
+	if err != nil {
+		return fmt.Errorf("to unlink tg chats: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+const sqlSelectClub = `
+	SELECT c.id, c.creator_id, c.name, c.description, c.avatar_url,
+		ARRAY(SELECT t.tg_chat_id FROM "public".club_tg_chat t WHERE t.club_id = c.id ORDER BY t.created_at),
+		c.created_at,
+		(SELECT COUNT(*) FROM "public".club_member m WHERE m.club_id = c.id) AS count_member
+	FROM "public".club c`
+
+func scanClub(row pgx.Row) (*models.Club, error) {
+	var (
+		club         models.Club
+		rawTgChatIDs pgtype.Array[int64]
+	)
+
+	err := row.Scan(&club.ID, &club.CreatorID, &club.Name, &club.Description, &club.AvatarURL,
+		&rawTgChatIDs, &club.CreatedAt, &club.CountMember)
+	if err != nil {
+		return nil, err
+	}
+
+	club.TgChatIDs = rawTgChatIDs.Elements
+	if club.TgChatIDs == nil {
+		club.TgChatIDs = []int64{}
+	}
+
+	return &club, nil
+}
+
+func (p *PostgresStorage) GetClub(ctx context.Context, id uuid.UUID) (*models.Club, error) {
+	sqlSelect := sqlSelectClub + ` WHERE c.id = $1 AND c.deleted_at IS NULL;`
+
+	club, err := scanClub(p.pool.QueryRow(ctx, sqlSelect, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFoundClub
+		}
+
+		return nil, fmt.Errorf("to scan club: %w", err)
+	}
+
+	return club, nil
+}
+
+func (p *PostgresStorage) GetClubByTgChatID(ctx context.Context, tgChatID int64) (*models.Club, error) {
+	sqlSelect := sqlSelectClub + ` WHERE c.id = (SELECT club_id FROM "public".club_tg_chat WHERE tg_chat_id = $1)
+		AND c.deleted_at IS NULL;`
+
+	club, err := scanClub(p.pool.QueryRow(ctx, sqlSelect, tgChatID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFoundClub
+		}
+
+		return nil, fmt.Errorf("to scan club: %w", err)
+	}
+
+	return club, nil
+}
+
+func (p *PostgresStorage) CreateClubTgLinkCode(ctx context.Context, clubID uuid.UUID, code string, expiresAt time.Time) error {
+	sqlInsert := `
+	INSERT INTO "public".club_tg_link_code (code, club_id, expires_at) VALUES ($1, $2, $3);`
+
+	_, err := p.pool.Exec(ctx, sqlInsert, code, clubID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("to insert club tg link code: %w", err)
+	}
+
+	return nil
+}
+
+// LinkClubTgChatByCode uses code once and adds chat to club of code. Code stays unused if club is deleted
+// or chat belongs to another club, posting code to chat of the same club again is not an error.
+func (p *PostgresStorage) LinkClubTgChatByCode(ctx context.Context, code string, tgChatID int64) (uuid.UUID, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	sqlDeleteCode := `
+	DELETE FROM "public".club_tg_link_code WHERE code = $1 AND expires_at > NOW() RETURNING club_id;`
+
+	var clubID uuid.UUID
+
+	err = tx.QueryRow(ctx, sqlDeleteCode, code).Scan(&clubID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNotFoundClubTgCode
+		}
+
+		return uuid.Nil, fmt.Errorf("to delete club tg link code: %w", err)
+	}
+
+	// lock keeps club from being deleted until chat is linked
+	sqlLockClub := `
+	SELECT id FROM "public".club WHERE id = $1 AND deleted_at IS NULL FOR SHARE;`
+
+	err = tx.QueryRow(ctx, sqlLockClub, clubID).Scan(&clubID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNotFoundClub
+		}
+
+		return uuid.Nil, fmt.Errorf("to lock club: %w", err)
+	}
+
+	// insert waits for concurrent link of the same chat, next statement sees its owner.
+	// Chat of deleted club is free to link.
+	sqlInsertChat := `
+	INSERT INTO "public".club_tg_chat (tg_chat_id, club_id) VALUES ($1, $2)
+		ON CONFLICT (tg_chat_id) DO UPDATE SET club_id = EXCLUDED.club_id, created_at = NOW()
+			WHERE club_tg_chat.club_id IN (SELECT id FROM "public".club WHERE deleted_at IS NOT NULL);`
+
+	tag, err := tx.Exec(ctx, sqlInsertChat, tgChatID, clubID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("to insert club tg chat: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		var ownerID uuid.UUID
+
+		err = tx.QueryRow(ctx, `SELECT club_id FROM "public".club_tg_chat WHERE tg_chat_id = $1;`, tgChatID).
+			Scan(&ownerID)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("to select owner of tg chat: %w", err)
+		}
+
+		if ownerID != clubID {
+			return uuid.Nil, ErrClubTgChatTaken
+		}
+	}
+
+	return clubID, tx.Commit(ctx)
+}
+
+func (p *PostgresStorage) FindClubs(ctx context.Context, query string) ([]models.Club, error) {
+	sqlSelect := sqlSelectClub + ` WHERE c.deleted_at IS NULL AND ($1 = '' OR c.name ILIKE '%' || $1 || '%')
+		ORDER BY count_member DESC, c.name LIMIT 100;`
+
+	rawRows, err := p.pool.Query(ctx, sqlSelect, query)
+	if err != nil {
+		return nil, fmt.Errorf("to select clubs: %w", err)
+	}
+	defer rawRows.Close()
+
+	result := []models.Club{}
+
+	for rawRows.Next() {
+		club, err := scanClub(rawRows)
+		if err != nil {
+			return nil, fmt.Errorf("to scan club: %w", err)
+		}
+
+		result = append(result, *club)
+	}
+
+	if err := rawRows.Err(); err != nil {
+		return nil, fmt.Errorf("to read clubs: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) GetClubMembers(ctx context.Context, clubID uuid.UUID) ([]models.ClubMember, error) {
+	sqlSelect := `
+	SELECT user_id, role, created_at FROM "public".club_member WHERE club_id = $1
+		ORDER BY role DESC, created_at;`
+
+	rawRows, err := p.pool.Query(ctx, sqlSelect, clubID)
+	if err != nil {
+		return nil, fmt.Errorf("to select club members: %w", err)
+	}
+
+	var curMember models.ClubMember
+
+	result := []models.ClubMember{}
+
+	_, err = pgx.ForEachRow(rawRows, []any{&curMember.UserID, &curMember.Role, &curMember.CreatedAt}, func() error {
+		result = append(result, curMember)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to scan club members: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) GetClubRole(ctx context.Context, clubID, userID uuid.UUID) (models.ClubRole, error) {
+	sqlSelect := `SELECT role FROM "public".club_member WHERE club_id = $1 AND user_id = $2;`
+
+	var role models.ClubRole
+
+	err := p.pool.QueryRow(ctx, sqlSelect, clubID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFoundClubMember
+		}
+
+		return "", fmt.Errorf("to scan club role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (p *PostgresStorage) AddClubMember(ctx context.Context, clubID, userID uuid.UUID, role models.ClubRole) error {
+	sqlInsert := `
+	INSERT INTO "public".club_member (club_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (club_id, user_id) DO NOTHING;`
+
+	tag, err := p.pool.Exec(ctx, sqlInsert, clubID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrClubMemberExist
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) SetClubMemberRole(ctx context.Context, clubID, userID uuid.UUID, role models.ClubRole) error {
+	sqlUpdate := `UPDATE "public".club_member SET role = $1 WHERE club_id = $2 AND user_id = $3;`
+
+	tag, err := p.pool.Exec(ctx, sqlUpdate, role, clubID, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundClubMember
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) RemoveClubMember(ctx context.Context, clubID, userID uuid.UUID) error {
+	sqlDelete := `DELETE FROM "public".club_member WHERE club_id = $1 AND user_id = $2;`
+
+	tag, err := p.pool.Exec(ctx, sqlDelete, clubID, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundClubMember
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) CountClubAdmins(ctx context.Context, clubID uuid.UUID) (int, error) {
+	sqlSelect := `SELECT COUNT(*) FROM "public".club_member WHERE club_id = $1 AND role = 'admin';`
+
+	var count int
+
+	err := p.pool.QueryRow(ctx, sqlSelect, clubID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("to count club admins: %w", err)
+	}
+
+	return count, nil
+}
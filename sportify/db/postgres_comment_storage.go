@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+var ErrNotFoundComment = errors.New("Комментарий не найден")
+
+// text of deleted comment is hidden, comment itself stays for its replies
+const sqlSelectComment = `
+	SELECT id, event_id, author_id, parent_id,
+		CASE WHEN deleted_at IS NULL THEN text ELSE '' END, deleted_at IS NOT NULL, edited_at, created_at
+	FROM "public".event_comment`
+
+func scanComment(row pgx.CollectableRow) (models.Comment, error) {
+	var comment models.Comment
+
+	err := row.Scan(&comment.ID, &comment.EventID, &comment.AuthorID, &comment.ParentID,
+		&comment.Text, &comment.Deleted, &comment.EditedAt, &comment.CreatedAt)
+
+	return comment, err
+}
+
+func (p *PostgresStorage) CreateComment(ctx context.Context, comment *models.Comment) error {
+	sqlInsert := `
+	INSERT INTO "public".event_comment (id, event_id, author_id, parent_id, text, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6);`
+
+	_, err := p.pool.Exec(ctx, sqlInsert,
+		comment.ID, comment.EventID, comment.AuthorID, comment.ParentID, comment.Text, comment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("to insert comment: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) GetComment(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	rows, err := p.pool.Query(ctx, sqlSelectComment+` WHERE id = $1;`, id)
+	if err != nil {
+		return nil, fmt.Errorf("to select comment: %w", err)
+	}
+
+	comment, err := pgx.CollectExactlyOneRow(rows, scanComment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFoundComment
+		}
+
+		return nil, fmt.Errorf("to collect comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// GetEventComments returns thread of event from the oldest comment.
+func (p *PostgresStorage) GetEventComments(ctx context.Context, eventID uuid.UUID) ([]models.Comment, error) {
+	rows, err := p.pool.Query(ctx, sqlSelectComment+` WHERE event_id = $1 ORDER BY created_at, id;`, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("to select comments: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, scanComment)
+	if err != nil {
+		return nil, fmt.Errorf("to collect comments: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) EditComment(ctx context.Context, id uuid.UUID, text string, editedAt time.Time) error {
+	tag, err := p.pool.Exec(ctx,
+		`UPDATE "public".event_comment SET text = $1, edited_at = $2 WHERE id = $3 AND deleted_at IS NULL;`,
+		text, editedAt, id)
+	if err != nil {
+		return fmt.Errorf("to update comment: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundComment
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) DeleteComment(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx,
+		`UPDATE "public".event_comment SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL;`, id)
+	if err != nil {
+		return fmt.Errorf("to delete comment: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundComment
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) SetCommentsMirrorToTg(ctx context.Context, eventID uuid.UUID, mirror bool) error {
+	tag, err := p.pool.Exec(ctx,
+		`UPDATE "public".event SET mirror_comments_to_tg = $1 WHERE id = $2 AND deleted_at IS NULL;`, mirror, eventID)
+	if err != nil {
+		return fmt.Errorf("to update comments mirror: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundEvent
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) GetCommentsMirrorToTg(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	var mirror bool
+
+	err := p.pool.QueryRow(ctx,
+		`SELECT mirror_comments_to_tg FROM "public".event WHERE id = $1;`, eventID).Scan(&mirror)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrNotFoundEvent
+		}
+
+		return false, fmt.Errorf("to select comments mirror: %w", err)
+	}
+
+	return mirror, nil
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// EnqueueCoordinatesJobs adds jobs for events with missing or expired coordinates.
+// Events at venue get coordinates from venue, so they are skipped. Failed job is
+// reset only when address of event was changed.
+func (p *PostgresStorage) EnqueueCoordinatesJobs(ctx context.Context) (int64, error) {
+	sqlInsert := `
+	INSERT INTO "public".coordinates_job (event_id, address)
+		SELECT id, address FROM "public".event
+		WHERE deleted_at IS NULL AND venue_id IS NULL AND address <> ''
+			AND (coordinates IS NULL OR expiration_time_coordinates IS NULL OR expiration_time_coordinates < NOW())
+	ON CONFLICT (event_id) DO UPDATE SET address = EXCLUDED.address, state = 'pending',
+		attempts = 0, next_attempt_at = NOW(), last_error = NULL
+		WHERE coordinates_job.address <> EXCLUDED.address;`
+
+	tag, err := p.pool.Exec(ctx, sqlInsert)
+	if err != nil {
+		return 0, fmt.Errorf("to insert coordinates jobs: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ClaimCoordinatesJobs takes ready jobs and postpones them for lease, so other
+// instances skip them. If instance dies before finishing, jobs become ready again
+// after lease.
+func (p *PostgresStorage) ClaimCoordinatesJobs(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]models.CoordinatesJob, error) {
+	sqlUpdate := `
+	UPDATE "public".coordinates_job SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE event_id IN (
+			SELECT event_id FROM "public".coordinates_job
+			WHERE state = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING event_id, address, attempts;`
+
+	rawRows, err := p.pool.Query(ctx, sqlUpdate, limit, time.Now().Add(lease))
+	if err != nil {
+		return nil, fmt.Errorf("to claim coordinates jobs: %w", err)
+	}
+
+	var job models.CoordinatesJob
+
+	result := []models.CoordinatesJob{}
+
+	_, err = pgx.ForEachRow(rawRows, []any{&job.EventID, &job.Address, &job.Attempts}, func() error {
+		result = append(result, job)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to read coordinates jobs: %w", err)
+	}
+
+	return result, nil
+}
+
+// CompleteCoordinatesJob sets coordinates and canonical address of event and removes its job.
+func (p *PostgresStorage) CompleteCoordinatesJob(
+	ctx context.Context,
+	eventID uuid.UUID,
+	latitude, longitude string,
+	details *models.AddressDetails,
+) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	sqlUpdate := `UPDATE public.event SET coordinates = ST_Point($1, $2, 4326)::geography,
+		expiration_time_coordinates = NOW() + interval '29' day, address_details = $3 WHERE id = $4`
+
+	_, err = tx.Exec(ctx, sqlUpdate, latitude, longitude, details, eventID)
+	if err != nil {
+		return fmt.Errorf("to set coordinates: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM "public".coordinates_job WHERE event_id = $1`, eventID)
+	if err != nil {
+		return fmt.Errorf("to delete coordinates job: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresStorage) FailCoordinatesJob(
+	ctx context.Context,
+	eventID uuid.UUID,
+	state models.CoordinatesJobState,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	sqlUpdate := `
+	UPDATE "public".coordinates_job SET state = $1, next_attempt_at = $2, last_error = $3
+		WHERE event_id = $4;`
+
+	_, err := p.pool.Exec(ctx, sqlUpdate, state, nextAttemptAt, lastError, eventID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+const eventChangesChannel = "event_changes"
+
+func (p *PostgresStorage) NotifyEventChange(ctx context.Context, payload []byte) error {
+	_, err := p.pool.Exec(ctx, `SELECT pg_notify($1, $2);`, eventChangesChannel, string(payload))
+	if err != nil {
+		return fmt.Errorf("to notify event change: %w", err)
+	}
+
+	return nil
+}
+
+// ListenEventChanges holds one connection of pool and calls handle for every change
+// until ctx is done or connection is lost.
+func (p *PostgresStorage) ListenEventChanges(ctx context.Context, handle func(payload []byte)) error {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+eventChangesChannel)
+	if err != nil {
+		return fmt.Errorf("to listen event changes: %w", err)
+	}
+
+	defer func() {
+		// connection goes back to pool, so it must not get notifications anymore
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "UNLISTEN "+eventChangesChannel)
+	}()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("to wait notification: %w", err)
+		}
+
+		handle([]byte(notification.Payload))
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+func facetQuery(value squirrel.Sqlizer, filterParams *models.FilterParams) squirrel.SelectBuilder {
+	query := squirrel.Select().
+		Column(value).
+		Column("COUNT(*)").
+		From(`"public".event`).
+		PlaceholderFormat(squirrel.Dollar).
+		Where(squirrel.Eq{"deleted_at": nil})
+
+	return applyFilterParams(query, filterParams)
+}
+
+// FindEventFacets counts all facets in one round trip, every facet is a separate
+// GROUP BY without its own filter.
+func (p *PostgresStorage) FindEventFacets(
+	ctx context.Context,
+	filterParams *models.FilterParams,
+	today time.Time,
+) (*models.EventFacets, error) {
+	priceBucket := squirrel.Expr(`CASE WHEN price = 0 THEN ? WHEN price <= 500 THEN ?
+		WHEN price <= 1000 THEN ? ELSE ? END AS bucket`,
+		models.PriceBucketFree, models.PriceBucketTo500, models.PriceBucketTo1000, models.PriceBucketFrom1000)
+
+	queries := []squirrel.SelectBuilder{
+		facetQuery(squirrel.Expr("sport_type::text"), filterParams.WithoutSportTypes()).
+			GroupBy("sport_type").OrderBy("COUNT(*) DESC"),
+		facetQuery(squirrel.Expr("level::text"), filterParams.WithoutGameLevels()).
+			JoinClause("CROSS JOIN unnest(game_level) AS level").
+			GroupBy("level").OrderBy("COUNT(*) DESC"),
+		facetQuery(priceBucket, filterParams.WithoutPrice()).
+			GroupBy("bucket").OrderBy("MIN(price)"),
+		facetQuery(squirrel.Expr("to_char(date_start AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day"),
+			filterParams.WithoutDates()).
+			Where(squirrel.GtOrEq{"date_start": today}).
+			Where(squirrel.Lt{"date_start": today.AddDate(0, 0, models.FacetDays)}).
+			GroupBy("day").OrderBy("day"),
+	}
+
+	batch := &pgx.Batch{}
+
+	for _, query := range queries {
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("query to sql: %w", err)
+		}
+
+		batch.Queue(sql, args...)
+	}
+
+	results := p.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	facets := make([][]models.FacetValue, len(queries))
+
+	for i := range queries {
+		rows, err := results.Query()
+		if err != nil {
+			return nil, fmt.Errorf("to select facet: %w", err)
+		}
+
+		facets[i], err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.FacetValue, error) {
+			var value models.FacetValue
+
+			err := row.Scan(&value.Value, &value.Count)
+
+			return value, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("to collect facet: %w", err)
+		}
+	}
+
+	return &models.EventFacets{
+		SportTypes:   facets[0],
+		GameLevels:   facets[1],
+		PriceBuckets: facets[2],
+		Dates:        facets[3],
+	}, nil
+}
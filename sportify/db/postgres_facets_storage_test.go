@@ -0,0 +1,33 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacetQueryDropsOwnFilter(t *testing.T) {
+	t.Parallel()
+
+	priceMin := 500
+	filterParams := &models.FilterParams{ //nolint:exhaustruct
+		SportTypes: []models.SportType{models.SportTypeFootball},
+		PriceMin:   &priceMin,
+	}
+
+	sql, args, err := facetQuery(squirrel.Expr("sport_type::text"), filterParams.WithoutSportTypes()).
+		GroupBy("sport_type").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT sport_type::text, COUNT(*) FROM "public".event `+
+		`WHERE deleted_at IS NULL AND status <> $1 AND price >= $2 GROUP BY sport_type`, sql)
+	assert.Equal(t, []any{models.EventStatusCancelled, priceMin}, args)
+
+	sql, args, err = facetQuery(squirrel.Expr("price"), filterParams.WithoutPrice()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT price, COUNT(*) FROM "public".event `+
+		`WHERE deleted_at IS NULL AND status <> $1 AND sport_type IN ($2)`, sql)
+	assert.Equal(t, []any{models.EventStatusCancelled, models.SportTypeFootball}, args)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	pgx "github.com/jackc/pgx/v5"
+)
+
+var ErrNotFoundGeocodeCache = errors.New("address is not in geocode cache")
+
+// GetGeocodeCache returns not expired entry, entry without coordinates means address was not found.
+func (p *PostgresStorage) GetGeocodeCache(ctx context.Context, address string) (*models.GeocodeCacheEntry, error) {
+	sqlSelect := `
+	SELECT address, ST_X(coordinates::geometry) as latitude, ST_Y(coordinates::geometry) as longitude,
+		details, provider, expires_at
+	FROM "public".geocode_cache WHERE address = $1 AND expires_at > NOW();`
+
+	var entry models.GeocodeCacheEntry
+
+	err := p.pool.QueryRow(ctx, sqlSelect, address).Scan(
+		&entry.Address, &entry.Latitude, &entry.Longitude, &entry.Details, &entry.Provider, &entry.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFoundGeocodeCache
+		}
+
+		return nil, fmt.Errorf("to scan geocode cache: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (p *PostgresStorage) SetGeocodeCache(ctx context.Context, entry *models.GeocodeCacheEntry) error {
+	sqlUpsert := `
+	INSERT INTO "public".geocode_cache (address, coordinates, details, provider, expires_at)
+		VALUES ($1, ST_Point($2, $3, 4326)::geography, $4, $5, $6)
+	ON CONFLICT (address) DO UPDATE SET coordinates = EXCLUDED.coordinates, details = EXCLUDED.details,
+		provider = EXCLUDED.provider, expires_at = EXCLUDED.expires_at;`
+
+	_, err := p.pool.Exec(ctx, sqlUpsert,
+		entry.Address, entry.Latitude, entry.Longitude, entry.Details, entry.Provider, entry.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+func (p *PostgresStorage) CountEvents(ctx context.Context, filterParams *models.FilterParams) (int, error) {
+	query := squirrel.Select("COUNT(*)").
+		From(`"public".event`).
+		PlaceholderFormat(squirrel.Dollar).
+		Where(squirrel.Eq{"deleted_at": nil})
+
+	query = applyFilterParams(query, filterParams)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("query to sql: %w", err)
+	}
+
+	var count int
+
+	err = p.pool.QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("to count events: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindMapClusters groups events by grid with cellSize in degrees,
+// cluster is placed in the center of its events, not of the cell.
+func (p *PostgresStorage) FindMapClusters(
+	ctx context.Context,
+	filterParams *models.FilterParams,
+	cellSize float64,
+) ([]models.MapCluster, error) {
+	query := squirrel.Select(
+		"COUNT(*)",
+		"AVG(ST_X(coordinates::geometry)) as latitude",
+		"AVG(ST_Y(coordinates::geometry)) as longitude",
+	).
+		Column(squirrel.Expr("FLOOR(ST_X(coordinates::geometry) / ?) as cell_x", cellSize)).
+		Column(squirrel.Expr("FLOOR(ST_Y(coordinates::geometry) / ?) as cell_y", cellSize)).
+		From(`"public".event`).
+		PlaceholderFormat(squirrel.Dollar).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		Where("coordinates IS NOT NULL")
+
+	query = applyFilterParams(query, filterParams).GroupBy("cell_x", "cell_y")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("query to sql: %w", err)
+	}
+
+	rawRows, err := p.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("to select map clusters: %w", err)
+	}
+
+	var (
+		cluster      models.MapCluster
+		cellX, cellY float64
+	)
+
+	result := []models.MapCluster{}
+
+	_, err = pgx.ForEachRow(rawRows, []any{&cluster.Count, &cluster.Latitude, &cluster.Longitude, &cellX, &cellY},
+		func() error {
+			result = append(result, cluster)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("to read map clusters: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+func (p *PostgresStorage) CreateNotifications(ctx context.Context, notifications []models.Notification) error {
+	_, err := p.pool.CopyFrom(ctx,
+		pgx.Identifier{"public", "notification"},
+		[]string{"id", "user_id", "kind", "event_id", "text", "created_at"},
+		pgx.CopyFromSlice(len(notifications), func(i int) ([]any, error) {
+			notification := &notifications[i]
+
+			return []any{
+				notification.ID, notification.UserID, string(notification.Kind), notification.EventID,
+				notification.Text, notification.CreatedAt,
+			}, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("to copy notifications: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) (
+	[]models.Notification, error,
+) {
+	sqlSelect := `
+	SELECT id, user_id, kind, event_id, text, read_at, created_at
+	FROM "public".notification
+	WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+	ORDER BY created_at DESC
+	LIMIT $3;`
+
+	rows, err := p.pool.Query(ctx, sqlSelect, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to select notifications: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Notification, error) {
+		var notification models.Notification
+
+		err := row.Scan(&notification.ID, &notification.UserID, &notification.Kind, &notification.EventID,
+			&notification.Text, &notification.ReadAt, &notification.CreatedAt)
+
+		return notification, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to collect notifications: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+
+	err := p.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM "public".notification WHERE user_id = $1 AND read_at IS NULL;`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkNotificationsRead marks notifications of user as read, all unread ones if ids are empty.
+func (p *PostgresStorage) MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	sqlUpdate := `
+	UPDATE "public".notification SET read_at = NOW()
+	WHERE user_id = $1 AND read_at IS NULL AND (cardinality($2::uuid[]) = 0 OR id = ANY($2));`
+
+	_, err := p.pool.Exec(ctx, sqlUpdate, userID, ids)
+	if err != nil {
+		return fmt.Errorf("to mark notifications read: %w", err)
+	}
+
+	return nil
+}
+
+// GetNotificationPreferences returns saved preferences of users for kind, users without them use defaults.
+func (p *PostgresStorage) GetNotificationPreferences(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+	kind models.NotificationKind,
+) (map[uuid.UUID]models.NotificationPreference, error) {
+	rows, err := p.pool.Query(ctx, `
+	SELECT user_id, in_app, telegram FROM "public".notification_preference
+	WHERE user_id = ANY($1) AND kind = $2;`, userIDs, string(kind))
+	if err != nil {
+		return nil, fmt.Errorf("to select notification preferences: %w", err)
+	}
+
+	result := map[uuid.UUID]models.NotificationPreference{}
+
+	var (
+		userID     uuid.UUID
+		preference = models.NotificationPreference{Kind: kind} //nolint:exhaustruct
+	)
+
+	_, err = pgx.ForEachRow(rows, []any{&userID, &preference.InApp, &preference.Telegram}, func() error {
+		result[userID] = preference
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to scan notification preferences: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetUsersTgIDs returns telegram ids of users, users without telegram are skipped.
+func (p *PostgresStorage) GetUsersTgIDs(ctx context.Context, userIDs []uuid.UUID) ([]int64, error) {
+	rows, err := p.pool.Query(ctx, `
+	SELECT tg_id FROM "public".user WHERE id = ANY($1) AND tg_id IS NOT NULL;`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("to select tg ids: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("to collect tg ids: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) (
+	[]models.NotificationPreference, error,
+) {
+	rows, err := p.pool.Query(ctx, `
+	SELECT kind, in_app, telegram FROM "public".notification_preference WHERE user_id = $1;`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to select notification preferences: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.NotificationPreference, error) {
+		var preference models.NotificationPreference
+
+		err := row.Scan(&preference.Kind, &preference.InApp, &preference.Telegram)
+
+		return preference, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to collect notification preferences: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) SetNotificationPreferences(
+	ctx context.Context,
+	userID uuid.UUID,
+	preferences []models.NotificationPreference,
+) error {
+	batch := &pgx.Batch{}
+
+	for _, preference := range preferences {
+		batch.Queue(`
+		INSERT INTO "public".notification_preference (user_id, kind, in_app, telegram) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, kind) DO UPDATE SET in_app = EXCLUDED.in_app, telegram = EXCLUDED.telegram;`,
+			userID, string(preference.Kind), preference.InApp, preference.Telegram)
+	}
+
+	err := p.pool.SendBatch(ctx, batch).Close()
+	if err != nil {
+		return fmt.Errorf("to upsert notification preferences: %w", err)
+	}
+
+	return nil
+}
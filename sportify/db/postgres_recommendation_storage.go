@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// FindCoAttendees returns users who attended at least minTogether past events together with user.
+func (p *PostgresStorage) FindCoAttendees(ctx context.Context, userID uuid.UUID, minTogether int) ([]uuid.UUID, error) {
+	sqlSelect := `
+	SELECT friend_id
+	FROM "public".event, unnest(subscriber_ids) AS friend_id
+	WHERE $1 = ANY(subscriber_ids) AND friend_id != $1 AND deleted_at IS NULL AND start_time < NOW()
+	GROUP BY friend_id
+	HAVING COUNT(*) >= $2;`
+
+	rows, err := p.pool.Query(ctx, sqlSelect, userID, minTogether)
+	if err != nil {
+		return nil, fmt.Errorf("to select co attendees: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("to collect co attendees: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// ClaimDueReminders finds participants whose reminder time has come and logs reminders as sent.
+// Insert decides who sends reminder, so replicas never send the same one twice.
+func (p *PostgresStorage) ClaimDueReminders(ctx context.Context, now time.Time, limit int) (
+	[]models.EventReminder, error,
+) {
+	sqlInsert := `
+	WITH due AS (
+		SELECT e.id AS event_id, u.id AS user_id, e.start_time
+		FROM "public".event e
+			CROSS JOIN unnest(e.subscriber_ids) AS subscriber(id)
+			JOIN "public".user u ON u.id = subscriber.id
+		WHERE e.deleted_at IS NULL AND e.status = 'scheduled' AND e.start_time > $1
+			AND e.start_time <= $1 + make_interval(hours => COALESCE(u.reminder_hours, e.reminder_hours, $2))
+			AND NOT EXISTS (SELECT 1 FROM "public".event_reminder r
+				WHERE r.event_id = e.id AND r.user_id = u.id AND r.start_time = e.start_time)
+		LIMIT $3
+	)
+	INSERT INTO "public".event_reminder (event_id, user_id, start_time)
+		SELECT event_id, user_id, start_time FROM due
+		ON CONFLICT DO NOTHING
+		RETURNING event_id, user_id, start_time;`
+
+	rows, err := p.pool.Query(ctx, sqlInsert, now, models.DefaultReminderHours, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to claim reminders: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.EventReminder])
+	if err != nil {
+		return nil, fmt.Errorf("to collect reminders: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *PostgresStorage) SetEventReminderHours(ctx context.Context, eventID uuid.UUID, hours *int) error {
+	tag, err := p.pool.Exec(ctx,
+		`UPDATE "public".event SET reminder_hours = $1 WHERE id = $2 AND deleted_at IS NULL;`, hours, eventID)
+	if err != nil {
+		return fmt.Errorf("to update event reminder: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundEvent
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) GetUserReminderHours(ctx context.Context, userID uuid.UUID) (*int, error) {
+	var hours *int
+
+	err := p.pool.QueryRow(ctx, `SELECT reminder_hours FROM "public".user WHERE id = $1;`, userID).Scan(&hours)
+	if err != nil {
+		return nil, fmt.Errorf("to select user reminder: %w", err)
+	}
+
+	return hours, nil
+}
+
+func (p *PostgresStorage) SetUserReminderHours(ctx context.Context, userID uuid.UUID, hours *int) error {
+	_, err := p.pool.Exec(ctx, `UPDATE "public".user SET reminder_hours = $1 WHERE id = $2;`, hours, userID)
+	if err != nil {
+		return fmt.Errorf("to update user reminder: %w", err)
+	}
+
+	return nil
+}
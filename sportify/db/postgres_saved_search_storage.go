@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+var ErrNotFoundSavedSearch = errors.New("Не найден сохраненный поиск")
+
+const (
+	// matchSavedSearchesChunk keeps query of matching far below limit of 65535 parameters.
+	matchSavedSearchesChunk = 200
+	// savedSearchAreaMargin widens circle of search, polygon of buffer lies inside the circle.
+	savedSearchAreaMargin = 1.1
+)
+
+const sqlSelectSavedSearch = `
+	SELECT s.id, s.user_id, s.name, s.filter_params, s.alerts_enabled, s.alert_interval_minutes, s.last_alert_at,
+		s.created_at
+	FROM "public".saved_search s`
+
+func scanSavedSearches(rows pgx.Rows) ([]models.SavedSearch, error) {
+	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.SavedSearch, error) {
+		var savedSearch models.SavedSearch
+
+		err := row.Scan(&savedSearch.ID, &savedSearch.UserID, &savedSearch.Name, &savedSearch.Params,
+			&savedSearch.AlertsEnabled, &savedSearch.AlertIntervalMinutes, &savedSearch.LastAlertAt,
+			&savedSearch.CreatedAt)
+
+		return savedSearch, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to collect saved searches: %w", err)
+	}
+
+	return result, nil
+}
+
+// savedSearchArea returns area where search finds events. It is a bit larger than the search,
+// events from it are checked by exact filters later.
+func savedSearchArea(filterParams *models.FilterParams) squirrel.Sqlizer {
+	if filterParams.Latitude != nil && filterParams.Longitude != nil {
+		radius := models.DefaultSearchRadiusM
+		if filterParams.RadiusM != nil {
+			radius = *filterParams.RadiusM
+		}
+
+		return squirrel.Expr("ST_Buffer(ST_Point(?, ?, 4326)::geography, ?)",
+			*filterParams.Latitude, *filterParams.Longitude, radius*savedSearchAreaMargin)
+	}
+
+	if filterParams.BBox != nil {
+		// coordinates are stored as ST_Point(latitude, longitude), so envelope is built in the same order
+		return squirrel.Expr("ST_MakeEnvelope(?, ?, ?, ?, 4326)::geography",
+			filterParams.BBox.MinLatitude, filterParams.BBox.MinLongitude,
+			filterParams.BBox.MaxLatitude, filterParams.BBox.MaxLongitude)
+	}
+
+	return squirrel.Expr("NULL")
+}
+
+func sqlInsertSavedSearch(savedSearch *models.SavedSearch) (string, []any, error) {
+	filterParams, err := savedSearch.FilterParams()
+	if err != nil {
+		return "", nil, fmt.Errorf("to parse params of saved search: %w", err)
+	}
+
+	sportTypes := make([]string, 0, len(filterParams.SportTypes))
+	for _, sportType := range filterParams.SportTypes {
+		sportTypes = append(sportTypes, string(sportType))
+	}
+
+	return squirrel.Insert(`"public".saved_search`).
+		Columns("id", "user_id", "name", "filter_params", "alerts_enabled", "alert_interval_minutes",
+			"sport_types", "area").
+		Values(savedSearch.ID, savedSearch.UserID, savedSearch.Name, savedSearch.Params, savedSearch.AlertsEnabled,
+			savedSearch.AlertIntervalMinutes, sportTypes, savedSearchArea(filterParams)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+}
+
+func (p *PostgresStorage) CreateSavedSearch(ctx context.Context, savedSearch *models.SavedSearch) error {
+	sqlInsert, args, err := sqlInsertSavedSearch(savedSearch)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.pool.Exec(ctx, sqlInsert, args...)
+	if err != nil {
+		return fmt.Errorf("to insert saved search: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error) {
+	rows, err := p.pool.Query(ctx, sqlSelectSavedSearch+` WHERE s.user_id = $1 ORDER BY s.created_at;`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("to select saved searches: %w", err)
+	}
+
+	return scanSavedSearches(rows)
+}
+
+func (p *PostgresStorage) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM "public".saved_search WHERE id = $1 AND user_id = $2;`, id, userID)
+	if err != nil {
+		return fmt.Errorf("to delete saved search: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundSavedSearch
+	}
+
+	return nil
+}
+
+// GetAlertingSavedSearches returns searches with enabled alerts of all users except creator of event,
+// which can find event by sport type and place. Other filters are checked by MatchSavedSearches.
+func (p *PostgresStorage) GetAlertingSavedSearches(ctx context.Context, eventID, exceptUserID uuid.UUID) (
+	[]models.SavedSearch, error,
+) {
+	sqlSelect := sqlSelectSavedSearch + `
+		JOIN "public".event e ON e.id = $1
+	WHERE s.alerts_enabled AND s.user_id != $2
+		AND (cardinality(s.sport_types) = 0 OR e.sport_type::text = ANY(s.sport_types))
+		AND (s.area IS NULL OR s.area && e.coordinates);`
+
+	rows, err := p.pool.Query(ctx, sqlSelect, eventID, exceptUserID)
+	if err != nil {
+		return nil, fmt.Errorf("to select alerting saved searches: %w", err)
+	}
+
+	return scanSavedSearches(rows)
+}
+
+// sqlMatchSavedSearches checks one event against filters of all searches in one query,
+// each search is a part of UNION ALL which returns id of search if event matches.
+func sqlMatchSavedSearches(eventID uuid.UUID, savedSearches []models.SavedSearchFilter) (string, []any, error) {
+	parts := make([]string, 0, len(savedSearches))
+
+	var args []any
+
+	for _, savedSearch := range savedSearches {
+		query := squirrel.Select().
+			Column("?::uuid", savedSearch.ID).
+			From(`"public".event`).
+			Where(squirrel.Eq{"id": eventID}).
+			Where(squirrel.Eq{"deleted_at": nil})
+
+		query = applyFilterParams(query, savedSearch.FilterParams)
+
+		sql, partArgs, err := query.ToSql()
+		if err != nil {
+			return "", nil, fmt.Errorf("query of saved search %s to sql: %w", savedSearch.ID, err)
+		}
+
+		parts = append(parts, sql)
+		args = append(args, partArgs...)
+	}
+
+	sql, err := squirrel.Dollar.ReplacePlaceholders(strings.Join(parts, "\nUNION ALL\n"))
+	if err != nil {
+		return "", nil, fmt.Errorf("to replace placeholders: %w", err)
+	}
+
+	return sql, args, nil
+}
+
+// MatchSavedSearches returns ids of searches which find event, searches are matched by chunks.
+func (p *PostgresStorage) MatchSavedSearches(
+	ctx context.Context,
+	eventID uuid.UUID,
+	savedSearches []models.SavedSearchFilter,
+) ([]uuid.UUID, error) {
+	if len(savedSearches) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	result := []uuid.UUID{}
+
+	for chunk := range slices.Chunk(savedSearches, matchSavedSearchesChunk) {
+		sql, args, err := sqlMatchSavedSearches(eventID, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := p.pool.Query(ctx, sql, args...)
+		if err != nil {
+			return nil, fmt.Errorf("to match saved searches: %w", err)
+		}
+
+		matched, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return nil, fmt.Errorf("to collect matched saved searches: %w", err)
+		}
+
+		result = append(result, matched...)
+	}
+
+	return result, nil
+}
+
+// AddSavedSearchAlert puts event to feed of search. It returns true if user should be notified now:
+// event is new for the search and last notification was earlier than interval of search.
+func (p *PostgresStorage) AddSavedSearchAlert(ctx context.Context, savedSearchID, eventID uuid.UUID) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, `
+	INSERT INTO "public".saved_search_alert (saved_search_id, event_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;`, savedSearchID, eventID)
+	if err != nil {
+		return false, fmt.Errorf("to insert saved search alert: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	tag, err = tx.Exec(ctx, `
+	UPDATE "public".saved_search SET last_alert_at = NOW()
+		WHERE id = $1 AND (last_alert_at IS NULL
+			OR last_alert_at <= NOW() - make_interval(mins => alert_interval_minutes));`, savedSearchID)
+	if err != nil {
+		return false, fmt.Errorf("to update last alert: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, fmt.Errorf("to commit: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (p *PostgresStorage) GetSavedSearchAlerts(ctx context.Context, userID uuid.UUID, limit int) (
+	[]models.SavedSearchAlert, error,
+) {
+	sqlSelect := `
+	SELECT a.saved_search_id, s.name, a.event_id, a.created_at
+	FROM "public".saved_search_alert a
+		JOIN "public".saved_search s ON s.id = a.saved_search_id
+		JOIN "public".event e ON e.id = a.event_id
+	WHERE s.user_id = $1 AND e.deleted_at IS NULL
+	ORDER BY a.created_at DESC
+	LIMIT $2;`
+
+	rows, err := p.pool.Query(ctx, sqlSelect, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to select saved search alerts: %w", err)
+	}
+
+	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.SavedSearchAlert, error) {
+		var alert models.SavedSearchAlert
+
+		err := row.Scan(&alert.SavedSearchID, &alert.SearchName, &alert.EventID, &alert.CreatedAt)
+
+		return alert, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to collect saved search alerts: %w", err)
+	}
+
+	return result, nil
+}
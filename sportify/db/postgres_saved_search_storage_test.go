@@ -0,0 +1,62 @@
+package db
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLMatchSavedSearches(t *testing.T) {
+	t.Parallel()
+
+	eventID := uuid.MustParse("0f8c4a5e-3f0b-4a5e-9a43-6d1e0f1b2c3d")
+	firstID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	secondID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	sql, args, err := sqlMatchSavedSearches(eventID, []models.SavedSearchFilter{
+		{ID: firstID, FilterParams: &models.FilterParams{WithCancelled: true}},                  //nolint:exhaustruct
+		{ID: secondID, FilterParams: &models.FilterParams{WithCancelled: true, OnlyFree: true}}, //nolint:exhaustruct
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		`SELECT $1::uuid FROM "public".event WHERE id = $2 AND deleted_at IS NULL`+"\n"+
+			"UNION ALL\n"+
+			`SELECT $3::uuid FROM "public".event WHERE id = $4 AND deleted_at IS NULL AND price = $5`,
+		sql)
+	assert.Equal(t, []any{firstID, eventID.String(), secondID, eventID.String(), 0}, args)
+}
+
+func TestSQLInsertSavedSearch(t *testing.T) {
+	t.Parallel()
+
+	savedSearch := &models.SavedSearch{ //nolint:exhaustruct
+		ID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		UserID: uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		Name:   "Футбол рядом",
+		Params: url.Values{"sport_type": {"football"}, "lat": {"55.7"}, "lon": {"37.6"}, "radius_m": {"1000"}},
+	}
+
+	sql, args, err := sqlInsertSavedSearch(savedSearch)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "VALUES ($1,$2,$3,$4,$5,$6,$7,ST_Buffer(ST_Point($8, $9, 4326)::geography, $10))")
+	assert.Equal(t, []string{"football"}, args[6])
+	assert.Equal(t, []any{55.7, 37.6, 1100.0}, args[7:])
+
+	// bbox search gets envelope, search without place has no area
+	savedSearch.Params = url.Values{"bbox": {"37.3,55.5,37.9,56"}}
+	sql, args, err = sqlInsertSavedSearch(savedSearch)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "ST_MakeEnvelope($8, $9, $10, $11, 4326)::geography")
+	assert.Equal(t, []any{55.5, 37.3, 56.0, 37.9}, args[7:])
+
+	savedSearch.Params = url.Values{}
+	sql, args, err = sqlInsertSavedSearch(savedSearch)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "$7,NULL)")
+	assert.Equal(t, []string{}, args[6])
+}
@@ -27,10 +27,12 @@ func (p *PostgresStorage) CreateEvent(ctx context.Context, event *models.FullEve
 	INSERT INTO "public".event (
     id, creator_id, subscriber_ids, sport_type, address, date_start, start_time, end_time,
     price, game_level, description, raw_message, capacity, busy, creation_type,
-    url_message, url_author, url_preview, url_photos, tg_chat_id, tg_message_id
+    url_message, url_author, url_preview, url_photos, tg_chat_id, tg_message_id, club_id, venue_id,
+    coordinates, expiration_time_coordinates, address_details
 ) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, 
           $9, $10, $11, $12, $13, $14, $15,
-          $16, $17, $18, $19, $20, $21);`
+          $16, $17, $18, $19, $20, $21, $22, $23,
+          ST_Point($24, $25, 4326)::geography, NOW() + interval '29' day, $26);`
 
 	preparedGameLevel := pq.Array(event.GameLevels)
 
@@ -38,7 +40,8 @@ func (p *PostgresStorage) CreateEvent(ctx context.Context, event *models.FullEve
 		event.ID, event.CreatorID, event.Subscribers, event.SportType, event.Address,
 		event.DateAndTime.Date, event.DateAndTime.StartTime, event.DateAndTime.EndTime, event.Price, preparedGameLevel,
 		event.Description, event.RawMessage, event.Capacity, event.Busy, event.CreationType,
-		event.URLMessage, event.URLAuthor, event.URLPreview, event.URLPhotos, event.TgChatID, event.TgMessageID)
+		event.URLMessage, event.URLAuthor, event.URLPreview, event.URLPhotos, event.TgChatID, event.TgMessageID,
+		event.ClubID, event.VenueID, event.Latitude, event.Longitude, event.AddressDetails)
 	if err != nil {
 		return err
 	}
@@ -52,8 +55,9 @@ func (p *PostgresStorage) EditEvent(ctx context.Context, event *models.FullEvent
 		date_start = $4, start_time = $5, end_time = $6, price = $7, game_level = $8,
 		description = $9, capacity = $10, creation_type = $11, url_message = $12, 
 		url_author = $13, url_preview = $14, url_photos = $15,
-		coordinates = ST_Point($16, $17, 4326)::geography
-		WHERE id = $18 AND deleted_at IS NULL;`
+		coordinates = ST_Point($16, $17, 4326)::geography, venue_id = $18,
+		expiration_time_coordinates = NOW() + interval '29' day, address_details = $19
+		WHERE id = $20 AND deleted_at IS NULL;`
 
 	preparedGameLevels := pq.Array(event.GameLevels)
 
@@ -61,7 +65,8 @@ func (p *PostgresStorage) EditEvent(ctx context.Context, event *models.FullEvent
 		event.CreatorID, event.SportType, event.Address,
 		event.DateAndTime.Date, event.DateAndTime.StartTime, event.DateAndTime.EndTime, event.Price, preparedGameLevels,
 		event.Description, event.Capacity, event.CreationType, event.URLMessage,
-		event.URLAuthor, event.URLPreview, event.URLPhotos, event.Latitude, event.Longitude, event.ID)
+		event.URLAuthor, event.URLPreview, event.URLPhotos, event.Latitude, event.Longitude, event.VenueID,
+		event.AddressDetails, event.ID)
 	if err != nil {
 		return err
 	}
@@ -80,7 +85,28 @@ func (p *PostgresStorage) DeleteEvent(ctx context.Context, userID, eventID uuid.
 	return nil
 }
 
-var ErrNotFoundEvent = errors.New("Не найдено событие")
+// HardDeleteEvent removes event row, rows of event in other tables are removed by cascade.
+func (p *PostgresStorage) HardDeleteEvent(ctx context.Context, eventID uuid.UUID) error {
+	sqlDelete := `DELETE FROM "public".event WHERE id = $1`
+
+	tag, err := p.pool.Exec(ctx, sqlDelete, eventID)
+	if err != nil {
+		return fmt.Errorf("to delete event: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundEvent
+	}
+
+	return nil
+}
+
+var (
+	ErrNotFoundEvent  = errors.New("Не найдено событие")
+	ErrEventNotActive = errors.New("Событие отменено или уже прошло")
+	// ErrEventStatusChanged is returned when status was changed by another request or event was deleted.
+	ErrEventStatusChanged = errors.New("Статус события уже изменился")
+)
 
 func (p *PostgresStorage) GetCreatorID(ctx context.Context, eventID uuid.UUID) (uuid.UUID, error) {
 	sqlSelectEvent := `
@@ -109,7 +135,8 @@ func (p *PostgresStorage) GetEventByTgChatAndMessageIDs(ctx context.Context, tgC
        url_author, url_message, 
        url_preview, url_photos,
        ST_X(coordinates::geometry) as latitude, ST_Y(coordinates::geometry) as longitude,
-	   tg_chat_id, tg_message_id, expiration_time_coordinates
+	   tg_chat_id, tg_message_id, expiration_time_coordinates, club_id, venue_id, address_details,
+	   status, cancel_reason, updated_at
 	FROM "public".event WHERE tg_chat_id = $1 AND $2 = tg_message_id AND deleted_at IS NULL;`
 
 	rawRow := p.pool.QueryRow(ctx, sqlSelectEvent, tgChatID, tgMessageID)
@@ -125,7 +152,8 @@ func (p *PostgresStorage) GetEventByTgChatAndMessageIDs(ctx context.Context, tgC
 		&event.DateAndTime.Date, &event.DateAndTime.StartTime, &event.DateAndTime.EndTime, &event.Price, &rawGameLevels,
 		&event.Description, &event.RawMessage, &event.Capacity, &event.Busy, &event.CreationType,
 		&event.URLAuthor, &event.URLMessage, &event.URLPreview, &rawURLPhotos, &event.Latitude, &event.Longitude,
-		&event.TgChatID, &event.TgMessageID, &event.ExpirationTimeCoordinates)
+		&event.TgChatID, &event.TgMessageID, &event.ExpirationTimeCoordinates, &event.ClubID, &event.VenueID,
+		&event.AddressDetails, &event.Status, &event.CancelReason, &event.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFoundEvent
@@ -149,7 +177,8 @@ func (p *PostgresStorage) GetEvent(ctx context.Context, eventID uuid.UUID) (*mod
        url_author, url_message, 
        url_preview, url_photos,
        ST_X(coordinates::geometry) as latitude, ST_Y(coordinates::geometry) as longitude,
-	   tg_chat_id, tg_message_id, expiration_time_coordinates
+	   tg_chat_id, tg_message_id, expiration_time_coordinates, club_id, venue_id, address_details,
+	   status, cancel_reason, updated_at
 	FROM "public".event WHERE id = $1 AND deleted_at IS NULL;`
 
 	rawRow := p.pool.QueryRow(ctx, sqlSelectEvent, eventID)
@@ -165,7 +194,8 @@ func (p *PostgresStorage) GetEvent(ctx context.Context, eventID uuid.UUID) (*mod
 		&event.DateAndTime.Date, &event.DateAndTime.StartTime, &event.DateAndTime.EndTime, &event.Price, &rawGameLevels,
 		&event.Description, &event.RawMessage, &event.Capacity, &event.Busy, &event.CreationType,
 		&event.URLAuthor, &event.URLMessage, &event.URLPreview, &rawURLPhotos, &event.Latitude, &event.Longitude,
-		&event.TgChatID, &event.TgMessageID, &event.ExpirationTimeCoordinates)
+		&event.TgChatID, &event.TgMessageID, &event.ExpirationTimeCoordinates, &event.ClubID, &event.VenueID,
+		&event.AddressDetails, &event.Status, &event.CancelReason, &event.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFoundEvent
@@ -207,7 +237,7 @@ func (p *PostgresStorage) SubscribeEvent(
 ) (*models.ResponseSubscribeEvent, error) {
 	// TODO add support of creator_id event notify
 	sqlSelectEvent := `
-	SELECT subscriber_ids, busy, capacity FROM "public".event 
+	SELECT subscriber_ids, busy, capacity, status FROM "public".event 
 	                                      WHERE id = $1 AND deleted_at IS NULL;`
 
 	rawRow := p.pool.QueryRow(ctx, sqlSelectEvent, eventID)
@@ -215,9 +245,10 @@ func (p *PostgresStorage) SubscribeEvent(
 	var (
 		rawSubscriberIDs       pgtype.Array[uuid.UUID]
 		responseSubscribeEvent models.ResponseSubscribeEvent
+		status                 models.EventStatus
 	)
 
-	err := rawRow.Scan(&rawSubscriberIDs, &responseSubscribeEvent.Busy, &responseSubscribeEvent.Capacity)
+	err := rawRow.Scan(&rawSubscriberIDs, &responseSubscribeEvent.Busy, &responseSubscribeEvent.Capacity, &status)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFoundEvent
@@ -226,6 +257,10 @@ func (p *PostgresStorage) SubscribeEvent(
 		return nil, fmt.Errorf("to scan event for subcribe: %w", err)
 	}
 
+	if subscribe && !status.Active() {
+		return nil, ErrEventNotActive
+	}
+
 	responseSubscribeEvent.Subscribers = rawSubscriberIDs.Elements
 
 	if subscribe {
@@ -274,12 +309,16 @@ func getSQLEvents(rawRows pgx.Rows) ([]models.ShortEvent, error) {
 			&curEvent.DateAndTime.StartTime, &curEvent.DateAndTime.EndTime, &curEvent.Price, &rawGameLevels,
 			&curEvent.Capacity, &curEvent.Busy, &curEvent.Subscribers,
 			&curEvent.URLPreview, &photoURLs, &curEvent.Latitude, &curEvent.Longitude, &curEvent.ExpirationTimeCoordinates,
+			&curEvent.ClubID, &curEvent.VenueID, &curEvent.AddressDetails, &curEvent.Status, &curEvent.CancelReason,
+			&curEvent.UpdatedAt, &curEvent.Snippet, &curEvent.SortValue,
 		},
 		func() error {
 			result = append(
 				result, models.ShortEvent{
 					ID:        curEvent.ID,
 					CreatorID: curEvent.CreatorID,
+					ClubID:    curEvent.ClubID,
+					VenueID:   curEvent.VenueID,
 					SportType: curEvent.SportType,
 					Address:   curEvent.Address,
 					DateAndTime: models.DateAndTime{
@@ -292,12 +331,18 @@ func getSQLEvents(rawRows pgx.Rows) ([]models.ShortEvent, error) {
 					GameLevels:                models.GameLevelFromRawNullable(rawGameLevels.Elements),
 					Capacity:                  curEvent.Capacity,
 					Busy:                      curEvent.Busy,
+					Status:                    curEvent.Status,
+					CancelReason:              curEvent.CancelReason,
 					Subscribers:               curEvent.Subscribers,
 					URLPreview:                curEvent.URLPreview,
 					URLPhotos:                 photoURLs.Elements,
 					Latitude:                  curEvent.Latitude,
 					Longitude:                 curEvent.Longitude,
+					AddressDetails:            curEvent.AddressDetails,
+					Snippet:                   snippetHTML(curEvent.Snippet),
 					ExpirationTimeCoordinates: curEvent.ExpirationTimeCoordinates,
+					SortValue:                 curEvent.SortValue,
+					UpdatedAt:                 curEvent.UpdatedAt,
 				})
 
 			return nil
@@ -313,28 +358,103 @@ func getSQLEvents(rawRows pgx.Rows) ([]models.ShortEvent, error) {
 	return result, nil
 }
 
-//nolint:lll
-func (p *PostgresStorage) FindEvents(ctx context.Context, filterParams *models.FilterParams) ([]models.ShortEvent, error) {
-	logger, err := mylogger.Get()
-	if err != nil {
-		return nil, fmt.Errorf("get logger: %w", err)
+func snippetHTML(rawSnippet *string) *string {
+	if rawSnippet == nil {
+		return nil
 	}
 
-	logger.WithCtx(ctx).Infow("Getting events", zap.Any("filter_params", filterParams))
+	result := models.SnippetHTML(*rawSnippet)
 
-	query := squirrel.Select(`id, creator_id, sport_type, address, date_start, start_time,
-		end_time, price, game_level, capacity, busy,
-		subscriber_ids, url_preview, url_photos,
-		ST_X(coordinates::geometry) as latitude, ST_Y(coordinates::geometry) as longitude, expiration_time_coordinates`).
-		From(`"public".event`).
-		PlaceholderFormat(squirrel.Dollar).
-		Where(squirrel.Eq{"deleted_at": nil})
-	// Where(squirrel.Gt{"start_time": time.Now().Add(-24 * time.Hour)}) // TODO: add later
+	return &result
+}
+
+// sqlSnippetOptions mark found words with control characters, snippet is made html after escaping of user text.
+const sqlSnippetOptions = "StartSel=" + models.SnippetStartSel + ", StopSel=" + models.SnippetStopSel +
+	", MaxFragments=2, MaxWords=20, MinWords=5"
+
+// sqlSnippet highlights words found by full text search, there is no snippet without search.
+func sqlSnippet(searchQuery string) squirrel.Sqlizer {
+	if searchQuery == "" {
+		return squirrel.Expr("NULL::text as snippet")
+	}
+
+	return squirrel.Expr(`ts_headline('russian', CONCAT_WS(' ', description, address, raw_message),
+		websearch_to_tsquery('russian', ?), ?) as snippet`, searchQuery, sqlSnippetOptions)
+}
+
+func applyScheduleFilters(query squirrel.SelectBuilder, filterParams *models.FilterParams) squirrel.SelectBuilder {
+	if filterParams.DateFrom != nil {
+		query = query.Where(squirrel.GtOrEq{"date_start": *filterParams.DateFrom})
+	}
+
+	if filterParams.DateTo != nil {
+		query = query.Where(squirrel.LtOrEq{"date_start": *filterParams.DateTo})
+	}
+
+	// time of events is stored as moscow wall clock in UTC, so it is read back in UTC
+	if len(filterParams.Weekdays) > 0 {
+		query = query.Where("EXTRACT(ISODOW FROM date_start AT TIME ZONE 'UTC') = ANY(?)",
+			pq.Array(filterParams.Weekdays))
+	}
+
+	if filterParams.TimeFrom != "" {
+		query = query.Where("(start_time AT TIME ZONE 'UTC')::time >= ?::time", filterParams.TimeFrom)
+	}
+
+	if filterParams.TimeTo != "" {
+		query = query.Where("(start_time AT TIME ZONE 'UTC')::time <= ?::time", filterParams.TimeTo)
+	}
+
+	return query
+}
+
+func applyEventKindFilters(query squirrel.SelectBuilder, filterParams *models.FilterParams) squirrel.SelectBuilder {
+	if filterParams.OnlyFree {
+		query = query.Where(squirrel.Eq{"price": 0})
+	}
+
+	if filterParams.HasFreePlaces {
+		query = query.Where("(capacity IS NULL OR capacity > busy)")
+	}
+
+	if filterParams.CreationType != nil {
+		query = query.Where(squirrel.Eq{"creation_type": *filterParams.CreationType})
+	}
+
+	if len(filterParams.LevelRange) > 0 {
+		query = query.Where("(game_level && ? OR COALESCE(cardinality(game_level), 0) = 0)",
+			pq.Array(filterParams.LevelRange))
+	}
+
+	if filterParams.ExcludeSubscriberID != nil {
+		query = query.Where("NOT COALESCE(? = ANY(subscriber_ids), false)", *filterParams.ExcludeSubscriberID)
+	}
+
+	return query
+}
+
+//nolint:cyclop
+func applyFilterParams(query squirrel.SelectBuilder, filterParams *models.FilterParams) squirrel.SelectBuilder {
+	if filterParams.EventID != nil {
+		query = query.Where(squirrel.Eq{"id": *filterParams.EventID})
+	}
 
 	if filterParams.CreatorID != nil {
 		query = query.Where(squirrel.Eq{"creator_id": filterParams.CreatorID})
 	}
 
+	if !filterParams.WithCancelled {
+		query = query.Where(squirrel.NotEq{"status": models.EventStatusCancelled})
+	}
+
+	if filterParams.ClubID != nil {
+		query = query.Where(squirrel.Eq{"club_id": filterParams.ClubID})
+	}
+
+	if filterParams.VenueID != nil {
+		query = query.Where(squirrel.Eq{"venue_id": filterParams.VenueID})
+	}
+
 	if len(filterParams.SportTypes) > 0 {
 		query = query.Where(squirrel.Eq{"sport_type": filterParams.SportTypes})
 	}
@@ -367,15 +487,117 @@ func (p *PostgresStorage) FindEvents(ctx context.Context, filterParams *models.F
 		query = query.Where(squirrel.Expr("capacity - busy >= ?", *filterParams.FreePlaces))
 	}
 
-	if filterParams.Address != "" {
-		if filterParams.AddressLatitude != nil && filterParams.AddressLongitude != nil {
-			query = query.Where(fmt.Sprintf("ST_DWithin(ST_POINT(%s,%s, 4326)::geography, coordinates, 5000.0)",
-				*filterParams.AddressLatitude, *filterParams.AddressLongitude))
+	query = applyEventKindFilters(query, filterParams)
+	query = applyScheduleFilters(query, filterParams)
+
+	if filterParams.Query != "" {
+		query = query.Where("search_vector @@ websearch_to_tsquery('russian', ?)", filterParams.Query)
+	}
+
+	if filterParams.Latitude != nil && filterParams.Longitude != nil {
+		radius := models.DefaultSearchRadiusM
+		if filterParams.RadiusM != nil {
+			radius = *filterParams.RadiusM
 		}
-		// TODO add find address or by reqexp or another text search
+
+		query = query.Where("ST_DWithin(ST_Point(?, ?, 4326)::geography, coordinates, ?)",
+			*filterParams.Latitude, *filterParams.Longitude, radius)
+	}
+
+	if filterParams.BBox != nil {
+		// coordinates are stored as ST_Point(latitude, longitude), so envelope is built in the same order
+		query = query.Where("coordinates && ST_MakeEnvelope(?, ?, ?, ?, 4326)::geography",
+			filterParams.BBox.MinLatitude, filterParams.BBox.MinLongitude,
+			filterParams.BBox.MaxLatitude, filterParams.BBox.MaxLongitude)
+	}
+
+	return query
+}
+
+// sqlSortKey returns expression of whitelisted order key and postgres type to compare cursor value with.
+func sqlSortKey(filterParams *models.FilterParams) (squirrel.Sqlizer, string) {
+	switch filterParams.OrderBy {
+	case models.OrderByPrice:
+		return squirrel.Expr("price"), "bigint"
+	case models.OrderByFreePlaces:
+		// events without capacity have unlimited places
+		return squirrel.Expr("COALESCE(capacity, 1000000) - busy"), "integer"
+	case models.OrderByCreatedAt:
+		return squirrel.Expr("created_at"), "timestamptz"
+	case models.OrderByDistance:
+		if filterParams.Latitude == nil || filterParams.Longitude == nil {
+			// there is no point to measure distance from, app checks it, so it is only a safety net
+			return squirrel.Expr("start_time"), "timestamptz"
+		}
+
+		// events without coordinates are the farthest
+		return squirrel.Expr("COALESCE(ST_Distance(coordinates, ST_Point(?, ?, 4326)::geography), 1e12)",
+			*filterParams.Latitude, *filterParams.Longitude), "float8"
+	case models.OrderByRelevance:
+		return squirrel.Expr("ts_rank(search_vector, websearch_to_tsquery('russian', ?))", filterParams.Query), "real"
+	default:
+		return squirrel.Expr("start_time"), "timestamptz"
+	}
+}
+
+// applyPage orders events by sort key and id, so order is stable and page
+// continues strictly after event of cursor.
+func applyPage(query squirrel.SelectBuilder, filterParams *models.FilterParams) (squirrel.SelectBuilder, error) {
+	sortKey, sortType := sqlSortKey(filterParams)
+
+	sortSQL, sortArgs, err := sortKey.ToSql()
+	if err != nil {
+		return query, fmt.Errorf("sort key to sql: %w", err)
+	}
+
+	direction, compare := "ASC", ">"
+	if filterParams.SortOrder == "desc" {
+		direction, compare = "DESC", "<"
+	}
+
+	query = query.Column(squirrel.Expr("("+sortSQL+")::text as sort_value", sortArgs...))
+
+	if filterParams.Cursor != nil {
+		args := append(append([]any{}, sortArgs...), filterParams.Cursor.Value, filterParams.Cursor.ID)
+		query = query.Where(
+			fmt.Sprintf("((%s), id) %s (CAST(? AS %s), ?)", sortSQL, compare, sortType), args...)
+	}
+
+	query = query.OrderByClause(fmt.Sprintf("(%s) %s, id %s", sortSQL, direction, direction), sortArgs...)
+
+	// one more event shows that there is next page
+	if filterParams.Limit > 0 {
+		query = query.Limit(uint64(filterParams.Limit) + 1)
+	}
+
+	return query, nil
+}
+
+func (p *PostgresStorage) FindEvents(ctx context.Context, filterParams *models.FilterParams) ([]models.ShortEvent, error) {
+	logger, err := mylogger.Get()
+	if err != nil {
+		return nil, fmt.Errorf("get logger: %w", err)
 	}
 
-	query = query.OrderBy(filterParams.OrderBy + " " + filterParams.SortOrder)
+	logger.WithCtx(ctx).Infow("Getting events", zap.Any("filter_params", filterParams))
+
+	query := squirrel.Select(`id, creator_id, sport_type, address, date_start, start_time,
+		end_time, price, game_level, capacity, busy,
+		subscriber_ids, url_preview, url_photos,
+		ST_X(coordinates::geometry) as latitude, ST_Y(coordinates::geometry) as longitude, expiration_time_coordinates,
+		club_id, venue_id, address_details, status, cancel_reason, updated_at`).
+		Column(sqlSnippet(filterParams.Query)).
+		From(`"public".event`).
+		PlaceholderFormat(squirrel.Dollar).
+		Where(squirrel.Eq{"deleted_at": nil})
+	// Where(squirrel.Gt{"start_time": time.Now().Add(-24 * time.Hour)}) // TODO: add later
+
+	query = applyFilterParams(query, filterParams)
+
+	query, err = applyPage(query, filterParams)
+	if err != nil {
+		return nil, err
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -392,6 +614,28 @@ func (p *PostgresStorage) FindEvents(ctx context.Context, filterParams *models.F
 	return getSQLEvents(rawRows)
 }
 
+// SetEventStatus changes status only if it is still oldStatus, so concurrent changes can't both pass check.
+func (p *PostgresStorage) SetEventStatus(
+	ctx context.Context,
+	eventID uuid.UUID,
+	oldStatus, status models.EventStatus,
+	cancelReason *string,
+) error {
+	sqlUpdate := `UPDATE "public".event SET status = $1, cancel_reason = $2
+		WHERE id = $3 AND status = $4 AND deleted_at IS NULL;`
+
+	tag, err := p.pool.Exec(ctx, sqlUpdate, status, cancelReason, eventID, oldStatus)
+	if err != nil {
+		return fmt.Errorf("to update event status: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrEventStatusChanged
+	}
+
+	return nil
+}
+
 func (p *PostgresStorage) AddUserPaid(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	sqlUpdate := `
 	UPDATE public.event SET user_paid_ids = ARRAY_APPEND(user_paid_ids, $1) WHERE id = $2;`
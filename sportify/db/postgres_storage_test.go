@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func pageSQL(t *testing.T, filterParams *models.FilterParams) (string, []any) {
+	t.Helper()
+
+	query := squirrel.Select("id").From(`"public".event`).PlaceholderFormat(squirrel.Dollar)
+
+	query, err := applyPage(query, filterParams)
+	assert.NoError(t, err)
+
+	sql, args, err := query.ToSql()
+	assert.NoError(t, err)
+
+	return sql, args
+}
+
+func TestApplyPageWithoutLimit(t *testing.T) {
+	t.Parallel()
+
+	sql, args := pageSQL(t, &models.FilterParams{OrderBy: models.OrderByStartTime, SortOrder: "asc"}) //nolint:exhaustruct
+
+	assert.Equal(t, `SELECT id, (start_time)::text as sort_value FROM "public".event `+
+		`ORDER BY (start_time) ASC, id ASC`, sql)
+	assert.Empty(t, args)
+}
+
+func TestApplyPageCursor(t *testing.T) {
+	t.Parallel()
+
+	cursorID := uuid.MustParse("0f8c4a5e-3f0b-4a5e-9a43-6d1e0f1b2c3d")
+
+	sql, args := pageSQL(t, &models.FilterParams{ //nolint:exhaustruct
+		OrderBy:   models.OrderByPrice,
+		SortOrder: "desc",
+		Limit:     20,
+		Cursor:    &models.EventsCursor{OrderBy: models.OrderByPrice, SortOrder: "desc", Value: "500", ID: cursorID},
+	})
+
+	assert.Equal(t, `SELECT id, (price)::text as sort_value FROM "public".event `+
+		`WHERE ((price), id) < (CAST($1 AS bigint), $2) ORDER BY (price) DESC, id DESC LIMIT 21`, sql)
+	assert.Equal(t, []any{"500", cursorID}, args)
+}
+
+func TestApplyPageRelevanceArgs(t *testing.T) {
+	t.Parallel()
+
+	sql, args := pageSQL(t, &models.FilterParams{ //nolint:exhaustruct
+		Query:     "футбол",
+		OrderBy:   models.OrderByRelevance,
+		SortOrder: "desc",
+		Limit:     10,
+		Cursor:    &models.EventsCursor{OrderBy: models.OrderByRelevance, SortOrder: "desc", Value: "0.5", ID: uuid.Nil},
+	})
+
+	// sort key with argument is used in column, cursor and order, so its argument is repeated
+	assert.Contains(t, sql, "WHERE ((ts_rank(search_vector, websearch_to_tsquery('russian', $2))), id) < (CAST($3 AS real), $4)")
+	assert.Contains(t, sql, "LIMIT 11")
+	assert.Equal(t, []any{"футбол", "футбол", "0.5", uuid.Nil, "футбол"}, args)
+}
+
+func TestApplyPageDistanceWithoutPoint(t *testing.T) {
+	t.Parallel()
+
+	sql, args := pageSQL(t, &models.FilterParams{OrderBy: models.OrderByDistance, SortOrder: "asc"}) //nolint:exhaustruct
+
+	assert.Equal(t, `SELECT id, (start_time)::text as sort_value FROM "public".event `+
+		`ORDER BY (start_time) ASC, id ASC`, sql)
+	assert.Empty(t, args)
+}
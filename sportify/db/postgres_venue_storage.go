@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheVovchenskiy/sportify-backend/models"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrNotFoundVenue = errors.New("Не найдена площадка")
+
+func rawVenueSportTypes(venue *models.Venue) []string {
+	return common.Map(func(item models.SportType) string {
+		return string(item)
+	}, venue.SportTypes)
+}
+
+func rawVenueAmenities(venue *models.Venue) []string {
+	return common.Map(func(item models.VenueAmenity) string {
+		return string(item)
+	}, venue.Amenities)
+}
+
+func (p *PostgresStorage) CreateVenue(ctx context.Context, venue *models.Venue) error {
+	sqlInsert := `
+	INSERT INTO "public".venue (id, creator_id, name, address, normalized_address, coordinates,
+		surface, indoor, sport_types, amenities, url_photos)
+		VALUES ($1, $2, $3, $4, $5, ST_Point($6, $7, 4326)::geography, $8, $9, $10, $11, $12);`
+
+	_, err := p.pool.Exec(ctx, sqlInsert,
+		venue.ID, venue.CreatorID, venue.Name, venue.Address, venue.NormalizedAddress, venue.Latitude, venue.Longitude,
+		venue.Surface, venue.Indoor, rawVenueSportTypes(venue), rawVenueAmenities(venue), venue.URLPhotos)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EditVenue updates venue and moves coordinates of all events held there.
+func (p *PostgresStorage) EditVenue(ctx context.Context, venue *models.Venue) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	sqlUpdate := `
+	UPDATE "public".venue SET name = $1, address = $2, normalized_address = $3,
+		coordinates = ST_Point($4, $5, 4326)::geography, surface = $6, indoor = $7,
+		sport_types = $8, amenities = $9, url_photos = $10
+		WHERE id = $11 AND deleted_at IS NULL;`
+
+	tag, err := tx.Exec(ctx, sqlUpdate,
+		venue.Name, venue.Address, venue.NormalizedAddress, venue.Latitude, venue.Longitude, venue.Surface,
+		venue.Indoor, rawVenueSportTypes(venue), rawVenueAmenities(venue), venue.URLPhotos, venue.ID)
+	if err != nil {
+		return fmt.Errorf("to update venue: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFoundVenue
+	}
+
+	sqlUpdateEvents := `
+	UPDATE "public".event SET coordinates = v.coordinates
+		FROM "public".venue v WHERE event.venue_id = v.id AND v.id = $1 AND event.deleted_at IS NULL;`
+
+	_, err = tx.Exec(ctx, sqlUpdateEvents, venue.ID)
+	if err != nil {
+		return fmt.Errorf("to update venue events coordinates: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+const sqlSelectVenue = `
+	SELECT id, creator_id, name, address, normalized_address,
+		ST_X(coordinates::geometry) as latitude, ST_Y(coordinates::geometry) as longitude,
+		surface, indoor, sport_types, amenities, url_photos, created_at
+	FROM "public".venue`
+
+func scanVenue(row pgx.Row) (*models.Venue, error) {
+	var (
+		venue         models.Venue
+		rawSportTypes pgtype.Array[string]
+		rawAmenities  pgtype.Array[string]
+		rawURLPhotos  pgtype.Array[string]
+	)
+
+	err := row.Scan(&venue.ID, &venue.CreatorID, &venue.Name, &venue.Address, &venue.NormalizedAddress,
+		&venue.Latitude, &venue.Longitude, &venue.Surface, &venue.Indoor, &rawSportTypes, &rawAmenities,
+		&rawURLPhotos, &venue.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	venue.SportTypes = common.Map(func(item string) models.SportType {
+		return models.SportType(item)
+	}, rawSportTypes.Elements)
+	venue.Amenities = common.Map(func(item string) models.VenueAmenity {
+		return models.VenueAmenity(item)
+	}, rawAmenities.Elements)
+	venue.URLPhotos = rawURLPhotos.Elements
+
+	if venue.URLPhotos == nil {
+		venue.URLPhotos = []string{}
+	}
+
+	return &venue, nil
+}
+
+func (p *PostgresStorage) GetVenue(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+	sqlSelect := sqlSelectVenue + ` WHERE id = $1 AND deleted_at IS NULL;`
+
+	venue, err := scanVenue(p.pool.QueryRow(ctx, sqlSelect, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFoundVenue
+		}
+
+		return nil, fmt.Errorf("to scan venue: %w", err)
+	}
+
+	return venue, nil
+}
+
+// FindVenues is used for autocomplete, so it matches by part of name or address
+// and puts the most similar venues first.
+func (p *PostgresStorage) FindVenues(
+	ctx context.Context,
+	query string,
+	sportType *models.SportType,
+	limit int,
+) ([]models.Venue, error) {
+	sqlSelect := sqlSelectVenue + ` WHERE deleted_at IS NULL
+		AND ($1 = '' OR LOWER(name) LIKE '%' || LOWER($1) || '%'
+			OR LOWER(normalized_address) LIKE '%' || LOWER($1) || '%')
+		AND ($2::sport_type_enum IS NULL OR $2::sport_type_enum = ANY(sport_types))
+		ORDER BY GREATEST(similarity(LOWER(name), LOWER($1)), similarity(LOWER(normalized_address), LOWER($1))) DESC,
+			name
+		LIMIT $3;`
+
+	rawRows, err := p.pool.Query(ctx, sqlSelect, query, sportType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("to select venues: %w", err)
+	}
+	defer rawRows.Close()
+
+	result := []models.Venue{}
+
+	for rawRows.Next() {
+		venue, err := scanVenue(rawRows)
+		if err != nil {
+			return nil, fmt.Errorf("to scan venue: %w", err)
+		}
+
+		result = append(result, *venue)
+	}
+
+	if err := rawRows.Err(); err != nil {
+		return nil, fmt.Errorf("to read venues: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpsertVenueByOSMID creates or refreshes venue imported from OpenStreetMap.
+// Photos set by users are kept and amenities are merged with existing ones.
+func (p *PostgresStorage) UpsertVenueByOSMID(ctx context.Context, osmID string, venue *models.Venue) (bool, error) {
+	sqlUpsert := `
+	INSERT INTO "public".venue (id, name, address, normalized_address, coordinates,
+		surface, indoor, sport_types, amenities, url_photos, osm_id)
+		VALUES ($1, $2, $3, $4, ST_Point($5, $6, 4326)::geography, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (osm_id) DO UPDATE SET name = EXCLUDED.name, address = EXCLUDED.address,
+		normalized_address = EXCLUDED.normalized_address, coordinates = EXCLUDED.coordinates,
+		surface = EXCLUDED.surface, indoor = EXCLUDED.indoor, sport_types = EXCLUDED.sport_types,
+		amenities = ARRAY(SELECT DISTINCT UNNEST(venue.amenities || EXCLUDED.amenities))
+	RETURNING id, (xmax = 0) AS inserted;`
+
+	var inserted bool
+
+	err := p.pool.QueryRow(ctx, sqlUpsert,
+		venue.ID, venue.Name, venue.Address, venue.NormalizedAddress, venue.Latitude, venue.Longitude,
+		venue.Surface, venue.Indoor, rawVenueSportTypes(venue), rawVenueAmenities(venue), venue.URLPhotos, osmID,
+	).Scan(&venue.ID, &inserted)
+	if err != nil {
+		return false, fmt.Errorf("to upsert venue: %w", err)
+	}
+
+	if inserted {
+		return true, nil
+	}
+
+	sqlUpdateEvents := `
+	UPDATE "public".event SET coordinates = ST_Point($1, $2, 4326)::geography
+		WHERE venue_id = $3 AND deleted_at IS NULL;`
+
+	_, err = p.pool.Exec(ctx, sqlUpdateEvents, venue.Latitude, venue.Longitude, venue.ID)
+	if err != nil {
+		return false, fmt.Errorf("to update venue events coordinates: %w", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TheVovchenskiy/sportify-backend/pkg/s3"
+)
+
+// S3Storage keeps files in bucket of S3-compatible storage, so every instance of app sees the same files.
+type S3Storage struct {
+	client         *s3.Client
+	publicURL      string
+	presignExpires time.Duration
+	// existingFiles saves HEAD requests, files are never deleted, so positive answer can't become wrong.
+	existingFiles   map[string]struct{}
+	muExistingFiles *sync.RWMutex
+}
+
+var ErrPresignExpires = errors.New("presign expires must be positive and not longer than 7 days")
+
+const (
+	s3Timeout        = time.Minute
+	publicFileMaxAge = 7 * 24 * time.Hour
+)
+
+// NewS3Storage makes storage, links to files are presigned when publicURL of bucket is empty.
+func NewS3Storage(cfg s3.Config, publicURL string, presignExpires time.Duration) (*S3Storage, error) {
+	if presignExpires <= 0 || presignExpires > s3.MaxPresignExpires {
+		return nil, fmt.Errorf("%w: %s", ErrPresignExpires, presignExpires)
+	}
+
+	client, err := s3.New(cfg, &http.Client{Timeout: s3Timeout}) //nolint:exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("to new s3 client: %w", err)
+	}
+
+	return &S3Storage{
+		client:          client,
+		publicURL:       publicURL,
+		presignExpires:  presignExpires,
+		existingFiles:   make(map[string]struct{}),
+		muExistingFiles: &sync.RWMutex{},
+	}, nil
+}
+
+func (s *S3Storage) markExisting(fileName string) {
+	s.muExistingFiles.Lock()
+	s.existingFiles[fileName] = struct{}{}
+	s.muExistingFiles.Unlock()
+}
+
+// Check bool in return slice means file exist if it's true.
+func (s *S3Storage) Check(ctx context.Context, files []string) ([]bool, error) {
+	result := make([]bool, len(files))
+
+	for i, fileName := range files {
+		s.muExistingFiles.RLock()
+		_, ok := s.existingFiles[fileName]
+		s.muExistingFiles.RUnlock()
+
+		if ok {
+			result[i] = true
+			continue
+		}
+
+		exists, err := s.client.ObjectExists(ctx, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("to check object: %w", err)
+		}
+
+		if exists {
+			s.markExisting(fileName)
+		}
+
+		result[i] = exists
+	}
+
+	return result, nil
+}
+
+func (s *S3Storage) SaveFile(ctx context.Context, content []byte, fileName string) error {
+	err := s.client.PutObject(ctx, fileName, content, http.DetectContentType(content))
+	if err != nil {
+		return fmt.Errorf("to put object: %w", err)
+	}
+
+	s.markExisting(fileName)
+
+	return nil
+}
+
+func (s *S3Storage) ReadFile(ctx context.Context, fileName string) ([]byte, error) {
+	content, err := s.client.GetObject(ctx, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("to get object: %w", err)
+	}
+
+	return content, nil
+}
+
+// FileURL is link for reading of file by browser, route of files redirects to it. Second value
+// is how long browser can keep redirect, names of files are hashes, so public link never changes.
+func (s *S3Storage) FileURL(fileName string) (string, time.Duration) {
+	if s.publicURL != "" {
+		return s.publicURL + fileName, publicFileMaxAge
+	}
+
+	url, validUntil := s.client.PresignGetObject(fileName, s.presignExpires)
+
+	return url, time.Until(validUntil)
+}
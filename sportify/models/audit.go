@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityEvent       AuditEntity = "event"
+	AuditEntityParticipant AuditEntity = "participant"
+	AuditEntityPayment     AuditEntity = "payment"
+	AuditEntityProfile     AuditEntity = "profile"
+	AuditEntityComment     AuditEntity = "comment"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+	AuditActionStatus AuditAction = "status"
+	AuditActionJoin   AuditAction = "join"
+	AuditActionLeave  AuditAction = "leave"
+)
+
+type AuditSource string
+
+const (
+	AuditSourceSite   AuditSource = "site"
+	AuditSourceTg     AuditSource = "tg"
+	AuditSourceAdmin  AuditSource = "admin"
+	AuditSourceSystem AuditSource = "system"
+)
+
+// AuditRecord is one change, Before and After are JSON of changed entity.
+type AuditRecord struct {
+	ID         int64           `json:"id"`
+	EntityType AuditEntity     `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	EventID    *uuid.UUID      `json:"event_id"`
+	Action     AuditAction     `json:"action"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	Source     AuditSource     `json:"source"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditSettings are taken from config, zero Retention keeps records forever.
+type AuditSettings struct {
+	Retention    time.Duration
+	ModeratorIDs []uuid.UUID
+}
+
+const (
+	DefaultAuditHistoryLimit = 100
+	MaxAuditHistoryLimit     = 500
+)
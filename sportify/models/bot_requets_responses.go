@@ -9,22 +9,24 @@ type BotUser struct {
 }
 
 type BotEvent struct {
-	ID          uuid.UUID   `json:"id"`
-	Description *string     `json:"description"`
-	Creator     BotUser     `json:"creator"`
-	SportType   SportType   `json:"sport_type"`
-	Address     string      `json:"address"`
-	DateAndTime DateAndTime `json:"date_and_time"`
-	Price       *int        `json:"price"`
-	IsFree      bool        `json:"is_free"`
-	GameLevels  []GameLevel `json:"game_levels"`
-	Capacity    *int        `json:"capacity"`
-	Busy        int         `json:"busy"`
-	Subscribers []BotUser   `json:"subscribers"`
-	URLPreview  string      `json:"url_preview"`
-	Latitude    *string     `json:"latitude,omitempty"`
-	Longitude   *string     `json:"longitude,omitempty"`
-	Hashtags    *[]string   `json:"hashtags,omitempty"`
+	ID           uuid.UUID   `json:"id"`
+	Description  *string     `json:"description"`
+	Creator      BotUser     `json:"creator"`
+	SportType    SportType   `json:"sport_type"`
+	Address      string      `json:"address"`
+	DateAndTime  DateAndTime `json:"date_and_time"`
+	Price        *int        `json:"price"`
+	IsFree       bool        `json:"is_free"`
+	GameLevels   []GameLevel `json:"game_levels"`
+	Capacity     *int        `json:"capacity"`
+	Busy         int         `json:"busy"`
+	Status       EventStatus `json:"status"`
+	CancelReason *string     `json:"cancel_reason,omitempty"`
+	Subscribers  []BotUser   `json:"subscribers"`
+	URLPreview   string      `json:"url_preview"`
+	Latitude     *string     `json:"latitude,omitempty"`
+	Longitude    *string     `json:"longitude,omitempty"`
+	Hashtags     *[]string   `json:"hashtags,omitempty"`
 }
 
 type EventCreatedBotRequest struct {
@@ -41,11 +43,24 @@ type EventCreatedBotResponse struct {
 
 type EventUpdatedBotRequest struct {
 	TgUserIDsToNotify []int64  `json:"tg_user_ids_to_notify,omitempty"`
+	TextToNotify      string   `json:"text_to_notify,omitempty"`
 	TgChatID          *int64   `json:"tg_chat_id"`
 	TgMessageID       *int64   `json:"tg_message_id"`
 	Event             BotEvent `json:"event"`
 }
 
+// MessageBotRequest is plain text message to users in private chat with bot.
+type MessageBotRequest struct {
+	TgUserIDs []int64 `json:"tg_user_ids"`
+	Text      string  `json:"text"`
+}
+
+// ChatMessageBotRequest is plain text message to group chat where bot is added.
+type ChatMessageBotRequest struct {
+	TgChatID int64  `json:"tg_chat_id"`
+	Text     string `json:"text"`
+}
+
 type EventDeletedBotRequest struct {
 	TgChatID    *int64    `json:"tg_chat_id"`
 	TgMessageID *int64    `json:"tg_message_id"`
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CalendarTimeZone is zone of time of events, they are saved as moscow time marked as UTC.
+	CalendarTimeZone = "Europe/Moscow"
+
+	calendarDateTimeLayout = "20060102T150405"
+	calendarLineLen        = 75
+	// calendarSequenceEpoch is 2024-01-01 UTC, it is before creation of any event.
+	calendarSequenceEpoch = 1704067200
+)
+
+// calendarTimeZone describes CalendarTimeZone for clients without tz database, moscow has no summer time.
+const calendarTimeZone = "BEGIN:VTIMEZONE\r\n" +
+	"TZID:" + CalendarTimeZone + "\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19700101T000000\r\n" +
+	"TZOFFSETFROM:+0300\r\n" +
+	"TZOFFSETTO:+0300\r\n" +
+	"TZNAME:MSK\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n"
+
+type CalendarEvent struct {
+	ID          uuid.UUID
+	Summary     string
+	Description *string
+	Location    string
+	Latitude    *string
+	Longitude   *string
+	URL         string
+	Start       time.Time
+	End         *time.Time
+	Status      EventStatus
+	UpdatedAt   time.Time
+}
+
+func NewCalendarEvent(event *ShortEvent, description *string, url string) CalendarEvent {
+	return CalendarEvent{
+		ID:          event.ID,
+		Summary:     sportTypeTitle(event.SportType),
+		Description: description,
+		Location:    event.Address,
+		Latitude:    event.Latitude,
+		Longitude:   event.Longitude,
+		URL:         url,
+		Start:       event.DateAndTime.StartTime,
+		End:         event.DateAndTime.EndTime,
+		Status:      event.Status,
+		UpdatedAt:   event.UpdatedAt,
+	}
+}
+
+func (e *CalendarEvent) status() string {
+	switch e.Status {
+	case EventStatusCancelled:
+		return "CANCELLED"
+	case EventStatusPostponed:
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+// sequence is made from time of update, it is seconds since the first events of service,
+// so it fits to integer of RFC 5545.
+func (e *CalendarEvent) sequence() int64 {
+	return max(e.UpdatedAt.Unix()-calendarSequenceEpoch, 0)
+}
+
+// escapeCalendarText escapes value of TEXT property by RFC 5545.
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeCalendarLine folds line longer than 75 octets without breaking utf-8 symbols.
+func writeCalendarLine(buf *bytes.Buffer, line string) {
+	limit := calendarLineLen
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+
+		line = line[cut:]
+		// space in the beginning of next line is part of folding
+		limit = calendarLineLen - 1
+	}
+
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+func formatCalendarUTC(t time.Time) string {
+	return t.UTC().Format(calendarDateTimeLayout) + "Z"
+}
+
+func formatCalendarTime(t time.Time) string {
+	return "TZID=" + CalendarTimeZone + ":" + t.Format(calendarDateTimeLayout)
+}
+
+// FormatCalendar makes iCalendar file, stamp is time when file is made.
+func FormatCalendar(name string, events []CalendarEvent, stamp time.Time) []byte {
+	buf := &bytes.Buffer{}
+
+	writeCalendarLine(buf, "BEGIN:VCALENDAR")
+	writeCalendarLine(buf, "VERSION:2.0")
+	writeCalendarLine(buf, "PRODID:-//Sportify//Events//RU")
+	writeCalendarLine(buf, "CALSCALE:GREGORIAN")
+	writeCalendarLine(buf, "METHOD:PUBLISH")
+	writeCalendarLine(buf, "X-WR-CALNAME:"+escapeCalendarText(name))
+	writeCalendarLine(buf, "X-WR-TIMEZONE:"+CalendarTimeZone)
+	buf.WriteString(calendarTimeZone)
+
+	for _, event := range events {
+		writeCalendarLine(buf, "BEGIN:VEVENT")
+		writeCalendarLine(buf, "UID:"+event.ID.String())
+		writeCalendarLine(buf, "DTSTAMP:"+formatCalendarUTC(stamp))
+		// sequence only grows, so clients replace their copy of changed event
+		writeCalendarLine(buf, fmt.Sprintf("SEQUENCE:%d", event.sequence()))
+		writeCalendarLine(buf, "LAST-MODIFIED:"+formatCalendarUTC(event.UpdatedAt))
+		writeCalendarLine(buf, "DTSTART;"+formatCalendarTime(event.Start))
+
+		if event.End != nil {
+			writeCalendarLine(buf, "DTEND;"+formatCalendarTime(*event.End))
+		}
+
+		writeCalendarLine(buf, "SUMMARY:"+escapeCalendarText(event.Summary))
+
+		if event.Description != nil && *event.Description != "" {
+			writeCalendarLine(buf, "DESCRIPTION:"+escapeCalendarText(*event.Description))
+		}
+
+		if event.Location != "" {
+			writeCalendarLine(buf, "LOCATION:"+escapeCalendarText(event.Location))
+		}
+
+		if event.Latitude != nil && event.Longitude != nil {
+			writeCalendarLine(buf, fmt.Sprintf("GEO:%s;%s", *event.Latitude, *event.Longitude))
+		}
+
+		writeCalendarLine(buf, "URL:"+event.URL)
+		writeCalendarLine(buf, "STATUS:"+event.status())
+		writeCalendarLine(buf, "END:VEVENT")
+	}
+
+	writeCalendarLine(buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+type ResponseCalendarFeed struct {
+	URL string `json:"url"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCalendar(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 3, 8, 19, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	latitude, longitude := "55.7158", "37.5537"
+	description := "Играем 5 на 5, берите\nманишки; мячи есть, " + strings.Repeat("очень ", 20)
+
+	event := NewCalendarEvent(&ShortEvent{ //nolint:exhaustruct
+		ID:          uuid.MustParse("6f1d7c3e-2b1a-4a55-9a0e-0c3c2b1a4a55"),
+		SportType:   SportTypeFootball,
+		Address:     "Москва, Лужники",
+		DateAndTime: DateAndTime{Date: start, StartTime: start, EndTime: &end},
+		Status:      EventStatusCancelled,
+		Latitude:    &latitude,
+		Longitude:   &longitude,
+		UpdatedAt:   time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC),
+	}, &description, "https://example.com/events/6f1d7c3e-2b1a-4a55-9a0e-0c3c2b1a4a55")
+
+	calendar := string(FormatCalendar("Мои игры", []CalendarEvent{event}, start))
+
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	assert.Contains(t, calendar, "DTSTART;TZID=Europe/Moscow:20250308T190000\r\n")
+	assert.Contains(t, calendar, "DTEND;TZID=Europe/Moscow:20250308T210000\r\n")
+	assert.Contains(t, calendar, "DTSTAMP:20250308T190000Z\r\n")
+	assert.Contains(t, calendar, "LAST-MODIFIED:20250301T103000Z\r\n")
+	assert.Contains(t, calendar, "SEQUENCE:36757800\r\n")
+	assert.Contains(t, calendar, "SUMMARY:Футбол\r\n")
+	assert.Contains(t, calendar, "LOCATION:Москва\\, Лужники\r\n")
+	assert.Contains(t, calendar, "GEO:55.7158;37.5537\r\n")
+	assert.Contains(t, calendar, "STATUS:CANCELLED\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(calendar, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), calendarLineLen)
+	}
+
+	changed := event
+	changed.UpdatedAt = changed.UpdatedAt.Add(time.Second)
+	assert.Greater(t, changed.sequence(), event.sequence())
+
+	unfolded := strings.ReplaceAll(calendar, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+escapeCalendarText(description)+"\r\n")
+	assert.Contains(t, unfolded, `берите\nманишки\; мячи есть\, `)
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type ClubRole string
+
+const (
+	ClubRoleMember ClubRole = "member"
+	ClubRoleAdmin  ClubRole = "admin"
+)
+
+type Club struct {
+	ID          uuid.UUID `json:"id"`
+	CreatorID   uuid.UUID `json:"creator_id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	AvatarURL   *string   `json:"avatar_url"`
+	TgChatIDs   []int64   `json:"tg_chat_ids"`
+	CountMember int       `json:"count_member"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (c *Club) GetAvatarURL(urlPrefixFile string) string {
+	if c.AvatarURL != nil {
+		return *c.AvatarURL
+	}
+
+	return urlPrefixFile + "user-default-avatar.png"
+}
+
+type ClubMember struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Role      ClubRole  `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ClubMemberAPI struct {
+	UserShortcutAPI
+	Role ClubRole `json:"role"`
+}
+
+type ClubPageAPI struct {
+	Club
+	Members        []ClubMemberAPI `json:"members"`
+	UpcomingEvents []ShortEvent    `json:"upcoming_events"`
+}
+
+type RequestClubCreate struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+func (r *RequestClubCreate) Valid() error {
+	r.Name = strings.TrimSpace(r.Name)
+
+	if r.Name == "" {
+		return fmt.Errorf("название клуба не может быть пустым")
+	}
+
+	if utf8.RuneCountInString(r.Name) > 256 {
+		return fmt.Errorf("название клуба должно быть короче 256 символов")
+	}
+
+	if r.Description != nil && utf8.RuneCountInString(*r.Description) > 4096 {
+		return fmt.Errorf("описание должно быть короче 4096 символов")
+	}
+
+	return nil
+}
+
+type RequestClubEdit struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	AvatarURL   *string `json:"avatar_url"`
+	// TgChatIDs can only unlink chats, new chat is linked by ClubTgLinkCommand posted in it.
+	TgChatIDs []int64 `json:"tg_chat_ids"`
+}
+
+type RequestClubMemberRole struct {
+	Role ClubRole `json:"role"`
+}
+
+func (r *RequestClubMemberRole) Valid() error {
+	if r.Role != ClubRoleMember && r.Role != ClubRoleAdmin {
+		return fmt.Errorf("неизвестная роль %q", r.Role)
+	}
+
+	return nil
+}
+
+type ResponseClubAvatar struct {
+	URL string `json:"url"`
+}
+
+// ClubTgLinkCommand is posted by club admin to telegram chat, so bot sees that admin has access to chat.
+const ClubTgLinkCommand = "/link_club"
+
+type ResponseClubTgLinkCode struct {
+	Command   string    `json:"command"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ParseClubTgLinkCommand returns code from message like "/link_club code" or "/link_club@bot code".
+func ParseClubTgLinkCommand(message string) (string, bool) {
+	fields := strings.Fields(message)
+	if len(fields) != 2 { //nolint:mnd
+		return "", false
+	}
+
+	command, _, _ := strings.Cut(fields[0], "@")
+	if command != ClubTgLinkCommand {
+		return "", false
+	}
+
+	return fields[1], true
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClubTgLinkCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		message  string
+		wantCode string
+		wantOK   bool
+	}{
+		{name: "plain", message: "/link_club abc123", wantCode: "abc123", wantOK: true},
+		{name: "bot mention", message: " /link_club@sportify_bot abc123\n", wantCode: "abc123", wantOK: true},
+		{name: "no code", message: "/link_club", wantOK: false},
+		{name: "extra words", message: "/link_club abc123 please", wantOK: false},
+		{name: "other command", message: "/start abc123", wantOK: false},
+		{name: "event message", message: "Футбол завтра в 19:00", wantOK: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, ok := ParseClubTgLinkCommand(testCase.message)
+			assert.Equal(t, testCase.wantOK, ok)
+			assert.Equal(t, testCase.wantCode, code)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxCommentLen = 2000
+	// MaxMentions limits notifications of one comment, other mentions are not notified.
+	MaxMentions = 10
+)
+
+// Comment of deleted comment has empty text, it stays in thread because of replies.
+type Comment struct {
+	ID        uuid.UUID  `json:"id"`
+	EventID   uuid.UUID  `json:"event_id"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	Text      string     `json:"text"`
+	Deleted   bool       `json:"deleted"`
+	EditedAt  *time.Time `json:"edited_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type CommentAPI struct {
+	Comment
+	Author UserShortcutAPI `json:"author"`
+}
+
+var (
+	ErrEmptyComment   = errors.New("Комментарий не может быть пустым")
+	ErrTooLongComment = errors.New("Комментарий должен быть короче 2000 символов")
+)
+
+func validCommentText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+
+	if text == "" {
+		return "", ErrEmptyComment
+	}
+
+	if utf8.RuneCountInString(text) > MaxCommentLen {
+		return "", ErrTooLongComment
+	}
+
+	return text, nil
+}
+
+type RequestCommentCreate struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+	Text     string     `json:"text"`
+}
+
+func (r *RequestCommentCreate) Valid() error {
+	text, err := validCommentText(r.Text)
+	if err != nil {
+		return err
+	}
+
+	r.Text = text
+
+	return nil
+}
+
+type RequestCommentEdit struct {
+	Text string `json:"text"`
+}
+
+func (r *RequestCommentEdit) Valid() error {
+	text, err := validCommentText(r.Text)
+	if err != nil {
+		return err
+	}
+
+	r.Text = text
+
+	return nil
+}
+
+// RequestCommentSettings is set by organizer for all comments of event.
+type RequestCommentSettings struct {
+	// MirrorToTg sends comments to chat of telegram post as reply to it.
+	MirrorToTg bool `json:"mirror_to_tg"`
+}
+
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\w@])@(\w{3,64})`)
+
+// ParseMentions returns usernames mentioned as @username, every one once, not more than MaxMentions.
+func ParseMentions(text string) []string {
+	result := make([]string, 0)
+	seen := make(map[string]struct{})
+
+	for _, match := range mentionRegexp.FindAllStringSubmatch(text, -1) {
+		if len(result) == MaxMentions {
+			break
+		}
+
+		username := match[1]
+		if _, ok := seen[username]; ok {
+			continue
+		}
+
+		seen[username] = struct{}{}
+		result = append(result, username)
+	}
+
+	return result
+}
+
+// CommentBotRequest asks bot to post comment as reply to message of event.
+type CommentBotRequest struct {
+	TgChatID    int64  `json:"tg_chat_id"`
+	TgMessageID int64  `json:"tg_message_id"`
+	Author      string `json:"author"`
+	Text        string `json:"text"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"ivan", "petr_2"},
+		ParseMentions("@ivan, есть парковка? спроси @petr_2 и @ivan"))
+	assert.Equal(t, []string{}, ParseMentions("пишите на mail@example.com или @@ab"))
+
+	text := ""
+	for i := range MaxMentions + 5 {
+		text += fmt.Sprintf(" @user%d @user%d", i, i)
+	}
+
+	mentions := ParseMentions(text)
+	assert.Len(t, mentions, MaxMentions)
+	assert.Equal(t, "user0", mentions[0])
+	assert.Equal(t, fmt.Sprintf("user%d", MaxMentions-1), mentions[MaxMentions-1])
+}
+
+func TestRequestCommentCreateValid(t *testing.T) {
+	t.Parallel()
+
+	request := RequestCommentCreate{Text: "  есть парковка?  "} //nolint:exhaustruct
+	assert.NoError(t, request.Valid())
+	assert.Equal(t, "есть парковка?", request.Text)
+
+	request = RequestCommentCreate{Text: "   "} //nolint:exhaustruct
+	assert.ErrorIs(t, request.Valid(), ErrEmptyComment)
+
+	request = RequestCommentCreate{Text: strings.Repeat("я", MaxCommentLen+1)} //nolint:exhaustruct
+	assert.ErrorIs(t, request.Valid(), ErrTooLongComment)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+type CoordinatesJobState string
+
+const (
+	CoordinatesJobStatePending CoordinatesJobState = "pending"
+	// CoordinatesJobStateFailed is set after max attempts, job is retried only when event address changes.
+	CoordinatesJobStateFailed CoordinatesJobState = "failed"
+)
+
+type CoordinatesJob struct {
+	EventID  uuid.UUID
+	Address  string
+	Attempts int
+}
@@ -49,6 +49,8 @@ func NewFullEventSite(eventID uuid.UUID, userID uuid.UUID, eventCreteSite *Event
 		ShortEvent: ShortEvent{
 			ID:        eventID,
 			CreatorID: userID,
+			ClubID:    eventCreteSite.ClubID,
+			VenueID:   eventCreteSite.VenueID,
 			SportType: eventCreteSite.SportType,
 			Address:   eventCreteSite.Address,
 			DateAndTime: DateAndTime{
@@ -61,6 +63,7 @@ func NewFullEventSite(eventID uuid.UUID, userID uuid.UUID, eventCreteSite *Event
 			GameLevels:  eventCreteSite.GameLevels,
 			Capacity:    eventCreteSite.Capacity,
 			Busy:        0,
+			Status:      EventStatusScheduled,
 			Subscribers: make([]uuid.UUID, 0),
 			URLPreview:  eventCreteSite.URLPreview,
 			URLPhotos:   eventCreteSite.URLPhotos,
@@ -77,42 +80,53 @@ func (e *FullEvent) ToBotEvent(creator *BotUser, subscribers []*BotUser, hashtag
 	}
 
 	return &BotEvent{ //nolint:exhaustruct
-		ID:          e.ID,
-		Creator:     *creator,
-		Description: e.Description,
-		SportType:   e.SportType,
-		Address:     e.Address,
-		DateAndTime: e.DateAndTime,
-		Price:       e.Price,
-		IsFree:      e.IsFree,
-		GameLevels:  e.GameLevels,
-		Capacity:    e.Capacity,
-		Busy:        e.Busy,
-		Subscribers: subs,
-		URLPreview:  e.URLPreview,
-		Latitude:    e.Latitude,
-		Longitude:   e.Longitude,
-		Hashtags:    hashtags,
+		ID:           e.ID,
+		Creator:      *creator,
+		Description:  e.Description,
+		SportType:    e.SportType,
+		Address:      e.Address,
+		DateAndTime:  e.DateAndTime,
+		Price:        e.Price,
+		IsFree:       e.IsFree,
+		GameLevels:   e.GameLevels,
+		Capacity:     e.Capacity,
+		Busy:         e.Busy,
+		Status:       e.Status,
+		CancelReason: e.CancelReason,
+		Subscribers:  subs,
+		URLPreview:   e.URLPreview,
+		Latitude:     e.Latitude,
+		Longitude:    e.Longitude,
+		Hashtags:     hashtags,
 	}
 }
 
 type ShortEvent struct {
-	ID                        uuid.UUID   `json:"id"`
-	CreatorID                 uuid.UUID   `json:"creator_id"`
-	SportType                 SportType   `json:"sport_type"`
-	Address                   string      `json:"address"`
-	DateAndTime               DateAndTime `json:"date_time"`
-	Price                     *int        `json:"price"`
-	IsFree                    bool        `json:"is_free"`
-	GameLevels                []GameLevel `json:"game_level"`
-	Capacity                  *int        `json:"capacity"`
-	Busy                      int         `json:"busy"`
-	Subscribers               []uuid.UUID `json:"subscribers_id"`
-	URLPreview                string      `json:"preview"`
-	URLPhotos                 []string    `json:"photos"`
-	Latitude                  *string     `json:"latitude"`
-	Longitude                 *string     `json:"longitude"`
-	ExpirationTimeCoordinates time.Time   `json:"-"`
+	ID                        uuid.UUID       `json:"id"`
+	CreatorID                 uuid.UUID       `json:"creator_id"`
+	ClubID                    *uuid.UUID      `json:"club_id"`
+	VenueID                   *uuid.UUID      `json:"venue_id"`
+	SportType                 SportType       `json:"sport_type"`
+	Address                   string          `json:"address"`
+	DateAndTime               DateAndTime     `json:"date_time"`
+	Price                     *int            `json:"price"`
+	IsFree                    bool            `json:"is_free"`
+	GameLevels                []GameLevel     `json:"game_level"`
+	Capacity                  *int            `json:"capacity"`
+	Busy                      int             `json:"busy"`
+	Status                    EventStatus     `json:"status"`
+	CancelReason              *string         `json:"cancel_reason"`
+	Subscribers               []uuid.UUID     `json:"subscribers_id"`
+	URLPreview                string          `json:"preview"`
+	URLPhotos                 []string        `json:"photos"`
+	Latitude                  *string         `json:"latitude"`
+	Longitude                 *string         `json:"longitude"`
+	AddressDetails            *AddressDetails `json:"address_details"`
+	Snippet                   *string         `json:"snippet,omitempty"`
+	ExpirationTimeCoordinates time.Time       `json:"-"`
+	SortValue                 string          `json:"-"`
+	// UpdatedAt is time of the last change of row, calendar apps find changed events by it.
+	UpdatedAt time.Time `json:"-"`
 }
 
 func IsFreePrice(price *int) bool {
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventChange is significant change of event which participants are told about.
+type EventChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+func formatEventTime(dateAndTime *DateAndTime) string {
+	result := dateAndTime.StartTime.Format("02.01 15:04")
+	if dateAndTime.EndTime != nil {
+		result += "–" + dateAndTime.EndTime.Format("15:04")
+	}
+
+	return result
+}
+
+func formatEventPrice(price *int) string {
+	if IsFreePrice(price) {
+		return "бесплатно"
+	}
+
+	return fmt.Sprintf("%d ₽", *price)
+}
+
+func formatEventCapacity(capacity *int) string {
+	if capacity == nil {
+		return "без ограничений"
+	}
+
+	return fmt.Sprintf("%d", *capacity)
+}
+
+// formatGameLevels writes levels from the lowest one, so order of levels in request is not a change.
+func formatGameLevels(gameLevels []GameLevel) string {
+	if len(gameLevels) == 0 {
+		return "любой"
+	}
+
+	result := make([]string, 0, len(gameLevels))
+
+	for _, level := range gameLevelsOrder {
+		for _, cur := range gameLevels {
+			if cur != level {
+				continue
+			}
+
+			ruLevel, _ := EnToRuGameLevel(level)
+			result = append(result, ruLevel)
+
+			break
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// DiffEvents returns changes of time, address, price, capacity and levels. Photos, description
+// and other fields are not interesting for participants, so they are skipped.
+func DiffEvents(before, after *ShortEvent) []EventChange {
+	fields := []EventChange{
+		{Field: "Время", Old: formatEventTime(&before.DateAndTime), New: formatEventTime(&after.DateAndTime)},
+		{Field: "Адрес", Old: strings.TrimSpace(before.Address), New: strings.TrimSpace(after.Address)},
+		{Field: "Цена", Old: formatEventPrice(before.Price), New: formatEventPrice(after.Price)},
+		{Field: "Мест", Old: formatEventCapacity(before.Capacity), New: formatEventCapacity(after.Capacity)},
+		{Field: "Уровень", Old: formatGameLevels(before.GameLevels), New: formatGameLevels(after.GameLevels)},
+	}
+
+	result := make([]EventChange, 0, len(fields))
+
+	for _, field := range fields {
+		if field.Old != field.New {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// FormatEventChanges is message for participants, one line per change: "Цена: бесплатно → 500 ₽".
+func FormatEventChanges(title string, changes []EventChange) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Событие «%s» изменено:", title)
+
+	for _, change := range changes {
+		fmt.Fprintf(&builder, "\n%s: %s → %s", change.Field, change.Old, change.New)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEvents(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 3, 8, 19, 0, 0, 0, time.UTC)
+	price := 500
+
+	before := ShortEvent{ //nolint:exhaustruct
+		Address:     "Москва, Лужники",
+		DateAndTime: DateAndTime{Date: start, StartTime: start, EndTime: nil},
+		GameLevels:  []GameLevel{GameLevelMid, GameLevelLow},
+		URLPhotos:   []string{"old.jpeg"},
+	}
+
+	after := before
+	after.URLPhotos = []string{"new.jpeg"}
+	after.GameLevels = []GameLevel{GameLevelLow, GameLevelMid}
+
+	assert.Empty(t, DiffEvents(&before, &after))
+
+	after.DateAndTime.StartTime = start.Add(90 * time.Minute)
+	after.Price = &price
+
+	changes := DiffEvents(&before, &after)
+	assert.Equal(t, []EventChange{
+		{Field: "Время", Old: "08.03 19:00", New: "08.03 20:30"},
+		{Field: "Цена", Old: "бесплатно", New: "500 ₽"},
+	}, changes)
+	assert.Equal(t, "Событие «Футбол» изменено:\nВремя: 08.03 19:00 → 08.03 20:30\nЦена: бесплатно → 500 ₽",
+		FormatEventChanges("Футбол", changes))
+}
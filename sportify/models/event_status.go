@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type EventStatus string
+
+const (
+	EventStatusScheduled EventStatus = "scheduled"
+	EventStatusCancelled EventStatus = "cancelled"
+	EventStatusFinished  EventStatus = "finished"
+	EventStatusPostponed EventStatus = "postponed"
+)
+
+// eventStatusTransitions lists statuses event can get from current one,
+// cancelled and finished events are final.
+var eventStatusTransitions = map[EventStatus][]EventStatus{ //nolint:gochecknoglobals
+	EventStatusScheduled: {EventStatusCancelled, EventStatusFinished, EventStatusPostponed},
+	EventStatusPostponed: {EventStatusScheduled, EventStatusCancelled},
+}
+
+func (s EventStatus) CanChangeTo(next EventStatus) bool {
+	for _, status := range eventStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Active is true while participants can join event.
+func (s EventStatus) Active() bool {
+	return s == EventStatusScheduled || s == EventStatusPostponed
+}
+
+const MaxCancelReasonLen = 500
+
+var ErrInvalidEventStatus = errors.New("Неизвестный статус события")
+
+type RequestEventStatus struct {
+	Status EventStatus `json:"status"`
+	Reason *string     `json:"reason"`
+}
+
+func (r *RequestEventStatus) Valid() error {
+	switch r.Status {
+	case EventStatusScheduled, EventStatusCancelled, EventStatusFinished, EventStatusPostponed:
+	default:
+		return ErrInvalidEventStatus
+	}
+
+	if r.Reason == nil {
+		return nil
+	}
+
+	reason := strings.TrimSpace(*r.Reason)
+	if utf8.RuneCountInString(reason) > MaxCancelReasonLen {
+		return errors.New("Причина отмены слишком длинная")
+	}
+
+	if reason == "" {
+		r.Reason = nil
+	} else {
+		r.Reason = &reason
+	}
+
+	return nil
+}
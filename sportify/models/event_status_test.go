@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStatusCanChangeTo(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, EventStatusScheduled.CanChangeTo(EventStatusCancelled))
+	assert.True(t, EventStatusPostponed.CanChangeTo(EventStatusScheduled))
+	assert.False(t, EventStatusScheduled.CanChangeTo(EventStatusScheduled))
+	assert.False(t, EventStatusCancelled.CanChangeTo(EventStatusScheduled))
+	assert.False(t, EventStatusFinished.CanChangeTo(EventStatusCancelled))
+}
+
+func TestRequestEventStatusValid(t *testing.T) {
+	t.Parallel()
+
+	reason := "  дождь  "
+	request := RequestEventStatus{Status: EventStatusCancelled, Reason: &reason}
+	assert.NoError(t, request.Valid())
+	assert.Equal(t, "дождь", *request.Reason)
+
+	empty := " "
+	request = RequestEventStatus{Status: EventStatusPostponed, Reason: &empty}
+	assert.NoError(t, request.Valid())
+	assert.Nil(t, request.Reason)
+
+	request = RequestEventStatus{Status: "deleted", Reason: nil}
+	assert.ErrorIs(t, request.Valid(), ErrInvalidEventStatus)
+}
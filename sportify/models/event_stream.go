@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type EventStreamKind string
+
+const (
+	EventStreamSubscribers EventStreamKind = "subscribers"
+	EventStreamUpdated     EventStreamKind = "updated"
+	EventStreamStatus      EventStreamKind = "status"
+	EventStreamComment     EventStreamKind = "comment"
+	EventStreamDeleted     EventStreamKind = "deleted"
+)
+
+// EventStreamMessage is change of event pushed to open pages of event, Data depends on Kind.
+// It goes through postgres NOTIFY, so it has to be shorter than 8000 bytes.
+type EventStreamMessage struct {
+	EventID uuid.UUID       `json:"event_id"`
+	Kind    EventStreamKind `json:"kind"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// EventStreamSubscribersData has no ids of subscribers, big list doesn't fit into NOTIFY.
+type EventStreamSubscribersData struct {
+	Capacity *int `json:"capacity"`
+	Busy     int  `json:"busy"`
+}
+
+type EventStreamStatusData struct {
+	Status       EventStatus `json:"status"`
+	CancelReason *string     `json:"cancel_reason"`
+}
+
+// EventStreamCommentData has only id of comment, page gets comments again to show it.
+type EventStreamCommentData struct {
+	CommentID uuid.UUID `json:"comment_id"`
+	Action    string    `json:"action"`
+}
+
+const (
+	EventStreamCommentCreated = "created"
+	EventStreamCommentEdited  = "edited"
+	EventStreamCommentDeleted = "deleted"
+)
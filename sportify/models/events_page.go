@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OrderByStartTime  = "start_time"
+	OrderByPrice      = "price"
+	OrderByDistance   = "distance"
+	OrderByFreePlaces = "free_places"
+	OrderByCreatedAt  = "created_at"
+	OrderByRelevance  = "relevance"
+
+	DefaultEventsLimit = 100
+	MaxEventsLimit     = 500
+)
+
+var (
+	ErrInvalidOrderBy = errors.New("сортировка возможна по start_time, price, distance, free_places, created_at, relevance")
+	ErrInvalidLimit   = fmt.Errorf("limit должен быть от 1 до %d", MaxEventsLimit)
+	ErrInvalidCursor  = errors.New("некорректный cursor")
+)
+
+func validOrderBy(orderBy string) bool {
+	switch orderBy {
+	case OrderByStartTime, OrderByPrice, OrderByDistance, OrderByFreePlaces, OrderByCreatedAt, OrderByRelevance:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventsCursor points to the last event of page. It is bound to sort,
+// so cursor from one listing can't be used with another order.
+type EventsCursor struct {
+	OrderBy   string    `json:"o"`
+	SortOrder string    `json:"s"`
+	Value     string    `json:"v"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (c *EventsCursor) Encode() string {
+	rawCursor, _ := json.Marshal(c) //nolint:errchkjson
+
+	return base64.RawURLEncoding.EncodeToString(rawCursor)
+}
+
+func DecodeEventsCursor(cursor string) (*EventsCursor, error) {
+	rawCursor, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var result EventsCursor
+
+	err = json.Unmarshal(rawCursor, &result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if !validOrderBy(result.OrderBy) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &result, nil
+}
+
+type EventsPage struct {
+	Events []ShortEvent
+	// NextCursor is empty on the last page.
+	NextCursor string
+	// Total is counted only on request, it is an extra query.
+	Total *int
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsCursorEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	cursor := &EventsCursor{
+		OrderBy:   OrderByPrice,
+		SortOrder: "desc",
+		Value:     "500",
+		ID:        uuid.MustParse("0f8c4a5e-3f0b-4a5e-9a43-6d1e0f1b2c3d"),
+	}
+
+	decoded, err := DecodeEventsCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	for _, rawCursor := range []string{
+		"not base64!",
+		"bm90IGpzb24",
+		(&EventsCursor{OrderBy: "creator_id", SortOrder: "asc"}).Encode(), //nolint:exhaustruct
+	} {
+		_, err := DecodeEventsCursor(rawCursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, rawCursor)
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	t.Parallel()
+
+	var params FilterParams
+
+	assert.NoError(t, parsePage(url.Values{}, &params))
+	assert.Zero(t, params.Limit, "whole list without limit and cursor")
+	assert.Nil(t, params.Cursor)
+	assert.Equal(t, OrderByStartTime, params.OrderBy)
+	assert.Equal(t, "asc", params.SortOrder)
+
+	params = FilterParams{} //nolint:exhaustruct
+	assert.NoError(t, parsePage(url.Values{"limit": {"20"}, "with_total": {"true"}}, &params))
+	assert.Equal(t, 20, params.Limit)
+	assert.True(t, params.WithTotal)
+
+	cursor := &EventsCursor{OrderBy: OrderByStartTime, SortOrder: "asc", Value: "v", ID: uuid.Nil}
+
+	params = FilterParams{} //nolint:exhaustruct
+	assert.NoError(t, parsePage(url.Values{"cursor": {cursor.Encode()}}, &params))
+	assert.Equal(t, DefaultEventsLimit, params.Limit)
+	assert.Equal(t, cursor, params.Cursor)
+
+	for _, limit := range []string{"0", "-1", "501", "ten"} {
+		params = FilterParams{} //nolint:exhaustruct
+		assert.ErrorIs(t, parsePage(url.Values{"limit": {limit}}, &params), ErrInvalidLimit, limit)
+	}
+
+	params = FilterParams{OrderBy: OrderByPrice} //nolint:exhaustruct
+	assert.ErrorIs(t, parsePage(url.Values{"cursor": {cursor.Encode()}}, &params), ErrInvalidCursor)
+}
@@ -0,0 +1,59 @@
+package models
+
+const (
+	PriceBucketFree     = "free"
+	PriceBucketTo500    = "to_500"
+	PriceBucketTo1000   = "to_1000"
+	PriceBucketFrom1000 = "from_1000"
+
+	// FacetDays is how many upcoming days are counted in date facet.
+	FacetDays = 14
+)
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// EventFacets are counts of events for every value of filter. Each facet is
+// counted without its own filter, so other values can be offered to user.
+type EventFacets struct {
+	SportTypes   []FacetValue `json:"sport_type"`
+	GameLevels   []FacetValue `json:"game_level"`
+	PriceBuckets []FacetValue `json:"price"`
+	Dates        []FacetValue `json:"date"`
+}
+
+// WithoutSportTypes and other Without* copy params without filter of facet.
+func (f *FilterParams) WithoutSportTypes() *FilterParams {
+	result := *f
+	result.SportTypes = nil
+
+	return &result
+}
+
+func (f *FilterParams) WithoutGameLevels() *FilterParams {
+	result := *f
+	result.GameLevels = nil
+	result.LevelRange = nil
+
+	return &result
+}
+
+func (f *FilterParams) WithoutPrice() *FilterParams {
+	result := *f
+	result.PriceMin = nil
+	result.PriceMax = nil
+	result.OnlyFree = false
+
+	return &result
+}
+
+func (f *FilterParams) WithoutDates() *FilterParams {
+	result := *f
+	result.DateStarts = nil
+	result.DateFrom = nil
+	result.DateTo = nil
+
+	return &result
+}
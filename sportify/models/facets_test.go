@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterParamsWithoutFacet(t *testing.T) {
+	t.Parallel()
+
+	priceMin, priceMax := 100, 1000
+	dateFrom := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+	filterParams := &FilterParams{ //nolint:exhaustruct
+		SportTypes: []SportType{SportTypeFootball},
+		GameLevels: []GameLevel{GameLevelLow},
+		LevelRange: []GameLevel{GameLevelLow, GameLevelMidMinus},
+		DateStarts: []string{"2025-03-08"},
+		DateFrom:   &dateFrom,
+		DateTo:     &dateFrom,
+		PriceMin:   &priceMin,
+		PriceMax:   &priceMax,
+		OnlyFree:   true,
+		Query:      "лужники",
+	}
+	original := *filterParams
+
+	withoutSportTypes := filterParams.WithoutSportTypes()
+	assert.Nil(t, withoutSportTypes.SportTypes)
+	assert.Equal(t, filterParams.GameLevels, withoutSportTypes.GameLevels)
+	assert.Equal(t, filterParams.PriceMin, withoutSportTypes.PriceMin)
+
+	withoutGameLevels := filterParams.WithoutGameLevels()
+	assert.Nil(t, withoutGameLevels.GameLevels)
+	assert.Nil(t, withoutGameLevels.LevelRange)
+	assert.Equal(t, filterParams.SportTypes, withoutGameLevels.SportTypes)
+
+	withoutPrice := filterParams.WithoutPrice()
+	assert.Nil(t, withoutPrice.PriceMin)
+	assert.Nil(t, withoutPrice.PriceMax)
+	assert.False(t, withoutPrice.OnlyFree)
+	assert.Equal(t, filterParams.DateStarts, withoutPrice.DateStarts)
+
+	withoutDates := filterParams.WithoutDates()
+	assert.Nil(t, withoutDates.DateStarts)
+	assert.Nil(t, withoutDates.DateFrom)
+	assert.Nil(t, withoutDates.DateTo)
+	assert.Equal(t, filterParams.Query, withoutDates.Query)
+
+	// copies don't change filters of the whole search
+	assert.Equal(t, original, *filterParams)
+}
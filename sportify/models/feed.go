@@ -0,0 +1,275 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+// moscowZone is zone of time of events, they are saved as moscow time marked as UTC.
+var moscowZone = time.FixedZone("MSK", 3*60*60) //nolint:gochecknoglobals
+
+func moscowTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, moscowZone)
+}
+
+type Feed struct {
+	Title       string
+	Description string
+	// Link is page of search on site, FeedURL is url of feed itself.
+	Link    string
+	FeedURL string
+	Updated time.Time
+	Items   []FeedItem
+}
+
+type FeedItem struct {
+	ID          uuid.UUID
+	Title       string
+	Description string
+	Link        string
+	Image       string
+	// Date is start of event, aggregators show items in order of it.
+	Date time.Time
+}
+
+func NewFeedItem(event *ShortEvent, link string) FeedItem {
+	sportType, ok := EnToRuSportType(event.SportType)
+	if !ok {
+		sportType = string(event.SportType)
+	}
+
+	description := []string{
+		"Когда: " + formatEventTime(&event.DateAndTime),
+		"Где: " + event.Address,
+		"Цена: " + formatEventPrice(event.Price),
+	}
+
+	if event.Capacity != nil {
+		description = append(description, fmt.Sprintf("Свободных мест: %d", max(*event.Capacity-event.Busy, 0)))
+	}
+
+	return FeedItem{
+		ID:          event.ID,
+		Title:       fmt.Sprintf("%s %s", sportType, event.DateAndTime.StartTime.Format("02.01 15:04")),
+		Description: strings.Join(description, "\n"),
+		Link:        link,
+		Image:       event.URLPreview,
+		Date:        moscowTime(event.DateAndTime.StartTime),
+	}
+}
+
+func imageType(url string) string {
+	result := mime.TypeByExtension(path.Ext(url))
+	if result == "" {
+		return "image/jpeg"
+	}
+
+	return result
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	GUID        string        `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	Language      string      `xml:"language"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	AtomLink      rssAtomLink `xml:"atom:link"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rss struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	XMLNSAtom string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+func FormatRSS(feed *Feed) ([]byte, error) {
+	items := make([]rssItem, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		var enclosure *rssEnclosure
+		if item.Image != "" {
+			// size of image is unknown, zero is allowed by readers
+			enclosure = &rssEnclosure{URL: item.Image, Type: imageType(item.Image), Length: 0}
+		}
+
+		items = append(items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			GUID:        item.Link,
+			PubDate:     item.Date.Format(time.RFC1123Z),
+			Enclosure:   enclosure,
+		})
+	}
+
+	return marshalFeedXML(rss{
+		XMLName:   xml.Name{Space: "", Local: "rss"},
+		Version:   "2.0",
+		XMLNSAtom: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			Language:      "ru",
+			LastBuildDate: feed.Updated.Format(time.RFC1123Z),
+			AtomLink:      rssAtomLink{Href: feed.FeedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:         items,
+		},
+	})
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Updated string     `xml:"updated"`
+	Links   []atomLink `xml:"link"`
+	Summary atomText   `xml:"summary"`
+}
+
+type atom struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+func FormatAtom(feed *Feed) ([]byte, error) {
+	entries := make([]atomEntry, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		links := []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}}
+		if item.Image != "" {
+			links = append(links, atomLink{Href: item.Image, Rel: "enclosure", Type: imageType(item.Image)})
+		}
+
+		entries = append(entries, atomEntry{
+			Title:   item.Title,
+			ID:      "urn:uuid:" + item.ID.String(),
+			Updated: item.Date.Format(time.RFC3339),
+			Links:   links,
+			Summary: atomText{Type: "text", Text: item.Description},
+		})
+	}
+
+	return marshalFeedXML(atom{
+		XMLName:  xml.Name{Space: "http://www.w3.org/2005/Atom", Local: "feed"},
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.FeedURL,
+		Updated:  feed.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feed.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: entries,
+	})
+}
+
+func marshalFeedXML(feed any) ([]byte, error) {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("to marshal feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	Image         string `json:"image,omitempty"`
+	DatePublished string `json:"date_published"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+// FormatJSONFeed makes feed by JSON Feed 1.1.
+func FormatJSONFeed(feed *Feed) ([]byte, error) {
+	items := make([]jsonFeedItem, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		items = append(items, jsonFeedItem{
+			ID:            item.ID.String(),
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentText:   item.Description,
+			Image:         item.Image,
+			DatePublished: item.Date.Format(time.RFC3339),
+		})
+	}
+
+	body, err := json.Marshal(jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		Description: feed.Description,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Language:    "ru",
+		Items:       items,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to marshal feed: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFeed() *Feed {
+	start := time.Date(2025, 3, 8, 19, 0, 0, 0, time.UTC)
+	price, capacity := 500, 10
+
+	item := NewFeedItem(&ShortEvent{ //nolint:exhaustruct
+		ID:          uuid.MustParse("6f1d7c3e-2b1a-4a55-9a0e-0c3c2b1a4a55"),
+		SportType:   SportTypeVolleyball,
+		Address:     "Москва, Лужники",
+		DateAndTime: DateAndTime{Date: start, StartTime: start, EndTime: nil},
+		Price:       &price,
+		Capacity:    &capacity,
+		Busy:        4,
+		URLPreview:  "https://example.com/img/default_volleyball.jpg",
+	}, "https://example.com/events/6f1d7c3e-2b1a-4a55-9a0e-0c3c2b1a4a55")
+
+	return &Feed{
+		Title:       "Ближайшие игры",
+		Description: "Игры & турниры",
+		Link:        "https://example.com/events",
+		FeedURL:     "https://example.com/api/v1/events/feed.rss",
+		Updated:     start,
+		Items:       []FeedItem{item},
+	}
+}
+
+func TestNewFeedItem(t *testing.T) {
+	t.Parallel()
+
+	item := testFeed().Items[0]
+
+	assert.Equal(t, "волейбол 08.03 19:00", item.Title)
+	assert.Contains(t, item.Description, "Москва, Лужники")
+	assert.Contains(t, item.Description, "Свободных мест: 6")
+	assert.Equal(t, "2025-03-08T19:00:00+03:00", item.Date.Format(time.RFC3339))
+}
+
+func TestFormatFeeds(t *testing.T) {
+	t.Parallel()
+
+	feed := testFeed()
+
+	rssBody, err := FormatRSS(feed)
+	assert.NoError(t, err)
+	assert.Contains(t, string(rssBody), `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, string(rssBody), `<pubDate>Sat, 08 Mar 2025 19:00:00 +0300</pubDate>`)
+	assert.Contains(t, string(rssBody), `<enclosure url="https://example.com/img/default_volleyball.jpg" type="image/jpeg" length="0">`)
+	assert.Contains(t, string(rssBody), `<description>Игры &amp; турниры</description>`)
+	assert.NoError(t, xml.Unmarshal(rssBody, new(any)))
+
+	atomBody, err := FormatAtom(feed)
+	assert.NoError(t, err)
+	assert.Contains(t, string(atomBody), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, string(atomBody), `<id>urn:uuid:6f1d7c3e-2b1a-4a55-9a0e-0c3c2b1a4a55</id>`)
+	assert.Contains(t, string(atomBody), `<updated>2025-03-08T19:00:00+03:00</updated>`)
+
+	jsonBody, err := FormatJSONFeed(feed)
+	assert.NoError(t, err)
+
+	var decoded map[string]any
+	assert.NoError(t, json.Unmarshal(jsonBody, &decoded))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", decoded["version"])
+	assert.Len(t, decoded["items"], 1)
+}
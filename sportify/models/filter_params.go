@@ -1,9 +1,12 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
 
@@ -19,20 +22,53 @@ type FilterParams struct {
 	PriceMin   *int
 	PriceMax   *int
 	FreePlaces *int
-	Address    string
-	OrderBy    string
-	SortOrder  string
+	// DateFrom and DateTo are inclusive bounds of date_start.
+	DateFrom *time.Time
+	DateTo   *time.Time
+	// Weekdays are ISO numbers, Monday is 1 and Sunday is 7.
+	Weekdays []int
+	// TimeFrom and TimeTo bound start time of day, format is 15:04.
+	TimeFrom      string
+	TimeTo        string
+	OnlyFree      bool
+	HasFreePlaces bool
+	CreationType  *CreationType
+	// LevelRange is all levels between level_min and level_max,
+	// events without level fit any range.
+	LevelRange    []GameLevel
+	ExcludeJoined bool
+	ClubID        *uuid.UUID
+	VenueID       *uuid.UUID
+	Address       string
+	// Query is full text search over description, address, telegram message and venue name.
+	Query string
+	// Latitude, Longitude and RadiusM is search around point,
+	// RadiusM is DefaultSearchRadiusM if not set.
+	Latitude  *float64
+	Longitude *float64
+	RadiusM   *float64
+	BBox      *BBox
+	OrderBy   string
+	SortOrder string
+	// Limit is page size, zero returns whole list. Cursor is position after previous page.
+	Limit     int
+	Cursor    *EventsCursor
+	WithTotal bool
 
 	// Block inner usage
 
 	CreatorID     *uuid.UUID
 	SubscriberIDs []uuid.UUID
+	// ExcludeSubscriberID hides events of user, it is set from token when ExcludeJoined.
+	ExcludeSubscriberID *uuid.UUID
+	// EventID checks if one event matches filters, it is used by saved searches.
+	EventID *uuid.UUID
+	// WithCancelled shows cancelled events, they are hidden everywhere except lists of user.
+	WithCancelled bool
 
 	// DateExpression is representation of WHERE statement
 	// you can use squirrel.Eq and another with similar sense
 	DateExpression any
-
-	AddressLatitude, AddressLongitude *string
 }
 
 //nolint:cyclop
@@ -49,6 +85,7 @@ func ParseFilterParams(query url.Values) (*FilterParams, error) {
 			}, query["game_level"]),
 		DateStarts: query["date_start"],
 		Address:    query.Get("address"),
+		Query:      strings.TrimSpace(query.Get("q")),
 		OrderBy:    query.Get("order_by"),
 		SortOrder:  query.Get("sort_order"),
 	}
@@ -77,15 +114,281 @@ func ParseFilterParams(query url.Values) (*FilterParams, error) {
 		params.FreePlaces = &freePlaces
 	}
 
+	if clubIDStr := query.Get("club_id"); clubIDStr != "" {
+		clubID, err := uuid.Parse(clubIDStr)
+		if err != nil {
+			return nil, err
+		}
+		params.ClubID = &clubID
+	}
+
+	if venueIDStr := query.Get("venue_id"); venueIDStr != "" {
+		venueID, err := uuid.Parse(venueIDStr)
+		if err != nil {
+			return nil, err
+		}
+		params.VenueID = &venueID
+	}
+
+	if bboxStr := query.Get("bbox"); bboxStr != "" {
+		bbox, err := ParseBBox(bboxStr)
+		if err != nil {
+			return nil, err
+		}
+		params.BBox = bbox
+	}
+
+	err := parseRadiusSearch(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseSchedule(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseEventKind(query, params)
+	if err != nil {
+		return nil, err
+	}
+
 	params.Address = strings.TrimSpace(params.Address)
 
-	if params.OrderBy == "" {
-		params.OrderBy = "start_time"
+	err = parsePage(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func parseOrder(params *FilterParams) error {
+	// found events are sorted by relevance unless other order is asked
+	if params.OrderBy == "" && params.Query != "" {
+		params.OrderBy = OrderByRelevance
+	}
+
+	if params.OrderBy == "" || (params.OrderBy == OrderByRelevance && params.Query == "") {
+		params.OrderBy = OrderByStartTime
+	}
+
+	if !validOrderBy(params.OrderBy) {
+		return ErrInvalidOrderBy
 	}
 
-	if params.SortOrder == "" || (params.SortOrder != "asc" && params.SortOrder != "desc") {
+	if params.OrderBy == OrderByDistance && params.Latitude == nil && params.Address == "" {
+		return fmt.Errorf("%w: для distance нужны lat и lon или address", ErrInvalidOrderBy)
+	}
+
+	if params.SortOrder != "asc" && params.SortOrder != "desc" {
 		params.SortOrder = "asc"
+
+		// the most relevant events go first by default
+		if params.OrderBy == OrderByRelevance {
+			params.SortOrder = "desc"
+		}
 	}
 
-	return params, nil
+	return nil
+}
+
+func parsePage(query url.Values, params *FilterParams) error {
+	err := parseOrder(params)
+	if err != nil {
+		return err
+	}
+
+	// without limit and cursor whole list is returned as before, old clients don't read page headers
+	if query.Has("cursor") {
+		params.Limit = DefaultEventsLimit
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		params.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || params.Limit < 1 || params.Limit > MaxEventsLimit {
+			return ErrInvalidLimit
+		}
+	}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		params.Cursor, err = DecodeEventsCursor(cursorStr)
+		if err != nil {
+			return err
+		}
+
+		if params.Cursor.OrderBy != params.OrderBy || params.Cursor.SortOrder != params.SortOrder {
+			return fmt.Errorf("%w: cursor получен для другой сортировки", ErrInvalidCursor)
+		}
+	}
+
+	params.WithTotal = query.Get("with_total") == "true"
+
+	return nil
+}
+
+var ErrInvalidFilter = errors.New("некорректный фильтр")
+
+var weekdays = map[string]int{ //nolint:gochecknoglobals
+	"mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6, "sun": 7,
+}
+
+func parseWeekday(raw string) (int, error) {
+	if weekday, ok := weekdays[strings.ToLower(raw)]; ok {
+		return weekday, nil
+	}
+
+	weekday, err := strconv.Atoi(raw)
+	if err != nil || weekday < 1 || weekday > 7 {
+		return 0, fmt.Errorf("%w: weekday %q, ожидается 1-7 или mon-sun", ErrInvalidFilter, raw)
+	}
+
+	return weekday, nil
+}
+
+func parseOptionalDate(query url.Values, name string) (*time.Time, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	// dates of events are stored as UTC midnight
+	date, err := time.ParseInLocation(time.DateOnly, raw, time.UTC)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s должен быть в формате 2006-01-02", ErrInvalidFilter, name)
+	}
+
+	return &date, nil
+}
+
+func parseOptionalClock(query url.Values, name string) (string, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return "", nil
+	}
+
+	clock, err := time.Parse("15:04", raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s должен быть в формате 15:04", ErrInvalidFilter, name)
+	}
+
+	return clock.Format("15:04"), nil
+}
+
+func parseSchedule(query url.Values, params *FilterParams) error {
+	var err error
+
+	params.DateFrom, err = parseOptionalDate(query, "date_from")
+	if err != nil {
+		return err
+	}
+
+	params.DateTo, err = parseOptionalDate(query, "date_to")
+	if err != nil {
+		return err
+	}
+
+	if params.DateFrom != nil && params.DateTo != nil && params.DateFrom.After(*params.DateTo) {
+		return fmt.Errorf("%w: date_from позже date_to", ErrInvalidFilter)
+	}
+
+	for _, raw := range query["weekday"] {
+		weekday, err := parseWeekday(raw)
+		if err != nil {
+			return err
+		}
+
+		params.Weekdays = append(params.Weekdays, weekday)
+	}
+
+	params.TimeFrom, err = parseOptionalClock(query, "time_from")
+	if err != nil {
+		return err
+	}
+
+	params.TimeTo, err = parseOptionalClock(query, "time_to")
+	if err != nil {
+		return err
+	}
+
+	if params.TimeFrom != "" && params.TimeTo != "" && params.TimeFrom > params.TimeTo {
+		return fmt.Errorf("%w: time_from позже time_to", ErrInvalidFilter)
+	}
+
+	return nil
+}
+
+func parseEventKind(query url.Values, params *FilterParams) error {
+	params.OnlyFree = query.Get("only_free") == "true"
+	params.HasFreePlaces = query.Get("has_free_places") == "true"
+	params.ExcludeJoined = query.Get("exclude_joined") == "true"
+
+	if creationTypeStr := query.Get("creator_type"); creationTypeStr != "" {
+		creationType := CreationType(creationTypeStr)
+		if creationType != CreationTypeTg && creationType != CreationTypeSite {
+			return fmt.Errorf("%w: creator_type может быть tg или site", ErrInvalidFilter)
+		}
+
+		params.CreationType = &creationType
+	}
+
+	levelMin, levelMax := GameLevel(query.Get("level_min")), GameLevel(query.Get("level_max"))
+	if levelMin != "" || levelMax != "" {
+		levelRange, ok := GameLevelsBetween(levelMin, levelMax)
+		if !ok {
+			return fmt.Errorf("%w: неизвестный уровень или level_min выше level_max", ErrInvalidFilter)
+		}
+
+		params.LevelRange = levelRange
+	}
+
+	return nil
+}
+
+func parseOptionalFloat(query url.Values, name string) (*float64, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &value, nil
+}
+
+func parseRadiusSearch(query url.Values, params *FilterParams) error {
+	var err error
+
+	params.Latitude, err = parseOptionalFloat(query, "lat")
+	if err != nil {
+		return err
+	}
+
+	params.Longitude, err = parseOptionalFloat(query, "lon")
+	if err != nil {
+		return err
+	}
+
+	params.RadiusM, err = parseOptionalFloat(query, "radius_m")
+	if err != nil {
+		return err
+	}
+
+	if (params.Latitude == nil) != (params.Longitude == nil) || (params.RadiusM != nil && params.Latitude == nil) {
+		return ErrInvalidRadiusSearch
+	}
+
+	if params.Latitude != nil && (*params.Latitude < -90 || *params.Latitude > 90 ||
+		*params.Longitude < -180 || *params.Longitude > 180) {
+		return fmt.Errorf("%w: координаты вне диапазона", ErrInvalidRadiusSearch)
+	}
+
+	if params.RadiusM != nil && (*params.RadiusM <= 0 || *params.RadiusM > MaxSearchRadiusM) {
+		return fmt.Errorf("%w: radius_m должен быть от 0 до %.0f", ErrInvalidRadiusSearch, MaxSearchRadiusM)
+	}
+
+	return nil
 }
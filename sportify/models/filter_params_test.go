@@ -0,0 +1,59 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameLevelsBetween(t *testing.T) {
+	t.Parallel()
+
+	levels, ok := GameLevelsBetween(GameLevelMidMinus, GameLevelMidPlus)
+	assert.True(t, ok)
+	assert.Equal(t, []GameLevel{GameLevelMidMinus, GameLevelMid, GameLevelMidPlus}, levels)
+
+	levels, ok = GameLevelsBetween("", GameLevelLowPlus)
+	assert.True(t, ok)
+	assert.Equal(t, []GameLevel{GameLevelLow, GameLevelLowPlus}, levels)
+
+	// caller may change its range, order of levels stays the same
+	levels = append(levels, GameLevelHighPlus)
+	levels[0] = GameLevelHigh
+	levels, _ = GameLevelsBetween("", GameLevelMidMinus)
+	assert.Equal(t, []GameLevel{GameLevelLow, GameLevelLowPlus, GameLevelMidMinus}, levels)
+
+	_, ok = GameLevelsBetween(GameLevelHigh, GameLevelLow)
+	assert.False(t, ok)
+
+	_, ok = GameLevelsBetween("unknown", "")
+	assert.False(t, ok)
+}
+
+func TestParseFilterParamsSchedule(t *testing.T) {
+	t.Parallel()
+
+	params, err := ParseFilterParams(url.Values{
+		"date_from": {"2025-03-01"},
+		"date_to":   {"2025-03-31"},
+		"weekday":   {"sat", "7"},
+		"time_from": {"19:00"},
+		"level_min": {"mid"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6, 7}, params.Weekdays)
+	assert.Equal(t, "19:00", params.TimeFrom)
+	assert.Equal(t, []GameLevel{GameLevelMid, GameLevelMidPlus, GameLevelHigh, GameLevelHighPlus}, params.LevelRange)
+
+	for _, query := range []url.Values{
+		{"date_from": {"2025-03-31"}, "date_to": {"2025-03-01"}},
+		{"weekday": {"8"}},
+		{"time_from": {"7pm"}},
+		{"creator_type": {"bot"}},
+		{"level_min": {"high"}, "level_max": {"low"}},
+	} {
+		_, err := ParseFilterParams(query)
+		assert.ErrorIs(t, err, ErrInvalidFilter, query)
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 const (
 	GameLevelLow      GameLevel = "low"
 	GameLevelLowPlus  GameLevel = "low_plus"
@@ -41,3 +43,52 @@ func RuToEnGameLevel(level string) (GameLevel, bool) {
 }
 
 type GameLevel string
+
+// gameLevelsOrder lists levels from the lowest to the highest.
+var gameLevelsOrder = []GameLevel{ //nolint:gochecknoglobals
+	GameLevelLow,
+	GameLevelLowPlus,
+	GameLevelMidMinus,
+	GameLevelMid,
+	GameLevelMidPlus,
+	GameLevelHigh,
+	GameLevelHighPlus,
+}
+
+func gameLevelIndex(level GameLevel) int {
+	for i, cur := range gameLevelsOrder {
+		if cur == level {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// GameLevelRank is position of level from the lowest one, false for unknown level.
+func GameLevelRank(level GameLevel) (int, bool) {
+	index := gameLevelIndex(level)
+
+	return index, index >= 0
+}
+
+// GameLevelsBetween returns all levels from levelMin to levelMax inclusive,
+// empty bound means the lowest or the highest level.
+func GameLevelsBetween(levelMin, levelMax GameLevel) ([]GameLevel, bool) {
+	indexMin, indexMax := 0, len(gameLevelsOrder)-1
+
+	if levelMin != "" {
+		indexMin = gameLevelIndex(levelMin)
+	}
+
+	if levelMax != "" {
+		indexMax = gameLevelIndex(levelMax)
+	}
+
+	if indexMin < 0 || indexMax < 0 || indexMin > indexMax {
+		return nil, false
+	}
+
+	// copy is returned, filters of request must not share memory with the global order
+	return slices.Clone(gameLevelsOrder[indexMin : indexMax+1]), true
+}
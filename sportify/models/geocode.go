@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type GeocodeCacheEntry struct {
+	Address string
+	// Latitude and Longitude are nil when providers didn't find address.
+	Latitude  *string
+	Longitude *string
+	Details   *AddressDetails
+	Provider  string
+	ExpiresAt time.Time
+}
+
+func (e *GeocodeCacheEntry) IsNotFound() bool {
+	return e.Latitude == nil || e.Longitude == nil
+}
+
+// AddressDetails is canonical address returned by geocoder.
+type AddressDetails struct {
+	Formatted string `json:"formatted"`
+	City      string `json:"city,omitempty"`
+	Street    string `json:"street,omitempty"`
+	House     string `json:"house,omitempty"`
+	Metro     string `json:"metro,omitempty"`
+}
+
+func NewAddressDetails(city, street, house, metro string) *AddressDetails {
+	parts := make([]string, 0, 3)
+
+	for _, part := range []string{city, street, house} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return &AddressDetails{
+		Formatted: strings.Join(parts, ", "),
+		City:      city,
+		Street:    street,
+		House:     house,
+		Metro:     metro,
+	}
+}
+
+type ResponseReverseGeocode struct {
+	Latitude  string          `json:"latitude"`
+	Longitude string          `json:"longitude"`
+	Address   *AddressDetails `json:"address"`
+}
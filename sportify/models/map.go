@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidBBox         = errors.New("bbox должен быть в формате minLon,minLat,maxLon,maxLat")
+	ErrInvalidRadiusSearch = errors.New("для поиска по радиусу нужны lat и lon")
+)
+
+const (
+	DefaultSearchRadiusM = 5000.0
+	MaxSearchRadiusM     = 100000.0
+)
+
+// BBox is visible area of map.
+type BBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+func ParseBBox(raw string) (*BBox, error) {
+	rawValues := strings.Split(raw, ",")
+	if len(rawValues) != 4 { //nolint:mnd
+		return nil, ErrInvalidBBox
+	}
+
+	values := make([]float64, len(rawValues))
+
+	for i, rawValue := range rawValues {
+		value, err := strconv.ParseFloat(strings.TrimSpace(rawValue), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBBox, err)
+		}
+
+		values[i] = value
+	}
+
+	bbox := &BBox{MinLongitude: values[0], MinLatitude: values[1], MaxLongitude: values[2], MaxLatitude: values[3]}
+
+	if bbox.MinLongitude > bbox.MaxLongitude || bbox.MinLatitude > bbox.MaxLatitude ||
+		bbox.MinLongitude < -180 || bbox.MaxLongitude > 180 || bbox.MinLatitude < -90 || bbox.MaxLatitude > 90 {
+		return nil, ErrInvalidBBox
+	}
+
+	return bbox, nil
+}
+
+type MapCluster struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Count     int     `json:"count"`
+}
+
+// MapEvents contains either events or clusters, clusters are returned
+// when there are too many events in visible area.
+type MapEvents struct {
+	Events   []ShortEvent `json:"events"`
+	Clusters []MapCluster `json:"clusters"`
+}
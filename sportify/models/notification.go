@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind is the reason of notification. There is no kind for waitlist:
+// events have no waitlist, organizer learns that event is full by NotificationEventFull.
+type NotificationKind string
+
+const (
+	NotificationEventUpdated      NotificationKind = "event_updated"
+	NotificationEventDeleted      NotificationKind = "event_deleted"
+	NotificationEventCancelled    NotificationKind = "event_cancelled"
+	NotificationParticipantJoined NotificationKind = "participant_joined"
+	NotificationParticipantLeft   NotificationKind = "participant_left"
+	NotificationEventFull         NotificationKind = "event_full"
+	NotificationPaymentStatus     NotificationKind = "payment_status"
+	NotificationSavedSearch       NotificationKind = "saved_search"
+	NotificationEventReminder     NotificationKind = "event_reminder"
+	NotificationEventComment      NotificationKind = "event_comment"
+	NotificationCommentReply      NotificationKind = "comment_reply"
+	NotificationCommentMention    NotificationKind = "comment_mention"
+)
+
+var notificationKinds = []NotificationKind{ //nolint:gochecknoglobals
+	NotificationEventUpdated,
+	NotificationEventDeleted,
+	NotificationEventCancelled,
+	NotificationParticipantJoined,
+	NotificationParticipantLeft,
+	NotificationEventFull,
+	NotificationPaymentStatus,
+	NotificationSavedSearch,
+	NotificationEventReminder,
+	NotificationEventComment,
+	NotificationCommentReply,
+	NotificationCommentMention,
+}
+
+func NotificationKinds() []NotificationKind {
+	return notificationKinds
+}
+
+func (k NotificationKind) Valid() bool {
+	for _, kind := range notificationKinds {
+		if kind == k {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	DefaultNotificationsLimit = 50
+	MaxNotificationsLimit     = 200
+)
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    uuid.UUID        `json:"-"`
+	Kind      NotificationKind `json:"kind"`
+	EventID   *uuid.UUID       `json:"event_id"`
+	Text      string           `json:"text"`
+	ReadAt    *time.Time       `json:"read_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type ResponseNotifications struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unread_count"`
+}
+
+// RequestNotificationsRead marks listed notifications as read, all of them if IDs is empty.
+type RequestNotificationsRead struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type NotificationPreference struct {
+	Kind     NotificationKind `json:"kind"`
+	InApp    bool             `json:"in_app"`
+	Telegram bool             `json:"telegram"`
+}
+
+func DefaultNotificationPreference(kind NotificationKind) NotificationPreference {
+	return NotificationPreference{Kind: kind, InApp: true, Telegram: true}
+}
+
+func ValidNotificationPreferences(preferences []NotificationPreference) error {
+	for _, preference := range preferences {
+		if !preference.Kind.Valid() {
+			return fmt.Errorf("неизвестный тип уведомлений %q", preference.Kind)
+		}
+	}
+
+	return nil
+}
@@ -8,6 +8,9 @@ const (
 	PaymentStatusPaid      = "paid"
 	PaymentStatusCancelled = "cancelled"
 	PaymentStatusPending   = "pending"
+	// PaymentStatusRefundPending is set when event is cancelled and money has to be returned.
+	PaymentStatusRefundPending = "refund_pending"
+	PaymentStatusRefunded      = "refunded"
 )
 
 type Payment struct {
@@ -0,0 +1,48 @@
+package models
+
+import "github.com/google/uuid"
+
+// Reasons of recommendation, frontend shows them as tags.
+const (
+	ReasonSportType     = "sport_type"
+	ReasonLevel         = "level"
+	ReasonNearby        = "nearby"
+	ReasonUsualWeekday  = "usual_weekday"
+	ReasonUsualTime     = "usual_time"
+	ReasonFamiliarVenue = "familiar_venue"
+	ReasonFriends       = "friends"
+	ReasonFreePlaces    = "free_places"
+)
+
+// RecommendationWeights is contribution of every signal to score, each signal is from 0 to 1.
+type RecommendationWeights struct {
+	SportType  float64
+	Level      float64
+	Distance   float64
+	Weekday    float64
+	TimeOfDay  float64
+	Venue      float64
+	Friends    float64
+	FreePlaces float64
+}
+
+// RecommendationProfile is what we know about habits of user.
+type RecommendationProfile struct {
+	SportTypes map[SportType]bool
+	UsualLevel *GameLevel
+	// HomeLatitude and HomeLongitude are set from request or center of attended events.
+	HomeLatitude  *float64
+	HomeLongitude *float64
+	// Weekdays and Hours count attended events by ISO weekday and start hour.
+	Weekdays      map[int]int
+	Hours         map[int]int
+	AttendedCount int
+	Venues        map[uuid.UUID]bool
+	Friends       map[uuid.UUID]bool
+}
+
+type RecommendedEvent struct {
+	ShortEvent
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
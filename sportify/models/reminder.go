@@ -0,0 +1,32 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultReminderHours = 3
+	MaxReminderHours     = 72
+)
+
+type EventReminder struct {
+	EventID   uuid.UUID
+	UserID    uuid.UUID
+	StartTime time.Time
+}
+
+// RequestReminder sets hours before start, null returns default and 0 turns reminders off.
+type RequestReminder struct {
+	HoursBefore *int `json:"hours_before"`
+}
+
+func (r *RequestReminder) Valid() error {
+	if r.HoursBefore != nil && (*r.HoursBefore < 0 || *r.HoursBefore > MaxReminderHours) {
+		return fmt.Errorf("напоминание можно поставить за 0-%d часов", MaxReminderHours)
+	}
+
+	return nil
+}
@@ -14,11 +14,6 @@ import (
 	"github.com/google/uuid"
 )
 
-type ResponseOpenMapCoordinates struct {
-	Latitude  string `json:"lat"`
-	Longitude string `json:"lon"`
-}
-
 type ResponsesPayment struct {
 	PaymentStatus PaymentStatus `json:"payment_status"`
 }
@@ -45,6 +40,7 @@ type RequestEventEditSite struct {
 }
 
 type EventEditSite struct {
+	VenueID     *uuid.UUID   `json:"venue_id"`
 	SportType   *SportType   `json:"sport_type"`
 	Address     *string      `json:"address"`
 	DateAndTime *DateAndTime `json:"date_time"`
@@ -57,6 +53,8 @@ type EventEditSite struct {
 }
 
 type EventCreateSite struct {
+	ClubID      *uuid.UUID  `json:"club_id"`
+	VenueID     *uuid.UUID  `json:"venue_id"`
 	SportType   SportType   `json:"sport_type"`
 	Address     string      `json:"address"`
 	DateAndTime DateAndTime `json:"date_time"`
@@ -120,8 +118,29 @@ func NewResponseEventDelete() ResponseEventDelete {
 	return ResponseEventDelete{Status: "ok"}
 }
 
+type ResponseOK struct {
+	Status string `json:"status"`
+}
+
+func NewResponseOK() ResponseOK {
+	return ResponseOK{Status: "ok"}
+}
+
+// ImageVariants are urls of sizes of uploaded image, thumbnail is for preview of event.
+// Fields with WebP suffix are the same sizes in WebP, they are empty if WebP is not smaller.
+type ImageVariants struct {
+	Thumbnail     string `json:"thumbnail"`
+	Medium        string `json:"medium"`
+	Full          string `json:"full"`
+	ThumbnailWebP string `json:"thumbnail_webp"`
+	MediumWebP    string `json:"medium_webp"`
+	FullWebP      string `json:"full_webp"`
+}
+
+// ResponseUploadFile has URL of full variant for old clients.
 type ResponseUploadFile struct {
-	URL string `json:"url"`
+	URL      string        `json:"url"`
+	Variants ImageVariants `json:"variants"`
 }
 
 type ResponseSubscribeEvent struct {
@@ -222,6 +241,14 @@ func NewResponseBadRequestErr(name, message string) ResponseErr {
 	}
 }
 
+func NewResponseUnauthorizedErr(name, message string) ResponseErr {
+	return ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		ErrName:    name,
+		ErrMessage: message,
+	}
+}
+
 func NewResponseForbiddenErr(name, message string) ResponseErr {
 	return ResponseErr{
 		StatusCode: http.StatusForbidden,
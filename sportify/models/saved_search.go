@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultAlertIntervalMinutes = 60
+	// MaxAlertIntervalMinutes is one week.
+	MaxAlertIntervalMinutes = 7 * 24 * 60
+)
+
+// savedSearchPageParams are not part of search, they are dropped on save.
+var savedSearchPageParams = []string{"limit", "cursor", "with_total", "order_by", "sort_order"} //nolint:gochecknoglobals
+
+type SavedSearch struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	// Params are query params of GET /events.
+	Params               url.Values `json:"params"`
+	AlertsEnabled        bool       `json:"alerts_enabled"`
+	AlertIntervalMinutes int        `json:"alert_interval_minutes"`
+	LastAlertAt          *time.Time `json:"last_alert_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+func (s *SavedSearch) FilterParams() (*FilterParams, error) {
+	return ParseFilterParams(s.Params)
+}
+
+// SavedSearchFilter is parsed search ready to match new event.
+type SavedSearchFilter struct {
+	ID           uuid.UUID
+	FilterParams *FilterParams
+}
+
+type RequestSavedSearchCreate struct {
+	Name                 string     `json:"name"`
+	Params               url.Values `json:"params"`
+	AlertsEnabled        *bool      `json:"alerts_enabled"`
+	AlertIntervalMinutes *int       `json:"alert_interval_minutes"`
+}
+
+func (r *RequestSavedSearchCreate) Valid() error {
+	r.Name = strings.TrimSpace(r.Name)
+
+	if r.Name == "" {
+		return fmt.Errorf("название поиска не может быть пустым")
+	}
+
+	if utf8.RuneCountInString(r.Name) > 256 {
+		return fmt.Errorf("название поиска должно быть короче 256 символов")
+	}
+
+	if r.Params == nil {
+		r.Params = url.Values{}
+	}
+
+	for _, param := range savedSearchPageParams {
+		r.Params.Del(param)
+	}
+
+	_, err := ParseFilterParams(r.Params)
+	if err != nil {
+		return fmt.Errorf("некорректные фильтры поиска: %w", err)
+	}
+
+	if r.AlertIntervalMinutes != nil && (*r.AlertIntervalMinutes < 0 || *r.AlertIntervalMinutes > MaxAlertIntervalMinutes) {
+		return fmt.Errorf("интервал уведомлений должен быть от 0 до %d минут", MaxAlertIntervalMinutes)
+	}
+
+	return nil
+}
+
+type SavedSearchAlert struct {
+	SavedSearchID uuid.UUID `json:"saved_search_id"`
+	SearchName    string    `json:"search_name"`
+	EventID       uuid.UUID `json:"event_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestSavedSearchCreateValid(t *testing.T) {
+	t.Parallel()
+
+	request := RequestSavedSearchCreate{ //nolint:exhaustruct
+		Name:   " волейбол вечером ",
+		Params: url.Values{"sport_type": {"volleyball"}, "time_from": {"19:00"}, "cursor": {"abc"}, "limit": {"5"}},
+	}
+
+	assert.NoError(t, request.Valid())
+	assert.Equal(t, "волейбол вечером", request.Name)
+	assert.Equal(t, url.Values{"sport_type": {"volleyball"}, "time_from": {"19:00"}}, request.Params)
+
+	request = RequestSavedSearchCreate{Name: "плохой", Params: url.Values{"weekday": {"holiday"}}} //nolint:exhaustruct
+	assert.Error(t, request.Valid())
+
+	interval := -1
+	request = RequestSavedSearchCreate{Name: "интервал", AlertIntervalMinutes: &interval} //nolint:exhaustruct
+	assert.Error(t, request.Valid())
+}
+
+func TestRequestSavedSearchCreateValidNameLen(t *testing.T) {
+	t.Parallel()
+
+	request := RequestSavedSearchCreate{Name: strings.Repeat("ф", 256)} //nolint:exhaustruct
+	assert.NoError(t, request.Valid())
+
+	request = RequestSavedSearchCreate{Name: strings.Repeat("ф", 257)} //nolint:exhaustruct
+	assert.Error(t, request.Valid())
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventShare is text of preview of event in messengers and of its share card.
+type EventShare struct {
+	Title      string
+	DateTime   string
+	Address    string
+	Price      string
+	FreePlaces string
+}
+
+func NewEventShare(event *ShortEvent) EventShare {
+	freePlaces := "Без ограничения мест"
+	if event.Capacity != nil {
+		freePlaces = fmt.Sprintf("Свободных мест: %d", max(*event.Capacity-event.Busy, 0))
+	}
+
+	return EventShare{
+		Title:      sportTypeTitle(event.SportType),
+		DateTime:   formatEventTime(&event.DateAndTime),
+		Address:    event.Address,
+		Price:      formatEventPrice(event.Price),
+		FreePlaces: freePlaces,
+	}
+}
+
+func (s *EventShare) Description() string {
+	return strings.Join([]string{s.DateTime, s.Address, s.Price, s.FreePlaces}, " · ")
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"html"
+	"strings"
+)
+
+// Full text search marks found words with control characters, user text can't be mixed with html tags in db.
+const (
+	SnippetStartSel = "\x02"
+	SnippetStopSel  = "\x03"
+)
+
+// SnippetHTML escapes snippet of full text search and turns marks of found words into <mark> tags.
+// Marks from user text itself can't open tag twice or leave it open.
+func SnippetHTML(rawSnippet string) string {
+	var (
+		result strings.Builder
+		opened bool
+	)
+
+	for rawSnippet != "" {
+		i := strings.IndexAny(rawSnippet, SnippetStartSel+SnippetStopSel)
+		if i == -1 {
+			result.WriteString(html.EscapeString(rawSnippet))
+			break
+		}
+
+		result.WriteString(html.EscapeString(rawSnippet[:i]))
+
+		switch mark := rawSnippet[i : i+1]; {
+		case mark == SnippetStartSel && !opened:
+			result.WriteString("<mark>")
+			opened = true
+		case mark == SnippetStopSel && opened:
+			result.WriteString("</mark>")
+			opened = false
+		}
+
+		rawSnippet = rawSnippet[i+1:]
+	}
+
+	if opened {
+		result.WriteString("</mark>")
+	}
+
+	return result.String()
+}
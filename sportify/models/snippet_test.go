@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippetHTML(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "no marks", raw: "футбол во дворе", want: "футбол во дворе"},
+		{
+			name: "found words",
+			raw:  "играем в \x02футбол\x03 по \x02средам\x03",
+			want: "играем в <mark>футбол</mark> по <mark>средам</mark>",
+		},
+		{
+			name: "html of user is escaped",
+			raw:  "<img src=x onerror=alert(1)> \x02футбол\x03 & <b>",
+			want: "&lt;img src=x onerror=alert(1)&gt; <mark>футбол</mark> &amp; &lt;b&gt;",
+		},
+		{name: "unbalanced marks", raw: "\x03a \x02\x02b", want: "a <mark>b</mark>"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.want, SnippetHTML(testCase.raw))
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 const (
 	SportTypeVolleyball  SportType = "volleyball"
 	SportTypeBasketball  SportType = "basketball"
@@ -31,6 +36,20 @@ func EnToRuSportType(sportType SportType) (string, bool) {
 	return result, ok
 }
 
+// sportTypeTitle is russian name of sport type from capital letter for headers.
+func sportTypeTitle(sportType SportType) string {
+	title, ok := EnToRuSportType(sportType)
+	if !ok {
+		title = string(sportType)
+	}
+
+	if first, size := utf8.DecodeRuneInString(title); size > 0 {
+		title = strings.ToUpper(string(first)) + title[size:]
+	}
+
+	return title
+}
+
 var ruToEnSportType = map[string]SportType{ //nolint:gochecknoglobals
 	"волейбол":           SportTypeVolleyball,
 	"баскетбол":          SportTypeBasketball,
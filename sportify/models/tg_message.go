@@ -5,6 +5,7 @@ import "fmt"
 type TgMessage struct {
 	MessageID int `json:"message_id"`
 	Chat      struct {
+		ID       int64  `json:"id"`
 		Username string `json:"username"`
 	} `json:"chat"`
 	RawMessage string `json:"text"`
@@ -8,6 +8,13 @@ import (
 	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
 )
 
+// MoscowNow returns current moscow time marked as UTC, time of events is saved so.
+// Это жесткий костыль, как привратить time.Now() из московского пояса в utc, но лучше я не придумал
+// time.Local = time.UTC не работает должным образом
+func MoscowNow() time.Time {
+	return time.Now().Add(time.Hour * 3)
+}
+
 type dateAndTimeAPI struct {
 	Date      time.Time `json:"date"`
 	StartTime string    `json:"start_time"`
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type VenueSurface string
+
+const (
+	VenueSurfaceGrass           VenueSurface = "grass"
+	VenueSurfaceArtificialGrass VenueSurface = "artificial_grass"
+	VenueSurfaceParquet         VenueSurface = "parquet"
+	VenueSurfaceRubber          VenueSurface = "rubber"
+	VenueSurfaceAsphalt         VenueSurface = "asphalt"
+	VenueSurfaceIce             VenueSurface = "ice"
+	VenueSurfaceSand            VenueSurface = "sand"
+	VenueSurfaceClay            VenueSurface = "clay"
+	VenueSurfaceOther           VenueSurface = "other"
+)
+
+func (s VenueSurface) Valid() bool {
+	switch s {
+	case VenueSurfaceGrass, VenueSurfaceArtificialGrass, VenueSurfaceParquet, VenueSurfaceRubber,
+		VenueSurfaceAsphalt, VenueSurfaceIce, VenueSurfaceSand, VenueSurfaceClay, VenueSurfaceOther:
+		return true
+	default:
+		return false
+	}
+}
+
+type VenueAmenity string
+
+// Amenities are the same facilities that are matched in telegram messages
+// by app.SportEventRegExps plus a few that matter for choosing a place.
+const (
+	VenueAmenityShowers       VenueAmenity = "showers"
+	VenueAmenityChangingRooms VenueAmenity = "changing_rooms"
+	VenueAmenityDrinkingWater VenueAmenity = "drinking_water"
+	VenueAmenityVideo         VenueAmenity = "video"
+	VenueAmenityParking       VenueAmenity = "parking"
+	VenueAmenityLighting      VenueAmenity = "lighting"
+)
+
+var enToRuVenueAmenity = map[VenueAmenity]string{ //nolint:gochecknoglobals
+	VenueAmenityShowers:       "душевые",
+	VenueAmenityChangingRooms: "раздевалки",
+	VenueAmenityDrinkingWater: "вода для игроков",
+	VenueAmenityVideo:         "видеосъёмка",
+	VenueAmenityParking:       "парковка",
+	VenueAmenityLighting:      "освещение",
+}
+
+func EnToRuVenueAmenity(amenity VenueAmenity) (string, bool) {
+	result, ok := enToRuVenueAmenity[amenity]
+	return result, ok
+}
+
+type Venue struct {
+	ID                uuid.UUID      `json:"id"`
+	CreatorID         *uuid.UUID     `json:"creator_id"`
+	Name              string         `json:"name"`
+	Address           string         `json:"address"`
+	NormalizedAddress string         `json:"normalized_address"`
+	Latitude          *string        `json:"latitude"`
+	Longitude         *string        `json:"longitude"`
+	Surface           *VenueSurface  `json:"surface"`
+	Indoor            bool           `json:"indoor"`
+	SportTypes        []SportType    `json:"sport_types"`
+	Amenities         []VenueAmenity `json:"amenities"`
+	URLPhotos         []string       `json:"photos"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+type VenuePageAPI struct {
+	Venue
+	UpcomingEvents []ShortEvent `json:"upcoming_events"`
+}
+
+// validCoordinates checks that coordinates are numbers in range, they are saved to ST_Point as is.
+func validCoordinates(latitude, longitude string) error {
+	latitudeValue, errLatitude := strconv.ParseFloat(latitude, 64)
+	longitudeValue, errLongitude := strconv.ParseFloat(longitude, 64)
+
+	if errLatitude != nil || errLongitude != nil {
+		return fmt.Errorf("координаты должны быть числами")
+	}
+
+	if latitudeValue < -90 || latitudeValue > 90 || longitudeValue < -180 || longitudeValue > 180 {
+		return fmt.Errorf("координаты вне диапазона")
+	}
+
+	return nil
+}
+
+type RequestVenueCreate struct {
+	Name       string         `json:"name"`
+	Address    string         `json:"address"`
+	Latitude   *string        `json:"latitude"`
+	Longitude  *string        `json:"longitude"`
+	Surface    *VenueSurface  `json:"surface"`
+	Indoor     bool           `json:"indoor"`
+	SportTypes []SportType    `json:"sport_types"`
+	Amenities  []VenueAmenity `json:"amenities"`
+	URLPhotos  []string       `json:"photos"`
+}
+
+//nolint:cyclop
+func (r *RequestVenueCreate) Valid() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Address = strings.TrimSpace(r.Address)
+
+	if r.Name == "" {
+		return fmt.Errorf("название площадки не может быть пустым")
+	}
+
+	if utf8.RuneCountInString(r.Name) > 256 {
+		return fmt.Errorf("название площадки должно быть короче 256 символов")
+	}
+
+	if r.Address == "" {
+		return fmt.Errorf("адрес площадки не может быть пустым")
+	}
+
+	if utf8.RuneCountInString(r.Address) > 512 {
+		return fmt.Errorf("адрес площадки должен быть короче 512 символов")
+	}
+
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return fmt.Errorf("координаты нужно указывать вместе: широту и долготу")
+	}
+
+	if r.Latitude != nil {
+		err := validCoordinates(*r.Latitude, *r.Longitude)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Surface != nil && !r.Surface.Valid() {
+		return fmt.Errorf("неизвестное покрытие %q", *r.Surface)
+	}
+
+	for _, sportType := range r.SportTypes {
+		if _, ok := EnToRuSportType(sportType); !ok {
+			return fmt.Errorf("неизвестный вид спорта %q", sportType)
+		}
+	}
+
+	for _, amenity := range r.Amenities {
+		if _, ok := EnToRuVenueAmenity(amenity); !ok {
+			return fmt.Errorf("неизвестное удобство %q", amenity)
+		}
+	}
+
+	return nil
+}
+
+type RequestVenueEdit struct {
+	Name       *string        `json:"name"`
+	Address    *string        `json:"address"`
+	Latitude   *string        `json:"latitude"`
+	Longitude  *string        `json:"longitude"`
+	Surface    *VenueSurface  `json:"surface"`
+	Indoor     *bool          `json:"indoor"`
+	SportTypes []SportType    `json:"sport_types"`
+	Amenities  []VenueAmenity `json:"amenities"`
+	URLPhotos  []string       `json:"photos"`
+}
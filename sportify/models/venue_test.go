@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestVenueCreateCoordinates(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		latitude  *string
+		longitude *string
+		wantErr   bool
+	}{
+		{name: "without coordinates"},
+		{name: "moscow", latitude: common.Ref("55.7558"), longitude: common.Ref("37.6173")},
+		{name: "only latitude", latitude: common.Ref("55.7558"), wantErr: true},
+		{name: "not number", latitude: common.Ref("55.7558"), longitude: common.Ref("37.6'); DROP"), wantErr: true},
+		{name: "latitude out of range", latitude: common.Ref("91"), longitude: common.Ref("37.6"), wantErr: true},
+		{name: "longitude out of range", latitude: common.Ref("55.7"), longitude: common.Ref("-180.5"), wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := &RequestVenueCreate{ //nolint:exhaustruct
+				Name:      "Лужники",
+				Address:   "Москва, Лужнецкая набережная, 24",
+				Latitude:  testCase.latitude,
+				Longitude: testCase.longitude,
+			}
+
+			err := request.Valid()
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
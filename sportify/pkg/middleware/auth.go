@@ -72,6 +72,12 @@ var mapErrReplace = map[string]string{
 
 func ConvertErrUnknownToOurType(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// stream of event can't be buffered, its errors are written by handler in our type already
+		if strings.HasSuffix(r.URL.Path, "/stream") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		dummyWriter := httptest.NewRecorder()
 		next.ServeHTTP(dummyWriter, r)
 
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// redactedQueryParams are secrets which are passed in url, for example token of calendar feed,
+// calendar apps can't send headers.
+var redactedQueryParams = []string{"token"} //nolint:gochecknoglobals
+
+type redactingLogFormatter struct {
+	chimiddleware.LogFormatter
+}
+
+func (f redactingLogFormatter) NewLogEntry(r *http.Request) chimiddleware.LogEntry {
+	query := r.URL.Query()
+	redacted := false
+
+	for _, param := range redactedQueryParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+
+			redacted = true
+		}
+	}
+
+	if !redacted {
+		return f.LogFormatter.NewLogEntry(r)
+	}
+
+	logged := r.Clone(r.Context())
+	logged.URL.RawQuery = query.Encode()
+	logged.RequestURI = logged.URL.RequestURI()
+
+	return f.LogFormatter.NewLogEntry(logged)
+}
+
+// Logger is Logger of chi, which doesn't write secrets from url to log.
+func Logger(next http.Handler) http.Handler {
+	return chimiddleware.RequestLogger(redactingLogFormatter{
+		LogFormatter: &chimiddleware.DefaultLogFormatter{Logger: log.New(os.Stdout, "", log.LstdFlags), NoColor: false},
+	})(next)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactingLogFormatter(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	formatter := redactingLogFormatter{
+		LogFormatter: &chimiddleware.DefaultLogFormatter{Logger: log.New(buf, "", 0), NoColor: true},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/users/1/calendar.ics?token=secret&lang=ru", nil)
+	formatter.NewLogEntry(r).Write(http.StatusOK, 0, http.Header{}, time.Millisecond, nil)
+
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Contains(t, buf.String(), "token=REDACTED")
+	assert.Equal(t, "secret", r.URL.Query().Get("token"))
+}
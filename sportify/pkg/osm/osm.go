@@ -0,0 +1,201 @@
+// Package osm reads nodes and ways from local OpenStreetMap extracts
+// in PBF (.osm.pbf) or XML (.osm, .osm.bz2) format.
+package osm
+
+import (
+	"compress/bzip2"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("unknown osm extract format, expected .pbf, .osm, .xml or .osm.bz2")
+
+type ObjectType string
+
+const (
+	ObjectTypeNode ObjectType = "node"
+	ObjectTypeWay  ObjectType = "way"
+)
+
+// Object is tagged node or way. Way is reduced to the centroid of its nodes.
+type Object struct {
+	Type ObjectType
+	ID   int64
+	Tags map[string]string
+	Lat  float64
+	Lon  float64
+}
+
+// Key is stable OSM identifier like "way/123456".
+func (o *Object) Key() string {
+	return string(o.Type) + "/" + strconv.FormatInt(o.ID, 10)
+}
+
+// Filter decides by tags if object is needed.
+type Filter func(tags map[string]string) bool
+
+// handler receives elements in file order. Tags are nil when not requested.
+type handler interface {
+	node(id int64, lat, lon float64, tags map[string]string)
+	way(id int64, refs []int64, tags map[string]string)
+}
+
+type decodeFunc func(r io.Reader, h handler) error
+
+func openExtract(path string) (io.ReadCloser, decodeFunc, error) {
+	var decode decodeFunc
+
+	switch {
+	case strings.HasSuffix(path, ".pbf"):
+		decode = decodePBF
+	case strings.HasSuffix(path, ".osm"), strings.HasSuffix(path, ".xml"), strings.HasSuffix(path, ".osm.bz2"):
+		decode = decodeXML
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !strings.HasSuffix(path, ".bz2") {
+		return file, decode, nil
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{Reader: bzip2.NewReader(file), Closer: file}, decode, nil
+}
+
+// ReadFile returns objects matching filter. Extract is read twice when ways match:
+// the second pass only collects coordinates of their nodes, so memory
+// does not depend on the size of the whole extract. Relations are skipped.
+func ReadFile(path string, filter Filter) ([]Object, error) {
+	collector := &matchCollector{filter: filter, neededNodes: make(map[int64]*nodeCoordinates)}
+
+	err := readFile(path, collector)
+	if err != nil {
+		return nil, fmt.Errorf("to read objects: %w", err)
+	}
+
+	if len(collector.ways) == 0 {
+		return collector.result, nil
+	}
+
+	err = readFile(path, &nodeCollector{neededNodes: collector.neededNodes})
+	if err != nil {
+		return nil, fmt.Errorf("to read way nodes: %w", err)
+	}
+
+	for _, way := range collector.ways {
+		object, ok := way.centroid(collector.neededNodes)
+		if ok {
+			collector.result = append(collector.result, object)
+		}
+	}
+
+	return collector.result, nil
+}
+
+func readFile(path string, h handler) error {
+	file, decode, err := openExtract(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return decode(file, h)
+}
+
+type nodeCoordinates struct {
+	lat, lon float64
+	found    bool
+}
+
+type pendingWay struct {
+	id   int64
+	refs []int64
+	tags map[string]string
+}
+
+func (w *pendingWay) centroid(nodes map[int64]*nodeCoordinates) (Object, bool) {
+	refs := w.refs
+	// closed way repeats first node at the end
+	if len(refs) > 1 && refs[0] == refs[len(refs)-1] {
+		refs = refs[:len(refs)-1]
+	}
+
+	var sumLat, sumLon float64
+
+	count := 0
+
+	for _, ref := range refs {
+		node := nodes[ref]
+		if node == nil || !node.found {
+			continue
+		}
+
+		sumLat += node.lat
+		sumLon += node.lon
+		count++
+	}
+
+	if count == 0 {
+		return Object{}, false
+	}
+
+	return Object{
+		Type: ObjectTypeWay,
+		ID:   w.id,
+		Tags: w.tags,
+		Lat:  sumLat / float64(count),
+		Lon:  sumLon / float64(count),
+	}, true
+}
+
+type matchCollector struct {
+	filter      Filter
+	result      []Object
+	ways        []pendingWay
+	neededNodes map[int64]*nodeCoordinates
+}
+
+func (c *matchCollector) node(id int64, lat, lon float64, tags map[string]string) {
+	if len(tags) == 0 || !c.filter(tags) {
+		return
+	}
+
+	c.result = append(c.result, Object{Type: ObjectTypeNode, ID: id, Tags: tags, Lat: lat, Lon: lon})
+}
+
+func (c *matchCollector) way(id int64, refs []int64, tags map[string]string) {
+	if len(tags) == 0 || !c.filter(tags) {
+		return
+	}
+
+	c.ways = append(c.ways, pendingWay{id: id, refs: refs, tags: tags})
+
+	for _, ref := range refs {
+		if _, ok := c.neededNodes[ref]; !ok {
+			c.neededNodes[ref] = &nodeCoordinates{}
+		}
+	}
+}
+
+type nodeCollector struct {
+	neededNodes map[int64]*nodeCoordinates
+}
+
+func (c *nodeCollector) node(id int64, lat, lon float64, _ map[string]string) {
+	if node, ok := c.neededNodes[id]; ok {
+		node.lat, node.lon, node.found = lat, lon, true
+	}
+}
+
+func (c *nodeCollector) way(int64, []int64, map[string]string) {}
@@ -0,0 +1,212 @@
+package osm_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/TheVovchenskiy/sportify-backend/pkg/osm"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func isPitch(tags map[string]string) bool {
+	return tags["leisure"] == "pitch"
+}
+
+func sortedByKey(objects []osm.Object) []osm.Object {
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key() < objects[j].Key()
+	})
+
+	return objects
+}
+
+const testXML = `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" lat="55.0" lon="37.0"/>
+  <node id="2" lat="55.0" lon="37.2"/>
+  <node id="3" lat="55.2" lon="37.2"/>
+  <node id="4" lat="55.2" lon="37.0"/>
+  <node id="5" lat="56.0" lon="38.0">
+    <tag k="leisure" v="pitch"/>
+    <tag k="sport" v="soccer"/>
+  </node>
+  <node id="6" lat="56.0" lon="38.0">
+    <tag k="amenity" v="cafe"/>
+  </node>
+  <way id="10">
+    <nd ref="1"/>
+    <nd ref="2"/>
+    <nd ref="3"/>
+    <nd ref="4"/>
+    <nd ref="1"/>
+    <tag k="leisure" v="pitch"/>
+    <tag k="name" v="Коробка"/>
+  </way>
+</osm>`
+
+func TestReadFileXML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "extract.osm")
+	err := os.WriteFile(path, []byte(testXML), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := osm.ReadFile(path, isPitch)
+	if !assert.NoError(t, err) || !assert.Len(t, objects, 2) {
+		return
+	}
+
+	objects = sortedByKey(objects)
+
+	assert.Equal(t, "node/5", objects[0].Key())
+	assert.Equal(t, "soccer", objects[0].Tags["sport"])
+	assert.InDelta(t, 56.0, objects[0].Lat, 1e-9)
+
+	assert.Equal(t, "way/10", objects[1].Key())
+	assert.Equal(t, "Коробка", objects[1].Tags["name"])
+	assert.InDelta(t, 55.1, objects[1].Lat, 1e-9)
+	assert.InDelta(t, 37.1, objects[1].Lon, 1e-9)
+}
+
+func appendPacked(b []byte, num protowire.Number, values []uint64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = protowire.AppendVarint(packed, v)
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+
+	return protowire.AppendBytes(b, packed)
+}
+
+func zigzag(values ...int64) []uint64 {
+	result := make([]uint64, len(values))
+	for i, v := range values {
+		result[i] = protowire.EncodeZigZag(v)
+	}
+
+	return result
+}
+
+func appendBlob(t *testing.T, file []byte, blobType string, data []byte) []byte {
+	t.Helper()
+
+	var compressed bytes.Buffer
+
+	writer := zlib.NewWriter(&compressed)
+	_, err := writer.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var blob []byte
+	blob = protowire.AppendTag(blob, 2, protowire.VarintType)
+	blob = protowire.AppendVarint(blob, uint64(len(data)))
+	blob = protowire.AppendTag(blob, 3, protowire.BytesType)
+	blob = protowire.AppendBytes(blob, compressed.Bytes())
+
+	var header []byte
+	header = protowire.AppendTag(header, 1, protowire.BytesType)
+	header = protowire.AppendString(header, blobType)
+	header = protowire.AppendTag(header, 3, protowire.VarintType)
+	header = protowire.AppendVarint(header, uint64(len(blob)))
+
+	file = binary.BigEndian.AppendUint32(file, uint32(len(header)))
+	file = append(file, header...)
+
+	return append(file, blob...)
+}
+
+func testPBF(t *testing.T) []byte {
+	t.Helper()
+
+	var headerBlock []byte
+	headerBlock = protowire.AppendTag(headerBlock, 4, protowire.BytesType)
+	headerBlock = protowire.AppendString(headerBlock, "OsmSchema-V0.6")
+	headerBlock = protowire.AppendTag(headerBlock, 4, protowire.BytesType)
+	headerBlock = protowire.AppendString(headerBlock, "DenseNodes")
+
+	var stringTable []byte
+	for _, s := range []string{"", "leisure", "pitch", "name", "Коробка", "sport", "soccer"} {
+		stringTable = protowire.AppendTag(stringTable, 1, protowire.BytesType)
+		stringTable = protowire.AppendString(stringTable, s)
+	}
+
+	// granularity 100 nanodegrees: 55.0 = 550000000
+	var dense []byte
+	dense = appendPacked(dense, 1, zigzag(1, 1, 1, 1, 1))
+	dense = appendPacked(dense, 8, zigzag(550000000, 0, 2000000, 0, 8000000))
+	dense = appendPacked(dense, 9, zigzag(370000000, 2000000, 0, -2000000, 10000000))
+	dense = appendPacked(dense, 10, []uint64{0, 0, 0, 0, 1, 2, 5, 6, 0})
+
+	var way []byte
+	way = protowire.AppendTag(way, 1, protowire.VarintType)
+	way = protowire.AppendVarint(way, 10)
+	way = appendPacked(way, 2, []uint64{1, 3})
+	way = appendPacked(way, 3, []uint64{2, 4})
+	way = appendPacked(way, 8, zigzag(1, 1, 1, 1, -3))
+
+	var group []byte
+	group = protowire.AppendTag(group, 2, protowire.BytesType)
+	group = protowire.AppendBytes(group, dense)
+	group = protowire.AppendTag(group, 3, protowire.BytesType)
+	group = protowire.AppendBytes(group, way)
+
+	var block []byte
+	block = protowire.AppendTag(block, 1, protowire.BytesType)
+	block = protowire.AppendBytes(block, stringTable)
+	block = protowire.AppendTag(block, 2, protowire.BytesType)
+	block = protowire.AppendBytes(block, group)
+
+	file := appendBlob(t, nil, "OSMHeader", headerBlock)
+
+	return appendBlob(t, file, "OSMData", block)
+}
+
+func TestReadFilePBF(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "extract.osm.pbf")
+	err := os.WriteFile(path, testPBF(t), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := osm.ReadFile(path, isPitch)
+	if !assert.NoError(t, err) || !assert.Len(t, objects, 2) {
+		return
+	}
+
+	objects = sortedByKey(objects)
+
+	assert.Equal(t, "node/5", objects[0].Key())
+	assert.Equal(t, "soccer", objects[0].Tags["sport"])
+	assert.InDelta(t, 56.0, objects[0].Lat, 1e-9)
+	assert.InDelta(t, 38.0, objects[0].Lon, 1e-9)
+
+	assert.Equal(t, "way/10", objects[1].Key())
+	assert.Equal(t, "Коробка", objects[1].Tags["name"])
+	assert.InDelta(t, 55.1, objects[1].Lat, 1e-9)
+	assert.InDelta(t, 37.1, objects[1].Lon, 1e-9)
+}
+
+func TestReadFileUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := osm.ReadFile("extract.geojson", isPitch)
+	assert.ErrorIs(t, err, osm.ErrUnknownFormat)
+}
@@ -0,0 +1,508 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers are taken from fileformat.proto and osmformat.proto of OSM-binary.
+const (
+	maxBlobHeaderSize = 64 * 1024
+	maxBlobSize       = 32 * 1024 * 1024
+
+	blobHeaderType     protowire.Number = 1
+	blobHeaderDataSize protowire.Number = 3
+
+	blobRaw      protowire.Number = 1
+	blobRawSize  protowire.Number = 2
+	blobZlibData protowire.Number = 3
+
+	headerRequiredFeatures protowire.Number = 4
+
+	blockStringTable  protowire.Number = 1
+	blockGroup        protowire.Number = 2
+	blockGranularity  protowire.Number = 17
+	blockLatOffset    protowire.Number = 19
+	blockLonOffset    protowire.Number = 20
+	stringTableString protowire.Number = 1
+
+	groupNodes protowire.Number = 1
+	groupDense protowire.Number = 2
+	groupWays  protowire.Number = 3
+
+	nodeID   protowire.Number = 1
+	nodeKeys protowire.Number = 2
+	nodeVals protowire.Number = 3
+	nodeLat  protowire.Number = 8
+	nodeLon  protowire.Number = 9
+
+	denseID       protowire.Number = 1
+	denseLat      protowire.Number = 8
+	denseLon      protowire.Number = 9
+	denseKeysVals protowire.Number = 10
+
+	wayID   protowire.Number = 1
+	wayKeys protowire.Number = 2
+	wayVals protowire.Number = 3
+	wayRefs protowire.Number = 8
+)
+
+var (
+	ErrPBFFormat      = errors.New("malformed osm pbf")
+	ErrPBFUnsupported = errors.New("unsupported osm pbf feature")
+)
+
+type pbfField struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+func forEachField(b []byte, fn func(f pbfField) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrPBFFormat, protowire.ParseError(n))
+		}
+
+		b = b[n:]
+		field := pbfField{num: num, typ: typ} //nolint:exhaustruct
+
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrPBFFormat, protowire.ParseError(n))
+		}
+
+		b = b[n:]
+
+		err := fn(field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendVarints supports both packed and not packed repeated fields.
+func appendVarints(dst []uint64, field pbfField) ([]uint64, error) {
+	if field.typ == protowire.VarintType {
+		return append(dst, field.varint), nil
+	}
+
+	b := field.bytes
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, fmt.Errorf("%w: %w", ErrPBFFormat, protowire.ParseError(n))
+		}
+
+		dst = append(dst, v)
+		b = b[n:]
+	}
+
+	return dst, nil
+}
+
+func readBlob(r io.Reader) (string, []byte, error) {
+	var rawHeaderSize [4]byte
+
+	_, err := io.ReadFull(r, rawHeaderSize[:])
+	if err != nil {
+		return "", nil, err
+	}
+
+	headerSize := binary.BigEndian.Uint32(rawHeaderSize[:])
+	if headerSize > maxBlobHeaderSize {
+		return "", nil, fmt.Errorf("%w: blob header size %d", ErrPBFFormat, headerSize)
+	}
+
+	rawHeader := make([]byte, headerSize)
+
+	_, err = io.ReadFull(r, rawHeader)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrPBFFormat, err)
+	}
+
+	var (
+		blobType string
+		dataSize uint64
+	)
+
+	err = forEachField(rawHeader, func(f pbfField) error {
+		switch f.num {
+		case blobHeaderType:
+			blobType = string(f.bytes)
+		case blobHeaderDataSize:
+			dataSize = f.varint
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if dataSize > maxBlobSize {
+		return "", nil, fmt.Errorf("%w: blob size %d", ErrPBFFormat, dataSize)
+	}
+
+	rawBlob := make([]byte, dataSize)
+
+	_, err = io.ReadFull(r, rawBlob)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrPBFFormat, err)
+	}
+
+	data, err := unpackBlob(rawBlob)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return blobType, data, nil
+}
+
+func unpackBlob(rawBlob []byte) ([]byte, error) {
+	var (
+		data       []byte
+		compressed []byte
+		rawSize    uint64
+		other      protowire.Number
+	)
+
+	err := forEachField(rawBlob, func(f pbfField) error {
+		switch f.num {
+		case blobRaw:
+			data = f.bytes
+		case blobRawSize:
+			rawSize = f.varint
+		case blobZlibData:
+			compressed = f.bytes
+		default:
+			other = f.num
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case data != nil:
+		return data, nil
+	case compressed != nil:
+		if rawSize > maxBlobSize {
+			return nil, fmt.Errorf("%w: raw blob size %d", ErrPBFFormat, rawSize)
+		}
+
+		reader, err := zlib.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrPBFFormat, err)
+		}
+		defer reader.Close()
+
+		result := make([]byte, 0, rawSize)
+		buffer := bytes.NewBuffer(result)
+
+		_, err = io.Copy(buffer, io.LimitReader(reader, maxBlobSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrPBFFormat, err)
+		}
+
+		return buffer.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w: blob compression field %d", ErrPBFUnsupported, other)
+	}
+}
+
+func checkHeaderBlock(data []byte) error {
+	return forEachField(data, func(f pbfField) error {
+		if f.num != headerRequiredFeatures {
+			return nil
+		}
+
+		switch feature := string(f.bytes); feature {
+		case "OsmSchema-V0.6", "DenseNodes":
+			return nil
+		default:
+			return fmt.Errorf("%w: required feature %s", ErrPBFUnsupported, feature)
+		}
+	})
+}
+
+func decodePBF(r io.Reader, h handler) error {
+	for {
+		blobType, data, err := readBlob(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		switch blobType {
+		case "OSMHeader":
+			err = checkHeaderBlock(data)
+		case "OSMData":
+			err = decodePrimitiveBlock(data, h)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type primitiveBlock struct {
+	strings     []string
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+func (b *primitiveBlock) coordinate(offset, value int64) float64 {
+	return 1e-9 * float64(offset+b.granularity*value)
+}
+
+func (b *primitiveBlock) tags(keys, vals []uint64) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	if len(keys) != len(vals) {
+		return nil, fmt.Errorf("%w: %d keys and %d values", ErrPBFFormat, len(keys), len(vals))
+	}
+
+	result := make(map[string]string, len(keys))
+
+	for i := range keys {
+		if keys[i] >= uint64(len(b.strings)) || vals[i] >= uint64(len(b.strings)) {
+			return nil, fmt.Errorf("%w: string index out of range", ErrPBFFormat)
+		}
+
+		result[b.strings[keys[i]]] = b.strings[vals[i]]
+	}
+
+	return result, nil
+}
+
+func decodePrimitiveBlock(data []byte, h handler) error {
+	block := primitiveBlock{granularity: 100} //nolint:exhaustruct
+
+	var groups [][]byte
+
+	err := forEachField(data, func(f pbfField) error {
+		switch f.num {
+		case blockStringTable:
+			return forEachField(f.bytes, func(s pbfField) error {
+				if s.num == stringTableString {
+					block.strings = append(block.strings, string(s.bytes))
+				}
+
+				return nil
+			})
+		case blockGroup:
+			groups = append(groups, f.bytes)
+		case blockGranularity:
+			block.granularity = int64(f.varint)
+		case blockLatOffset:
+			block.latOffset = int64(f.varint)
+		case blockLonOffset:
+			block.lonOffset = int64(f.varint)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		err = forEachField(group, func(f pbfField) error {
+			switch f.num {
+			case groupNodes:
+				return block.decodeNode(f.bytes, h)
+			case groupDense:
+				return block.decodeDenseNodes(f.bytes, h)
+			case groupWays:
+				return block.decodeWay(f.bytes, h)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *primitiveBlock) decodeNode(data []byte, h handler) error {
+	var (
+		id, lat, lon int64
+		keys, vals   []uint64
+	)
+
+	err := forEachField(data, func(f pbfField) error {
+		var err error
+
+		switch f.num {
+		case nodeID:
+			id = protowire.DecodeZigZag(f.varint)
+		case nodeLat:
+			lat = protowire.DecodeZigZag(f.varint)
+		case nodeLon:
+			lon = protowire.DecodeZigZag(f.varint)
+		case nodeKeys:
+			keys, err = appendVarints(keys, f)
+		case nodeVals:
+			vals, err = appendVarints(vals, f)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	tags, err := b.tags(keys, vals)
+	if err != nil {
+		return err
+	}
+
+	h.node(id, b.coordinate(b.latOffset, lat), b.coordinate(b.lonOffset, lon), tags)
+
+	return nil
+}
+
+//nolint:cyclop
+func (b *primitiveBlock) decodeDenseNodes(data []byte, h handler) error {
+	var ids, lats, lons, keysVals []uint64
+
+	err := forEachField(data, func(f pbfField) error {
+		var err error
+
+		switch f.num {
+		case denseID:
+			ids, err = appendVarints(ids, f)
+		case denseLat:
+			lats, err = appendVarints(lats, f)
+		case denseLon:
+			lons, err = appendVarints(lons, f)
+		case denseKeysVals:
+			keysVals, err = appendVarints(keysVals, f)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return fmt.Errorf("%w: dense nodes with %d ids, %d lats, %d lons", ErrPBFFormat, len(ids), len(lats), len(lons))
+	}
+
+	var id, lat, lon int64
+
+	idxKeyVal := 0
+
+	for i := range ids {
+		// ids and coordinates are delta coded
+		id += protowire.DecodeZigZag(ids[i])
+		lat += protowire.DecodeZigZag(lats[i])
+		lon += protowire.DecodeZigZag(lons[i])
+
+		var keys, vals []uint64
+
+		// keys_vals is (key, val)* 0 for every node, empty when no node has tags
+		for idxKeyVal < len(keysVals) {
+			key := keysVals[idxKeyVal]
+			idxKeyVal++
+
+			if key == 0 {
+				break
+			}
+
+			if idxKeyVal >= len(keysVals) {
+				return fmt.Errorf("%w: dense key without value", ErrPBFFormat)
+			}
+
+			keys = append(keys, key)
+			vals = append(vals, keysVals[idxKeyVal])
+			idxKeyVal++
+		}
+
+		tags, err := b.tags(keys, vals)
+		if err != nil {
+			return err
+		}
+
+		h.node(id, b.coordinate(b.latOffset, lat), b.coordinate(b.lonOffset, lon), tags)
+	}
+
+	return nil
+}
+
+func (b *primitiveBlock) decodeWay(data []byte, h handler) error {
+	var (
+		id               int64
+		keys, vals, refs []uint64
+	)
+
+	err := forEachField(data, func(f pbfField) error {
+		var err error
+
+		switch f.num {
+		case wayID:
+			id = int64(f.varint)
+		case wayKeys:
+			keys, err = appendVarints(keys, f)
+		case wayVals:
+			vals, err = appendVarints(vals, f)
+		case wayRefs:
+			refs, err = appendVarints(refs, f)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	tags, err := b.tags(keys, vals)
+	if err != nil {
+		return err
+	}
+
+	nodeRefs := make([]int64, len(refs))
+
+	var ref int64
+
+	for i := range refs {
+		ref += protowire.DecodeZigZag(refs[i])
+		nodeRefs[i] = ref
+	}
+
+	h.way(id, nodeRefs, tags)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package osm
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+type xmlElement struct {
+	name     string
+	id       int64
+	lat, lon float64
+	refs     []int64
+	tags     map[string]string
+}
+
+func (e *xmlElement) emit(h handler) {
+	switch e.name {
+	case "node":
+		h.node(e.id, e.lat, e.lon, e.tags)
+	case "way":
+		h.way(e.id, e.refs, e.tags)
+	}
+}
+
+//nolint:cyclop
+func decodeXML(r io.Reader, h handler) error {
+	decoder := xml.NewDecoder(r)
+
+	var current *xmlElement
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("to read xml token: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "node", "way":
+				current = &xmlElement{name: element.Name.Local} //nolint:exhaustruct
+
+				current.id, err = strconv.ParseInt(xmlAttr(element, "id"), 10, 64)
+				if err != nil {
+					return fmt.Errorf("to parse %s id: %w", element.Name.Local, err)
+				}
+
+				if element.Name.Local == "node" {
+					current.lat, err = strconv.ParseFloat(xmlAttr(element, "lat"), 64)
+					if err != nil {
+						return fmt.Errorf("to parse lat of node %d: %w", current.id, err)
+					}
+
+					current.lon, err = strconv.ParseFloat(xmlAttr(element, "lon"), 64)
+					if err != nil {
+						return fmt.Errorf("to parse lon of node %d: %w", current.id, err)
+					}
+				}
+			case "tag":
+				if current == nil {
+					continue
+				}
+
+				if current.tags == nil {
+					current.tags = make(map[string]string)
+				}
+
+				current.tags[xmlAttr(element, "k")] = xmlAttr(element, "v")
+			case "nd":
+				if current == nil {
+					continue
+				}
+
+				ref, err := strconv.ParseInt(xmlAttr(element, "ref"), 10, 64)
+				if err != nil {
+					return fmt.Errorf("to parse nd ref of way %d: %w", current.id, err)
+				}
+
+				current.refs = append(current.refs, ref)
+			}
+		case xml.EndElement:
+			if current != nil && element.Name.Local == current.name {
+				current.emit(h)
+				current = nil
+			}
+		}
+	}
+}
@@ -1,107 +1,124 @@
+// Package reformat_url_open_map normalizes russian addresses typed by users
+// to the form OpenStreetMap and other geocoders understand: abbreviations of
+// street types are expanded, region before city and building parts after house
+// number are dropped.
 package reformat_url_open_map
 
-import "strings"
-
-func replaceCityURLOpenMap(url string) string {
-	separator := "г "
-	idxCityStart := strings.Index(url, separator)
-	if idxCityStart == -1 {
-		return url
-	}
+import (
+	"strings"
+	"unicode"
+)
+
+// abbreviations are short forms of address elements from FIAS.
+var abbreviations = map[string]string{ //nolint:gochecknoglobals
+	"ал":     "аллея",
+	"б-р":    "бульвар",
+	"бул":    "бульвар",
+	"взв":    "взвоз",
+	"взд":    "въезд",
+	"дор":    "дорога",
+	"ззд":    "заезд",
+	"км":     "километр",
+	"к-цо":   "кольцо",
+	"лн":     "линия",
+	"мгстр":  "магистраль",
+	"наб":    "набережная",
+	"пер-д":  "переезд",
+	"пер":    "переулок",
+	"пл-ка":  "площадка",
+	"пл":     "площадь",
+	"пр-д":   "проезд",
+	"пр-кт":  "проспект",
+	"просп":  "проспект",
+	"пр-ка":  "просека",
+	"пр-к":   "просек",
+	"пр-лок": "проселок",
+	"проул":  "проулок",
+	"рзд":    "разъезд",
+	"с-р":    "сквер",
+	"с-к":    "спуск",
+	"сзд":    "съезд",
+	"туп":    "тупик",
+	"ул":     "улица",
+	"ш":      "шоссе",
+	"тер":    "территория",
+	"обл":    "область",
+	"мкр":    "микрорайон",
+	"пос":    "поселок",
+}
 
-	idxCityEnd := idxCityStart + len(separator)
+// cityPrefixes start city part, everything before it is region and is dropped.
+var cityPrefixes = map[string]struct{}{ //nolint:gochecknoglobals
+	"г":     {},
+	"город": {},
+}
 
-	return url[idxCityEnd:]
+// housePrefixes start house number, everything after number (корпус, строение) is dropped.
+var housePrefixes = map[string]struct{}{ //nolint:gochecknoglobals
+	"д":   {},
+	"дом": {},
 }
 
-func replaceHomeURLOpenMap(url string) string {
-	url = strings.ReplaceAll(url, " д ", " дом ")
+func abbreviationKey(word string) string {
+	return strings.ToLower(strings.TrimSuffix(word, "."))
+}
 
-	separator := " дом "
-	idxHomeStart := strings.Index(url, separator)
-	if idxHomeStart == -1 {
-		return url
+func startsWithDigit(word string) bool {
+	for _, r := range word {
+		return unicode.IsDigit(r)
 	}
 
-	idxHomeEnd := idxHomeStart + len(separator)
-	endWithHomeWithExtra := url[idxHomeEnd+1:]
+	return false
+}
 
-	idxExtraStart := strings.Index(endWithHomeWithExtra, " ")
-	if idxExtraStart == -1 {
-		url = strings.ReplaceAll(url, "дом ", "")
-		return url
-	}
+// normalizePart expands abbreviations of one comma separated part of address.
+// Second result is house number if part contains it, rest of address is dropped then.
+func normalizePart(words []string) ([]string, string) {
+	result := make([]string, 0, len(words))
 
-	url = url[:idxHomeEnd+idxExtraStart+1]
-	url = strings.ReplaceAll(url, "дом ", "")
+	for i, word := range words {
+		key := abbreviationKey(word)
 
-	return url
-}
+		if _, ok := housePrefixes[key]; ok && i+1 < len(words) && startsWithDigit(words[i+1]) {
+			return result, words[i+1]
+		}
 
-func replaceAnotherURLOpenMap(url string) string {
-	replacements := map[string]string{
-		" ал ":     " аллея ",
-		" б-р ":    " бульвар ",
-		" взв ":    " взвоз ",
-		" взд ":    " въезд ",
-		" дор ":    " дорога ",
-		" ззд ":    " заезд ",
-		" км ":     " километр ",
-		" к-цо ":   " кольцо ",
-		" коса ":   " коса ",
-		" лн ":     " линия ",
-		" мгстр ":  " магистраль ",
-		" наб ":    " набережная ",
-		" пер-д ":  " переезд ",
-		" пер ":    " переулок ",
-		" пл-ка ":  " площадк а",
-		" пл ":     " площадь ",
-		" пр-д ":   " проезд ",
-		" пр-кт ":  " проспект ",
-		" пр-ка ":  " просека ",
-		" пр-к ":   " просек ",
-		" пр-лок ": " проселок ",
-		" проул ":  " проулок ",
-		" рзд ":    " разъезд ",
-		" ряд ":    " ряд ",
-		" с-р ":    " сквер ",
-		" с-к ":    " спуск ",
-		" сзд ":    " съезд ",
-		" тракт ":  " тракт ",
-		" туп ":    " тупик ",
-		" ул ":     " улица ",
-		" ш ":      " шоссе ",
-		" тер ":    " территория ",
-		" обл ":    " область ",
-	}
+		if full, ok := abbreviations[key]; ok {
+			word = full
+		}
 
-	leftComma := func(abbrev string) string {
-		return "," + abbrev[1:]
-	}
-	rightComma := func(abbrev string) string {
-		return abbrev[:len(abbrev)-1] + ","
-	}
-	rightDot := func(abbrev string) string {
-		return abbrev[:len(abbrev)-1] + "."
+		result = append(result, word)
 	}
 
-	for abbrev, full := range replacements {
-		url = strings.ReplaceAll(url, abbrev, full)
-		url = strings.ReplaceAll(url, leftComma(abbrev), leftComma(full))
-		url = strings.ReplaceAll(url, rightComma(abbrev), rightComma(full))
-		url = strings.ReplaceAll(url, rightDot(abbrev), full[:len(full)-1])
-	}
-
-	// comment because Н.Э.Баумана exists
-	//url = strings.ReplaceAll(url, ".", "")
-
-	return url
+	return result, ""
 }
 
+// ReformatURLOpenMap normalizes address, for example
+// "г Москва, ул Воротынская, д 9 к 1" becomes "Москва, улица Воротынская, 9".
 func ReformatURLOpenMap(url string) string {
-	url = replaceCityURLOpenMap(url)
-	url = replaceHomeURLOpenMap(url)
-	url = replaceAnotherURLOpenMap(url)
+	parts := make([]string, 0)
+
+	for _, rawPart := range strings.Split(url, ",") {
+		words := strings.Fields(rawPart)
+		if len(words) == 0 {
+			continue
+		}
+
+		if _, ok := cityPrefixes[abbreviationKey(words[0])]; ok && len(words) > 1 {
+			parts = parts[:0]
+			words = words[1:]
+		}
+
+		words, house := normalizePart(words)
+		if len(words) != 0 {
+			parts = append(parts, strings.Join(words, " "))
+		}
+
+		if house != "" {
+			parts = append(parts, house)
+			break
+		}
+	}
 
-	return url
+	return strings.Join(parts, ", ")
 }
@@ -1,9 +1,12 @@
 package reformat_url_open_map_test
 
 import (
+	"strings"
+	"testing"
+
 	"github.com/TheVovchenskiy/sportify-backend/pkg/reformat_url_open_map"
+
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func TestReformatURLOpenMap(t *testing.T) {
@@ -16,6 +19,11 @@ func TestReformatURLOpenMap(t *testing.T) {
 		"г Москва, ул Воротынская, д 9 к 1":       "Москва, улица Воротынская, 9",
 		"г Москва, Госпитальный пер, д 4-6 стр 3": "Москва, Госпитальный переулок, 4-6",
 		"Московская обл, г Клин, деревня Кононово, тер. СНТ Аллея Перова МГТУ им Н.Э.Баумана": "Клин, деревня Кононово, территория СНТ Аллея Перова МГТУ им Н.Э.Баумана",
+		"г. Москва,  ул.  Бауманская , д. 5": "Москва, улица Бауманская, 5",
+		"Москва, ул Бауманская д 5 корп 2":   "Москва, улица Бауманская, 5",
+		"Москва, Ленинский пр-кт, 32":        "Москва, Ленинский проспект, 32",
+		"Москва, Д Кононово":                 "Москва, Д Кононово",
+		"":                                   "",
 	}
 
 	for input, want := range testCases {
@@ -27,3 +35,58 @@ func TestReformatURLOpenMap(t *testing.T) {
 		})
 	}
 }
+
+func TestReformatURLOpenMapAbbreviations(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"ал":     "аллея",
+		"б-р":    "бульвар",
+		"бул":    "бульвар",
+		"взв":    "взвоз",
+		"взд":    "въезд",
+		"дор":    "дорога",
+		"ззд":    "заезд",
+		"км":     "километр",
+		"к-цо":   "кольцо",
+		"лн":     "линия",
+		"мгстр":  "магистраль",
+		"наб":    "набережная",
+		"пер-д":  "переезд",
+		"пер":    "переулок",
+		"пл-ка":  "площадка",
+		"пл":     "площадь",
+		"пр-д":   "проезд",
+		"пр-кт":  "проспект",
+		"просп":  "проспект",
+		"пр-ка":  "просека",
+		"пр-к":   "просек",
+		"пр-лок": "проселок",
+		"проул":  "проулок",
+		"рзд":    "разъезд",
+		"с-р":    "сквер",
+		"с-к":    "спуск",
+		"сзд":    "съезд",
+		"туп":    "тупик",
+		"ул":     "улица",
+		"ш":      "шоссе",
+		"тер":    "территория",
+		"обл":    "область",
+		"мкр":    "микрорайон",
+		"пос":    "поселок",
+	}
+
+	for abbreviation, full := range testCases {
+		t.Run(abbreviation, func(t *testing.T) {
+			t.Parallel()
+
+			// abbreviation is expanded before and after name, with dot and in upper case
+			assert.Equal(t, "Москва, "+full+" Садовая",
+				reformat_url_open_map.ReformatURLOpenMap("г Москва, "+abbreviation+" Садовая"))
+			assert.Equal(t, "Москва, Садовая "+full+", 1",
+				reformat_url_open_map.ReformatURLOpenMap("г Москва, Садовая "+abbreviation+"., д 1"))
+			assert.Equal(t, "Москва, "+full+" Садовая",
+				reformat_url_open_map.ReformatURLOpenMap("г Москва, "+strings.ToUpper(abbreviation)+" Садовая"))
+		})
+	}
+}
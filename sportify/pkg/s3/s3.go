@@ -0,0 +1,174 @@
+// Package s3 is small client of S3-compatible object storages (AWS S3, MinIO, Yandex Object Storage)
+// with signature version 4. It has only requests needed to keep files of app.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrNoEndpoint = errors.New("endpoint of s3 is empty")
+	ErrNoBucket   = errors.New("bucket of s3 is empty")
+)
+
+// MaxPresignExpires is limit of signature version 4.
+const MaxPresignExpires = 7 * 24 * time.Hour
+
+type Config struct {
+	// Endpoint is url of storage like https://storage.yandexcloud.net or http://localhost:9000.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle puts bucket into path instead of host, MinIO needs it.
+	PathStyle bool
+}
+
+type Client struct {
+	cfg        Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func New(cfg Config, httpClient *http.Client) (*Client, error) {
+	if cfg.Endpoint == "" {
+		return nil, ErrNoEndpoint
+	}
+
+	if cfg.Bucket == "" {
+		return nil, ErrNoBucket
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("to parse endpoint: %w", err)
+	}
+
+	return &Client{cfg: cfg, endpoint: endpoint, httpClient: httpClient, now: time.Now}, nil
+}
+
+func strconvSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(duration.Seconds()))
+}
+
+func (c *Client) objectURL(key string) *url.URL {
+	result := *c.endpoint
+	key = strings.TrimPrefix(key, "/")
+
+	if c.cfg.PathStyle {
+		result.Path = strings.TrimSuffix(result.Path, "/") + "/" + c.cfg.Bucket + "/" + key
+	} else {
+		result.Host = c.cfg.Bucket + "." + result.Host
+		result.Path = strings.TrimSuffix(result.Path, "/") + "/" + key
+	}
+
+	result.RawPath = escape(result.Path, true)
+
+	return &result
+}
+
+func (c *Client) do(ctx context.Context, method, key string, body []byte, contentType string) (*http.Response, error) {
+	objectURL := c.objectURL(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("to new request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	c.sign(req, hashHex(body), c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("to do request: %w", err)
+	}
+
+	return resp, nil
+}
+
+func responseError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	// body of error is small xml with code and message of storage
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:mnd
+
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func (c *Client) PutObject(ctx context.Context, key string, content []byte, contentType string) error {
+	resp, err := c.do(ctx, http.MethodPut, key, content, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("to put object: %w", responseError(resp))
+	}
+
+	return nil
+}
+
+func (c *Client) GetObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("to get object: %w", responseError(resp))
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("to read object: %w", err)
+	}
+
+	return content, nil
+}
+
+// ObjectExists checks object by HEAD request without downloading it.
+func (c *Client) ObjectExists(ctx context.Context, key string) (bool, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, nil, "")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("to head object: %w", responseError(resp))
+	}
+}
+
+// PresignGetObject makes url for reading of object from private bucket. Time of signature is rounded
+// down to half of expires, so url is the same inside of this window and can be cached by browsers.
+// Url is valid until returned time, it is at least half of expires from now.
+func (c *Client) PresignGetObject(key string, expires time.Duration) (string, time.Time) {
+	expires = min(expires, MaxPresignExpires)
+	signedAt := c.now().Truncate(max(expires/2, time.Second)) //nolint:mnd
+
+	return c.presign(http.MethodGet, c.objectURL(key), expires, signedAt), signedAt.Add(expires)
+}
@@ -0,0 +1,168 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm   = "AWS4-HMAC-SHA256"
+	signService     = "s3"
+	signTerminator  = "aws4_request"
+	amzDateLayout   = "20060102T150405Z"
+	scopeDateLayout = "20060102"
+
+	// unsignedPayload is used in presigned urls, body of GET is empty and not known to signer.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+func hashHex(content []byte) string {
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// escape encodes by RFC 3986 like signature version 4 requires, url.QueryEscape writes space as '+'.
+func escape(value string, keepSlash bool) string {
+	var builder strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', keepSlash && b == '/':
+			builder.WriteByte(b)
+		default:
+			builder.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{b})))
+		}
+	}
+
+	return builder.String()
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, escape(key, false)+"="+escape(value, false))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// canonicalHeaders returns signed headers and their canonical form, host is always signed.
+func canonicalHeaders(req *http.Request) (string, string) {
+	headers := map[string]string{"host": req.URL.Host}
+
+	for key, values := range req.Header {
+		lowerKey := strings.ToLower(key)
+		if lowerKey == "host" || lowerKey == "x-amz-content-sha256" || lowerKey == "x-amz-date" ||
+			lowerKey == "content-type" || strings.HasPrefix(lowerKey, "x-amz-meta-") {
+			headers[lowerKey] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		builder.WriteString(key + ":" + headers[key] + "\n")
+	}
+
+	return strings.Join(keys, ";"), builder.String()
+}
+
+func (c *Client) scope(now time.Time) string {
+	return strings.Join([]string{now.Format(scopeDateLayout), c.cfg.Region, signService, signTerminator}, "/")
+}
+
+func (c *Client) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		now.Format(amzDateLayout),
+		c.scope(now),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretKey), now.Format(scopeDateLayout))
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, signService)
+	key = hmacSHA256(key, signTerminator)
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalRequest(method string, u *url.URL, query, headers, signedHeaders, payloadHash string) string {
+	return strings.Join([]string{
+		method,
+		escape(u.Path, true),
+		query,
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+}
+
+// sign adds Authorization header of signature version 4 to request.
+func (c *Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+
+	req.Header.Set("X-Amz-Date", now.Format(amzDateLayout))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders, headers := canonicalHeaders(req)
+	request := canonicalRequest(req.Method, req.URL, canonicalQuery(req.URL.Query()), headers, signedHeaders, payloadHash)
+
+	req.Header.Set("Authorization", signAlgorithm+
+		" Credential="+c.cfg.AccessKey+"/"+c.scope(now)+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+c.signature(now, request))
+}
+
+// presign makes url with signature in query, it can be opened by anyone until it expires.
+func (c *Client) presign(method string, u *url.URL, expires time.Duration, now time.Time) string {
+	now = now.UTC()
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", signAlgorithm)
+	query.Set("X-Amz-Credential", c.cfg.AccessKey+"/"+c.scope(now))
+	query.Set("X-Amz-Date", now.Format(amzDateLayout))
+	query.Set("X-Amz-Expires", strconvSeconds(expires))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	request := canonicalRequest(method, u, canonicalQuery(query), "host:"+u.Host+"\n", "host", unsignedPayload)
+
+	result := *u
+	result.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + c.signature(now, request)
+
+	return result.String()
+}
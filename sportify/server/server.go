@@ -11,10 +11,14 @@ import (
 	"github.com/TheVovchenskiy/sportify-backend/app"
 	"github.com/TheVovchenskiy/sportify-backend/app/botapi"
 	"github.com/TheVovchenskiy/sportify-backend/app/config"
+	"github.com/TheVovchenskiy/sportify-backend/app/geocoder"
 	"github.com/TheVovchenskiy/sportify-backend/app/telegramapi"
+	"github.com/TheVovchenskiy/sportify-backend/app/yookassa"
 	"github.com/TheVovchenskiy/sportify-backend/db"
+	"github.com/TheVovchenskiy/sportify-backend/models"
 	sportifymiddleware "github.com/TheVovchenskiy/sportify-backend/pkg/middleware"
 	"github.com/TheVovchenskiy/sportify-backend/pkg/mylogger"
+	"github.com/TheVovchenskiy/sportify-backend/pkg/s3"
 
 	chi "github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -23,10 +27,13 @@ import (
 	authmiddleware "github.com/go-pkgz/auth/middleware"
 	"github.com/go-pkgz/auth/provider"
 	"github.com/go-pkgz/auth/token"
+	"github.com/google/uuid"
 )
 
 const basicTimeout = 10 * time.Second
 
+var errUnknownFileStorage = errors.New("unknown type of file storage")
+
 func (s *Server) runTgHandler(ctx context.Context, cfg *config.Config, handler api.Handler, logger *mylogger.MyLogger) error {
 	r := chi.NewRouter()
 	r.Route(cfg.App.APIPrefix, func(r chi.Router) {
@@ -57,6 +64,114 @@ func (s *Server) runTgHandler(ctx context.Context, cfg *config.Config, handler a
 	return nil
 }
 
+// newFileStorage returns storage of files and handler of route with files.
+// Links to files are saved in db, so with s3 old links are redirected to bucket.
+func newFileStorage(cfg *config.Config) (app.FileStorage, http.Handler, error) {
+	prefix := cfg.App.APIPrefix + "img/"
+
+	switch cfg.FileStorage.Type {
+	case config.FileStorageTypeFS:
+		fsStorage, err := db.NewFileSystemStorage(cfg.App.PathPhotos)
+		if err != nil {
+			return nil, nil, fmt.Errorf("to new fs storage: %w", err)
+		}
+
+		return fsStorage, http.StripPrefix(prefix, http.FileServer(http.Dir(cfg.App.PathPhotos))), nil
+	case config.FileStorageTypeS3:
+		s3Storage, err := NewS3Storage(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		handler := http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			url, maxAge := s3Storage.FileURL(r.URL.Path)
+
+			w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+			http.Redirect(w, r, url, http.StatusFound)
+		}))
+
+		return s3Storage, handler, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", errUnknownFileStorage, cfg.FileStorage.Type)
+	}
+}
+
+// NewS3Storage is used also by command which moves files from disk to bucket.
+func NewS3Storage(cfg *config.Config) (*db.S3Storage, error) {
+	s3Storage, err := db.NewS3Storage(
+		s3.Config{
+			Endpoint:  cfg.FileStorage.S3.Endpoint,
+			Region:    cfg.FileStorage.S3.Region,
+			Bucket:    cfg.FileStorage.S3.Bucket,
+			AccessKey: cfg.FileStorage.S3.AccessKey,
+			SecretKey: cfg.FileStorage.S3.SecretKey,
+			PathStyle: cfg.FileStorage.S3.PathStyle,
+		},
+		cfg.FileStorage.S3.PublicURL, cfg.FileStorage.S3.PresignExpires,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("to new s3 storage: %w", err)
+	}
+
+	return s3Storage, nil
+}
+
+// newYookassaClient returns nil when shop is not configured, payments are turned off then.
+func newYookassaClient(cfg *config.Config) app.YookassaClient {
+	if cfg.App.Yookassa.ShopID == "" {
+		return nil
+	}
+
+	return yookassa.NewClient(cfg.App.Yookassa.ShopID, cfg.App.Yookassa.AgentID,
+		cfg.App.Yookassa.TokenPayment, cfg.App.Yookassa.TokenPayout)
+}
+
+// newGeocoder asks static file first, then Nominatim and Yandex as fallback.
+// All answers are cached in postgres, so providers are asked once per address.
+func newGeocoder(cfg *config.Config, storage geocoder.CacheStorage, logger *mylogger.MyLogger) (*geocoder.Cached, error) {
+	var providers []geocoder.Geocoder
+
+	if cfg.Geocoder.StaticFile != "" {
+		static, err := geocoder.NewStatic(cfg.Geocoder.StaticFile)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, static)
+	}
+
+	providers = append(providers,
+		geocoder.NewNominatim(http.DefaultClient, cfg.Geocoder.UserAgent, cfg.Geocoder.NominatimRPS),
+		geocoder.NewYandex(http.DefaultClient, cfg.App.IAMToken, cfg.Geocoder.YandexRPS),
+	)
+
+	return geocoder.NewCached(
+		geocoder.NewChain(providers...),
+		storage,
+		cfg.Geocoder.CacheTTL,
+		cfg.Geocoder.NegativeCacheTTL,
+		logger,
+	), nil
+}
+
+func newAuditSettings(cfg *config.Config) (models.AuditSettings, error) {
+	settings := models.AuditSettings{
+		Retention:    cfg.Audit.Retention,
+		ModeratorIDs: make([]uuid.UUID, 0, len(cfg.Audit.ModeratorIDs)),
+	}
+
+	for _, rawID := range cfg.Audit.ModeratorIDs {
+		moderatorID, err := uuid.Parse(rawID)
+		if err != nil {
+			return models.AuditSettings{}, fmt.Errorf("to parse moderator id %q: %w", rawID, err)
+		}
+
+		settings.ModeratorIDs = append(settings.ModeratorIDs, moderatorID)
+	}
+
+	return settings, nil
+}
+
 type Server struct {
 	serverPublic http.Server
 	serverTg     http.Server
@@ -88,14 +203,14 @@ func (s *Server) Run(ctx context.Context, configFile []string) error {
 
 	logger.Debugf("Config: %v", cfg)
 
-	postgresStorage, _, err := db.NewPostgresStorage(ctx, cfg.Postgres.URL)
+	postgresStorage, pool, err := db.NewPostgresStorage(ctx, cfg.Postgres.URL)
 	if err != nil {
 		return err
 	}
 
-	fsStorage, err := db.NewFileSystemStorage(cfg.App.PathPhotos)
+	fileStorage, fileHandler, err := newFileStorage(cfg)
 	if err != nil {
-		return fmt.Errorf("to new fs storage: %w", err)
+		return fmt.Errorf("to new file storage: %w", err)
 	}
 
 	mapTokenStorage := db.NewMapTokenStorage(time.Minute * 30)
@@ -105,10 +220,45 @@ func (s *Server) Run(ctx context.Context, configFile []string) error {
 		return fmt.Errorf("to new bot api: %w", err)
 	}
 
+	appGeocoder, err := newGeocoder(cfg, postgresStorage, logger)
+	if err != nil {
+		return fmt.Errorf("to new geocoder: %w", err)
+	}
+
+	auditSettings, err := newAuditSettings(cfg)
+	if err != nil {
+		return fmt.Errorf("to new audit settings: %w", err)
+	}
+
 	url := cfg.App.Domain + cfg.App.Port
-	appSportify := app.NewApp(
-		cfg.App.IAMToken, cfg.App.URLPrefixFile, fsStorage, postgresStorage, postgresStorage, mapTokenStorage, logger, botAPI,
-	)
+	appSportify := app.NewApp(app.Deps{
+		URLPrefixFile:         cfg.App.URLPrefixFile,
+		FileStorage:           fileStorage,
+		EventStorage:          postgresStorage,
+		AuthStorage:           postgresStorage,
+		ClubStorage:           postgresStorage,
+		VenueStorage:          postgresStorage,
+		CoordinatesJobStorage: postgresStorage,
+		MapStorage:            postgresStorage,
+		FacetStorage:          postgresStorage,
+		SavedSearchStorage:    postgresStorage,
+		RecommendationStorage: postgresStorage,
+		NotificationStorage:   postgresStorage,
+		ReminderStorage:       postgresStorage,
+		AuditStorage:          postgresStorage,
+		CommentStorage:        postgresStorage,
+		EventStreamStorage:    postgresStorage,
+		CalendarStorage:       postgresStorage,
+		TokenStorage:          mapTokenStorage,
+		Geocoder:              appGeocoder,
+		GeocodeTimeout:        cfg.Geocoder.SyncTimeout,
+		RecommendationWeights: models.RecommendationWeights(cfg.Recommendations),
+		AuditSettings:         auditSettings,
+		Logger:                logger,
+		BotAPI:                botAPI,
+		PaymentPayoutStorage:  db.NewPostgresPaymentPayoutStorage(pool),
+		YookassaClient:        newYookassaClient(cfg),
+	})
 
 	tgAPI := telegramapi.NewTelegramAPIDummy()
 	handler := api.NewHandler(
@@ -129,24 +279,68 @@ func (s *Server) Run(ctx context.Context, configFile []string) error {
 
 	r := chi.NewRouter()
 	r.Route(cfg.App.APIPrefix, func(r chi.Router) {
-		r.Use(middleware.Logger)
+		r.Use(sportifymiddleware.Logger)
 		r.Use(middleware.Recoverer)
 		r.Use(middleware.RequestID)
 		r.Use(sportifymiddleware.Config)
 		r.Use(sportifymiddleware.ConvertErrUnknownToOurType)
 		r.Get("/healthcheck", handler.Healthcheck)
-		r.Get("/events", handler.FindEvents)
+		r.With(authMiddleware.Trace).Get("/events", handler.FindEvents)
+		r.With(authMiddleware.Trace).Get("/events/map", handler.FindMapEvents)
+		r.Get("/events/feed.{format}", handler.FindEventsFeed)
+		r.With(authMiddleware.Trace).Get("/events/facets", handler.FindEventFacets)
+		r.With(authMiddleware.Auth).Get("/events/recommended", handler.RecommendEvents)
 		r.Get("/event/{id}", handler.GetEvent)
 		r.Get("/profiles/{id}", handler.GetProfile)
 		r.With(authMiddleware.Auth).Put("/event/{id}", handler.EditEventSite)
 		r.With(authMiddleware.Auth).Delete("/event/{id}", handler.DeleteEvent)
+		r.With(authMiddleware.Auth).Delete("/admin/event/{id}", handler.HardDeleteEvent)
 		r.With(authMiddleware.Auth).Put("/event/sub/{id}", handler.SubscribeEvent)
+		r.With(authMiddleware.Auth).Put("/event/{id}/reminder", handler.SetEventReminder)
+		r.With(authMiddleware.Auth).Put("/event/{id}/status", handler.ChangeEventStatus)
+		r.With(authMiddleware.Auth).Get("/event/{id}/history", handler.GetEventHistory)
+		r.Get("/event/{id}/stream", handler.StreamEvent)
+		r.Get("/event/{id}.ics", handler.GetEventCalendar)
+		r.Get("/share/events/{id}", handler.ShareEvent)
+		r.Get("/share/events/{id}/card.png", handler.GetEventShareCard)
+		r.Get("/users/{id}/calendar.ics", handler.GetUserCalendar)
+		r.With(authMiddleware.Auth).Get("/calendar", handler.GetCalendarFeed)
+		r.With(authMiddleware.Auth).Post("/calendar/reset", handler.ResetCalendarFeed)
+		r.Get("/event/{id}/comments", handler.GetEventComments)
+		r.With(authMiddleware.Auth).Post("/event/{id}/comments", handler.CreateComment)
+		r.With(authMiddleware.Auth).Put("/event/{id}/comments/settings", handler.SetCommentSettings)
+		r.With(authMiddleware.Auth).Put("/comments/{id}", handler.EditComment)
+		r.With(authMiddleware.Auth).Delete("/comments/{id}", handler.DeleteComment)
 		r.With(authMiddleware.Auth).Post("/event", handler.CreateEventSite)
 		r.With(authMiddleware.Auth).Get("/users/{id}/events", handler.GetUsersEvents)
 		r.With(authMiddleware.Auth).Get("/users/{id}/sub_active/events", handler.GetUsersSubActiveEvents)
 		r.With(authMiddleware.Auth).Get("/users/{id}/sub_archive/events", handler.GetUsersSubArchiveEvents)
 		r.With(authMiddleware.Auth).Post("/upload", handler.UploadFile)
 		r.With(authMiddleware.Auth).Put("/profiles/{user_id}", handler.UpdateProfile)
+		r.With(authMiddleware.Auth).Post("/saved_searches", handler.CreateSavedSearch)
+		r.With(authMiddleware.Auth).Get("/saved_searches", handler.GetSavedSearches)
+		r.With(authMiddleware.Auth).Get("/saved_searches/alerts", handler.GetSavedSearchAlerts)
+		r.With(authMiddleware.Auth).Delete("/saved_searches/{id}", handler.DeleteSavedSearch)
+		r.With(authMiddleware.Auth).Get("/notifications", handler.GetNotifications)
+		r.With(authMiddleware.Auth).Post("/notifications/read", handler.MarkNotificationsRead)
+		r.With(authMiddleware.Auth).Get("/notifications/preferences", handler.GetNotificationPreferences)
+		r.With(authMiddleware.Auth).Put("/notifications/preferences", handler.SetNotificationPreferences)
+		r.With(authMiddleware.Auth).Get("/notifications/reminder", handler.GetUserReminder)
+		r.With(authMiddleware.Auth).Put("/notifications/reminder", handler.SetUserReminder)
+		r.Get("/clubs", handler.FindClubs)
+		r.Get("/clubs/{id}", handler.GetClubPage)
+		r.With(authMiddleware.Auth).Post("/clubs", handler.CreateClub)
+		r.With(authMiddleware.Auth).Put("/clubs/{id}", handler.EditClub)
+		r.With(authMiddleware.Auth).Post("/clubs/{id}/avatar", handler.UploadClubAvatar)
+		r.With(authMiddleware.Auth).Post("/clubs/{id}/tg_link_code", handler.CreateClubTgLinkCode)
+		r.With(authMiddleware.Auth).Post("/clubs/{id}/members", handler.JoinClub)
+		r.With(authMiddleware.Auth).Put("/clubs/{id}/members/{user_id}", handler.SetClubMemberRole)
+		r.With(authMiddleware.Auth).Delete("/clubs/{id}/members/{user_id}", handler.RemoveClubMember)
+		r.Get("/geo/reverse", handler.ReverseGeocode)
+		r.Get("/venues", handler.SuggestVenues)
+		r.Get("/venues/{id}", handler.GetVenuePage)
+		r.With(authMiddleware.Auth).Post("/venues", handler.CreateVenue)
+		r.With(authMiddleware.Auth).Put("/venues/{id}", handler.EditVenue)
 
 		r.Mount("/auth",
 			sportifymiddleware.ConvertLoginResponseToCheck(
@@ -161,9 +355,7 @@ func (s *Server) Run(ctx context.Context, configFile []string) error {
 				return
 			}
 
-			fs := http.StripPrefix(cfg.App.APIPrefix+"img/", http.FileServer(http.Dir(cfg.App.PathPhotos)))
-
-			fs.ServeHTTP(w, r)
+			fileHandler.ServeHTTP(w, r)
 		})
 	})
 
@@ -180,6 +372,8 @@ func (s *Server) Run(ctx context.Context, configFile []string) error {
 		ReadTimeout:                  basicTimeout,
 		WriteTimeout:                 basicTimeout,
 	}
+	// shutdown waits for active connections, streams are never idle
+	s.serverPublic.RegisterOnShutdown(handler.CloseStreams)
 
 	logger.Infof("listen %s\n", cfg.App.Port)
 	if err := s.serverPublic.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {